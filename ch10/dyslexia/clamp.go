@@ -0,0 +1,85 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "fmt"
+
+// UnitClamp clamps one unit to a fixed activation during settling.
+type UnitClamp struct {
+
+	// name of the layer
+	Layer string
+
+	// index of the unit within the layer
+	Index int `min:"0"`
+
+	// activation value to clamp the unit to
+	Value float32 `min:"0" max:"1" default:"1"`
+}
+
+// String returns the unit and value, as Layer[index]=value.
+func (uc *UnitClamp) String() string {
+	return fmt.Sprintf("%s[%d]=%g", uc.Layer, uc.Index, uc.Value)
+}
+
+// ClampUnit clamps the unit at given index in given layer to given
+// activation value during settling on every trial, until cleared with
+// ClearClamps, to test what happens downstream when that unit is active.
+// The activation is set directly on every cycle, after the network has
+// updated, so the clamp works the same in any layer, including hidden layers,
+// and its downstream effects come through the activity the unit sends.
+func (ss *Sim) ClampUnit(layer string, idx int, value float32) error { //types:add
+	ly := ss.Net.LayerByName(layer)
+	if ly == nil {
+		return fmt.Errorf("ClampUnit: layer %q not found", layer)
+	}
+	if idx < 0 || idx >= len(ly.Neurons) {
+		return fmt.Errorf("ClampUnit: unit index %d out of range for layer %s with %d units", idx, layer, len(ly.Neurons))
+	}
+	for i := range ss.Clamps {
+		uc := &ss.Clamps[i]
+		if uc.Layer == layer && uc.Index == idx {
+			uc.Value = value
+			return nil
+		}
+	}
+	ss.Clamps = append(ss.Clamps, UnitClamp{Layer: layer, Index: idx, Value: value})
+	return nil
+}
+
+// ClampSelectedUnit clamps the unit last selected in the NetView
+// (by clicking on it, as for viewing its weights) to given activation value,
+// using ClampUnit.
+func (ss *Sim) ClampSelectedUnit(value float32) error { //types:add
+	nv := ss.ViewUpdate.View
+	if nv == nil || nv.Data.PathLay == "" || nv.Data.PathUnIndex < 0 {
+		return fmt.Errorf("ClampSelectedUnit: first click on a unit in the Network view to select it")
+	}
+	return ss.ClampUnit(nv.Data.PathLay, nv.Data.PathUnIndex, value)
+}
+
+// ClearClamps removes all of the unit clamps.
+func (ss *Sim) ClearClamps() { //types:add
+	ss.Clamps = nil
+}
+
+// ApplyClamps sets the activation of each of the clamped units to its
+// value, which is called on every cycle after the network Cycle update,
+// so that the clamped activity is sent to the other units on the next
+// cycle and recorded in the ActM and ActP phase values.  Clamps on units
+// that do not exist are ignored.
+func (ss *Sim) ApplyClamps() {
+	for _, uc := range ss.Clamps {
+		ly := ss.Net.LayerByName(uc.Layer)
+		if ly == nil || uc.Index < 0 || uc.Index >= len(ly.Neurons) {
+			continue
+		}
+		nrn := &ly.Neurons[uc.Index]
+		if nrn.IsOff() {
+			continue
+		}
+		nrn.Act = uc.Value
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"github.com/compcogneuro/sims/v2/simtest"
+)
+
+// newTestSim returns a configured Sim with the trained weights,
+// without the GUI.
+func newTestSim(t *testing.T) *Sim {
+	t.Helper()
+	ss := &Sim{}
+	simtest.Config(t, ss)
+	ss.Init()
+	if err := ss.Net.OpenWeightsFS(content, "trained.wts"); err != nil {
+		t.Fatal(err)
+	}
+	return ss
+}
+
+// TestClampHiddenUnit checks that clamping a unit in a hidden layer
+// sets its activity, in both phases, to the clamped value.
+func TestClampHiddenUnit(t *testing.T) {
+	ss := newTestSim(t)
+	ly := ss.Net.LayerByName("OShidden")
+	idx := 0
+	ss.TestAll()
+	act := ly.Neurons[idx].ActM
+	val := float32(1)
+	if act > 0.5 {
+		val = 0
+	}
+	if err := ss.ClampUnit(ly.Name, idx, val); err != nil {
+		t.Fatal(err)
+	}
+	ss.TestAll()
+	nrn := &ly.Neurons[idx]
+	if math32.Abs(nrn.ActM-val) > 1.0e-6 || math32.Abs(nrn.ActP-val) > 1.0e-6 {
+		t.Errorf("clamped %s[%d] to %g: ActM = %g, ActP = %g, unclamped ActM = %g", ly.Name, idx, val, nrn.ActM, nrn.ActP, act)
+	}
+
+	ss.ClearClamps()
+	ss.TestAll()
+	if ly.Neurons[idx].ActM != act {
+		t.Errorf("after ClearClamps, %s[%d] ActM = %g, want the unclamped %g", ly.Name, idx, ly.Neurons[idx].ActM, act)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// DevelopmentalDyslexia simulates developmental dyslexia as incomplete
+// learning, instead of a lesion of the fully trained network: it trains
+// a new, intact network for just Config.DevNEpochs epochs, and then tests
+// all the items, reporting the distribution of error types.  The test is
+// added to the Test Epoch plot, labeled as Dev and the number of epochs,
+// along with any previous tests, e.g., of acquired lesions, for comparison.
+// This replaces the current weights: use Open Trained Wts to restore the
+// fully trained network.
+func (ss *Sim) DevelopmentalDyslexia() { //types:add
+	if ss.GUI.Body == nil {
+		fmt.Println(ss.DevelopmentalTest())
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		sum := ss.DevelopmentalTest()
+		ss.GUI.Stopped()
+		ss.GUI.Body.AsyncLock()
+		core.MessageDialog(ss.GUI.Body, sum, "Developmental Dyslexia")
+		ss.GUI.Body.AsyncUnlock()
+	}()
+}
+
+// DevelopmentalTest trains one run for Config.DevNEpochs epochs, tests it,
+// and returns a summary of the error types.  The previous Test Epoch log,
+// which is reset by the new run, is kept before the new test.
+func (ss *Sim) DevelopmentalTest() string {
+	prev := ss.Logs.Table(etime.Test, etime.Epoch).Clone()
+	ss.UnLesionNet(ss.Net)
+	ss.Lesion = NoLesion
+	ss.LesionProp = 0
+	ss.Init()
+	ss.Loops.Loop(etime.Train, etime.Run).Counter.Max = 1
+	ss.Loops.Loop(etime.Train, etime.Epoch).Counter.Max = ss.Config.DevNEpochs
+	ss.Loops.Run(etime.Train)
+	ss.ApplyParams() // restores the counters
+	ss.DevEpochs = ss.Logs.Table(etime.Train, etime.Epoch).Rows
+	ss.TestAll()
+
+	tst := ss.Logs.Table(etime.Test, etime.Epoch)
+	dev := tst.Clone()
+	tst.SetNumRows(0)
+	appendRows(tst, prev)
+	appendRows(tst, dev)
+	ss.GUI.GoUpdatePlot(etime.Test, etime.Epoch)
+	if dev.Rows == 0 {
+		return "Developmental: the test did not run"
+	}
+	return errorProfile(dev, dev.Rows-1)
+}
+
+// errorProfile returns a summary of the error types in the given row
+// of the Test Epoch log, as the number of concrete and abstract errors
+// of each type, and the proportion of all the errors.
+func errorProfile(dt *table.Table, row int) string {
+	cols := []string{"Vis", "Sem", "VisSem", "Blend", "Other"}
+	total := 0.0
+	for _, cl := range cols {
+		total += dt.Float("Con"+cl, row) + dt.Float("Abs"+cl, row)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: PctErr: %.4g\n", dt.StringValue("Lesion", row), dt.Float("PctErr", row))
+	fmt.Fprintf(&b, "Type\tConcrete\tAbstract\tProportion\n")
+	for _, cl := range cols {
+		con, abs := dt.Float("Con"+cl, row), dt.Float("Abs"+cl, row)
+		prop := 0.0
+		if total > 0 {
+			prop = (con + abs) / total
+		}
+		fmt.Fprintf(&b, "%s\t%g\t%g\t%.2f\n", cl, con, abs, prop)
+	}
+	return b.String()
+}
+
+// appendRows appends the rows of src to dt, which must have the same
+// scalar columns, as in a copy of the same log.
+func appendRows(dt, src *table.Table) {
+	st := dt.Rows
+	dt.SetNumRows(st + src.Rows)
+	for ci, cl := range dt.Columns {
+		sc := src.Columns[ci]
+		for r := range src.Rows {
+			if cl.IsString() {
+				cl.SetString1D(st+r, sc.String1D(r))
+			} else {
+				cl.SetFloat1D(st+r, sc.Float1D(r))
+			}
+		}
+	}
+}
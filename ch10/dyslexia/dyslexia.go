@@ -6,20 +6,25 @@
 // of a distributed representation of word-level knowledge across Orthography, Semantics,
 // and Phonology. It is based on a model by Plaut and Shallice (1993).
 // Note that this form of dyslexia is *acquired* (via brain lesions such as stroke)
-// and not the more prevalent developmental variety.
+// and not the more prevalent developmental variety, which can be compared with it
+// by stopping training early, with DevelopmentalDyslexia.
 package main
 
 //go:generate core generate -add-types
 
 import (
 	"embed"
+	"fmt"
 	"math/rand"
 	"reflect"
+	"slices"
 	"strings"
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/events/key"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/math32/minmax"
 	"cogentcore.org/core/tree"
@@ -35,6 +40,7 @@ import (
 	"github.com/emer/emergent/v2/netview"
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/etensor/plot/plotcore"
 	"github.com/emer/etensor/tensor"
 	"github.com/emer/etensor/tensor/stats/clust"
 	"github.com/emer/etensor/tensor/stats/metric"
@@ -137,6 +143,10 @@ type Config struct {
 	// how often to run through all the test patterns, in terms of training epochs.
 	// can use 0 or -1 for no testing.
 	TestInterval int `default:"-1"`
+
+	// number of epochs to train for in a DevelopmentalDyslexia run,
+	// which stops training early, before the words are fully learned.
+	DevNEpochs int `default:"30" min:"1"`
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -151,6 +161,25 @@ type Sim struct {
 	// proportion of neurons lesioned -- use Lesion button to lesion
 	LesionProp float32 `edit:"-"`
 
+	// individual units lesioned, as Layer[index] -- use Lesion Unit button to lesion
+	UnitLesions []string `edit:"-"`
+
+	// number of epochs the network was trained for in a DevelopmentalDyslexia
+	// run, or 0 for the fully trained network -- use Developmental button
+	DevEpochs int `edit:"-"`
+
+	// standard deviation of Gaussian noise added to the weights, either once
+	// with the Add Wt Noise button, or on every cycle of testing if WtNoisePerCycle
+	WtNoise float32 `min:"0" step:"0.05"`
+
+	// draw new weight noise on every cycle of testing, instead of
+	// a fixed perturbation of the weights
+	WtNoisePerCycle bool
+
+	// units clamped to a fixed activation during settling, to test their causal role
+	// -- use the Clamp Unit buttons to add
+	Clamps []UnitClamp
+
 	// Config contains misc configuration parameters for running the sim
 	Config Config `new-window:"+" display:"no-inline"`
 
@@ -195,6 +224,9 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// weights without noise, saved during a test trial with WtNoisePerCycle
+	wtNoiseBase [][]float32
 }
 
 // New creates new blank elements and initializes defaults
@@ -216,6 +248,10 @@ func (ss *Sim) New() {
 func (ss *Sim) Defaults() {
 	ss.Lesion = NoLesion
 	ss.LesionProp = 0
+	ss.UnitLesions = nil
+	ss.DevEpochs = 0
+	ss.WtNoise = 0
+	ss.WtNoisePerCycle = false
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -337,6 +373,8 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.DevEpochs = 0
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -379,10 +417,16 @@ func (ss *Sim) ConfigLoops() {
 		stack.Loops[etime.Trial].OnStart.Add("ApplyInputs", func() {
 			ss.ApplyInputs()
 		})
+		stack.Loops[etime.Cycle].OnStart.Add("Clamps", ss.ApplyClamps) // after the Cycle update
 	}
 
 	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
+	testTrial := ls.Loop(etime.Test, etime.Trial)
+	testTrial.OnStart.Add("WtNoise", ss.WtNoiseTrialStart)
+	testTrial.OnEnd.Add("WtNoise", ss.WtNoiseTrialEnd)
+	ls.Loop(etime.Test, etime.Cycle).OnEnd.Add("WtNoise", ss.WtNoiseCycle)
+
 	// Train stop early condition
 	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("NZeroStop", func() bool {
 		// This is calculated in TrialStats
@@ -534,6 +578,7 @@ func (ss *Sim) UnLesionNet(net *leabra.Network) {
 	net.LayersSetOff(false)
 	net.UnLesionNeurons()
 	net.InitActs()
+	ss.UnitLesions = nil
 }
 
 func (ss *Sim) LesionNetImpl(net *leabra.Network, les LesionTypes, prop float32) {
@@ -567,6 +612,109 @@ func (ss *Sim) LesionNetImpl(net *leabra.Network, les LesionTypes, prop float32)
 	}
 }
 
+// LesionUnit lesions (off = true) or restores (off = false) the single unit
+// at given index in given layer, which then has zero activity, to test its
+// causal contribution.  It tests all the items before and after the change,
+// and reports the effect on the percent of errors (PctErr); the results
+// are also shown in the Test Epoch plot.  This adds to any LesionNet
+// lesion, and is undone by it.
+func (ss *Sim) LesionUnit(layer string, idx int, off bool) error { //types:add
+	ly := ss.Net.LayerByName(layer)
+	if ly == nil {
+		return fmt.Errorf("LesionUnit: layer %q not found", layer)
+	}
+	if idx < 0 || idx >= len(ly.Neurons) {
+		return fmt.Errorf("LesionUnit: unit index %d out of range for layer %s with %d units", idx, layer, len(ly.Neurons))
+	}
+	if ss.GUI.Body == nil {
+		fmt.Println(ss.LesionUnitTest(ly, idx, off))
+		return nil
+	}
+	if ss.GUI.IsRunning {
+		return nil
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		sum := ss.LesionUnitTest(ly, idx, off)
+		ss.GUI.Stopped()
+		ss.GUI.Body.AsyncLock()
+		core.MessageDialog(ss.GUI.Body, sum, "Lesion Unit")
+		ss.GUI.Body.AsyncUnlock()
+	}()
+	return nil
+}
+
+// LesionSelectedUnit toggles the lesion of the unit last selected in the
+// NetView (by clicking on it, as for viewing its weights), using LesionUnit.
+// Alt+click on a unit does both at once: see ConfigLesionClick.
+func (ss *Sim) LesionSelectedUnit() error {
+	nv := ss.ViewUpdate.View
+	if nv == nil || nv.Data.PathLay == "" || nv.Data.PathUnIndex < 0 {
+		return fmt.Errorf("LesionSelectedUnit: first click on a unit in the Network view to select it")
+	}
+	ly := ss.Net.LayerByName(nv.Data.PathLay)
+	if ly == nil {
+		return fmt.Errorf("LesionSelectedUnit: layer %q not found", nv.Data.PathLay)
+	}
+	idx := nv.Data.PathUnIndex
+	off := idx < len(ly.Neurons) && !ly.Neurons[idx].IsOff()
+	return ss.LesionUnit(ly.Name, idx, off)
+}
+
+// ConfigLesionClick adds a handler to the NetView so that Alt+click
+// (Option+click on macOS) on a unit selects it and toggles its lesion,
+// as in LesionSelectedUnit.  A plain click still just selects the unit.
+// The handler is added after those of the NetView, so it is called first.
+func (ss *Sim) ConfigLesionClick(nv *netview.NetView) {
+	sw := nv.SceneWidget()
+	sw.On(events.MouseDown, func(e events.Event) {
+		if !e.HasAllModifiers(key.Alt) || ss.GUI.IsRunning {
+			return
+		}
+		ly, _, _, idx := sw.LayerUnitAtPoint(e.Pos().Sub(sw.Geom.ContentBBox.Min))
+		if ly == nil {
+			return
+		}
+		e.SetHandled()
+		nv.Data.PathLay = ly.StyleName()
+		nv.Data.PathUnIndex = idx
+		if err := ss.LesionSelectedUnit(); err != nil {
+			core.ErrorSnackbar(ss.GUI.Body, err)
+		}
+	})
+}
+
+// LesionUnitTest tests all the items before and after setting the lesion
+// of given unit, and returns a summary of the change in PctErr.
+func (ss *Sim) LesionUnitTest(ly *leabra.Layer, idx int, off bool) string {
+	ss.GUI.StopNow = false
+	before := ss.TestPctErr()
+	ly.Neurons[idx].SetFlag(off, leabra.NeurOff)
+	ss.Net.InitActs()
+	unm := fmt.Sprintf("%s[%d]", ly.Name, idx)
+	ss.UnitLesions = slices.DeleteFunc(ss.UnitLesions, func(s string) bool { return s == unm })
+	if off {
+		ss.UnitLesions = append(ss.UnitLesions, unm)
+	}
+	after := ss.TestPctErr()
+	act := "lesioned"
+	if !off {
+		act = "restored"
+	}
+	return fmt.Sprintf("%s %s: PctErr %.4g -> %.4g (%+.4g)", unm, act, before, after, after-before)
+}
+
+// TestPctErr tests all the items and returns the resulting PctErr.
+func (ss *Sim) TestPctErr() float64 {
+	ss.TestAll()
+	dt := ss.Logs.Table(etime.Test, etime.Epoch)
+	if dt.Rows == 0 {
+		return 0
+	}
+	return dt.Float("PctErr", dt.Rows-1)
+}
+
 ////////////////////////////////////////////////////////////////////////
 // 		Stats
 
@@ -651,7 +799,20 @@ func (ss *Sim) TrialStats() {
 
 // DyslexStats computes dyslexia pronunciation, semantics stats
 func (ss *Sim) DyslexStats(net *leabra.Network) {
-	ss.Stats.SetString("Lesion", ss.Lesion.String())
+	les := ss.Lesion.String()
+	if ss.DevEpochs > 0 {
+		les = fmt.Sprintf("Dev%d", ss.DevEpochs)
+		if ss.Lesion != NoLesion {
+			les += " " + ss.Lesion.String()
+		}
+	}
+	if len(ss.UnitLesions) > 0 {
+		les += " " + strings.Join(ss.UnitLesions, " ")
+	}
+	for _, uc := range ss.Clamps {
+		les += " " + uc.String()
+	}
+	ss.Stats.SetString("Lesion", les)
 	ss.Stats.SetFloat32("LesionProp", ss.LesionProp)
 	_, sse, cnm := ss.ClosestPat(net, "Phonology", "ActM", ss.Train, "Phonology", "Name")
 	ss.Stats.SetString("Phon", cnm)
@@ -767,6 +928,8 @@ func (ss *Sim) ConfigLogs() {
 		ss.Logs.SetMeta(etime.Test, etime.Epoch, "Con"+cl+":On", "+")
 		ss.Logs.SetMeta(etime.Test, etime.Epoch, "Abs"+cl+":On", "+")
 	}
+
+	ss.ConfigWtNoise(ss.Logs.MiscTable("WtNoise"))
 }
 
 func (ss *Sim) AddTestStatAggItem(statName string) {
@@ -869,6 +1032,7 @@ func (ss *Sim) ConfigGUI() {
 	ss.ViewUpdate.Config(nv, etime.GammaCycle, etime.Cycle)
 	ss.GUI.ViewUpdate = &ss.ViewUpdate
 	nv.Current()
+	ss.ConfigLesionClick(nv)
 
 	ss.GUI.AddPlots(title, &ss.Logs)
 
@@ -876,6 +1040,14 @@ func (ss *Sim) ConfigGUI() {
 
 	ss.GUI.AddMiscPlotTab("SemCluster")
 
+	stnm := "WtNoise"
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey(stnm), stnm+" Plot")
+	plt.Options.Title = "Percent Errors by Weight Noise"
+	plt.Options.XAxis = "Noise"
+	plt.SetTable(ss.Logs.MiscTable(stnm))
+	// order of params: on, fixMin, min, fixMax, max
+	plt.SetColumnOptions("PctErr", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -891,12 +1063,97 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 		},
 	})
 
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Lesion Unit",
+		Icon:    icons.Delete,
+		Tooltip: "lesion (or restore) a single unit, and report the effect on the percent of errors",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.LesionUnit)
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Lesion Selected Unit",
+		Icon:    icons.Delete,
+		Tooltip: "toggles the lesion of the unit last clicked on in the Network view, and reports the effect on the percent of errors",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if err := ss.LesionSelectedUnit(); err != nil {
+				core.ErrorSnackbar(ss.GUI.Body, err)
+			}
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Developmental",
+		Icon:    icons.PlayArrow,
+		Tooltip: "simulates developmental dyslexia by training a new network for only Config.DevNEpochs epochs, and reports its distribution of error types, which is added to the Test Epoch plot -- use Open Trained Wts to restore the fully trained network",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.DevelopmentalDyslexia()
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Clamp Unit",
+		Icon:    icons.Lock,
+		Tooltip: "clamps a single unit to a given activation during settling, to see its effects downstream",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ClampUnit)
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Clamp Selected Unit",
+		Icon:    icons.Lock,
+		Tooltip: "clamps the unit last clicked on in the Network view to a given activation during settling",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ClampSelectedUnit)
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Clear Clamps",
+		Icon:    icons.LockOpen,
+		Tooltip: "removes all of the unit clamps",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.ClearClamps()
+			ss.GUI.UpdateWindow()
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Add Wt Noise",
+		Icon:    icons.Add,
+		Tooltip: "adds a fixed perturbation of Gaussian noise with standard deviation WtNoise to all the weights -- open the trained weights again to undo",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.AddWtNoise()
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Wt Noise Sweep",
+		Icon:    icons.PlayArrow,
+		Tooltip: "tests all the items with increasing levels of weight noise, plotting the percent errors as a function of the noise in the WtNoise Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.WtNoiseSweep)
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset Wt Noise Plot",
+		Icon:    icons.Reset,
+		Tooltip: "resets the WtNoise Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.resetWtNoisePlot()
+		},
+	})
+
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Open Trained Wts",
 		Icon:    icons.Open,
 		Tooltip: "Open trained weights",
 		Active:  egui.ActiveAlways,
 		Func: func() {
 			ss.Net.OpenWeightsFS(content, "trained.wts")
+			ss.DevEpochs = 0
 			ss.GUI.ViewUpdate.View.Current()
 		},
 	})
@@ -6,8 +6,10 @@ import (
 	"cogentcore.org/core/types"
 )
 
+var _ = types.AddType(&types.Type{Name: "main.UnitClamp", IDName: "unit-clamp", Doc: "UnitClamp clamps one unit to a fixed activation during settling.", Fields: []types.Field{{Name: "Layer", Doc: "name of the layer"}, {Name: "Index", Doc: "index of the unit within the layer"}, {Name: "Value", Doc: "activation value to clamp the unit to"}}})
+
 var _ = types.AddType(&types.Type{Name: "main.LesionTypes", IDName: "lesion-types", Doc: "LesionTypes is the type of lesion"})
 
-var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}}})
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}, {Name: "DevNEpochs", Doc: "number of epochs to train for in a DevelopmentalDyslexia run,\nwhich stops training early, before the words are fully learned."}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "LesionNet", Doc: "LesionNet does lesion of network with given proportion of neurons damaged\n0 < prop < 1.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"les", "prop"}}}, Fields: []types.Field{{Name: "Lesion", Doc: "type of lesion -- use Lesion button to lesion"}, {Name: "LesionProp", Doc: "proportion of neurons lesioned -- use Lesion button to lesion"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Train", Doc: "training patterns"}, {Name: "Semantics", Doc: "properties of semnatic features"}, {Name: "CloseOrthos", Doc: "close orthography outputs"}, {Name: "CloseSems", Doc: "close semantic outputs"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "ClampUnit", Doc: "ClampUnit clamps the unit at given index in given layer to given\nactivation value during settling on every trial, until cleared with\nClearClamps, to test what happens downstream when that unit is active.\nThe activation is set directly on every cycle, after the network has\nupdated, so the clamp works the same in any layer, including hidden layers,\nand its downstream effects come through the activity the unit sends.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"layer", "idx", "value"}, Returns: []string{"error"}}, {Name: "ClampSelectedUnit", Doc: "ClampSelectedUnit clamps the unit last selected in the NetView\n(by clicking on it, as for viewing its weights) to given activation value,\nusing ClampUnit.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"value"}, Returns: []string{"error"}}, {Name: "ClearClamps", Doc: "ClearClamps removes all of the unit clamps.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "DevelopmentalDyslexia", Doc: "DevelopmentalDyslexia simulates developmental dyslexia as incomplete\nlearning, instead of a lesion of the fully trained network: it trains\na new, intact network for just Config.DevNEpochs epochs, and then tests\nall the items, reporting the distribution of error types.  The test is\nadded to the Test Epoch plot, labeled as Dev and the number of epochs,\nalong with any previous tests, e.g., of acquired lesions, for comparison.\nThis replaces the current weights: use Open Trained Wts to restore the\nfully trained network.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "LesionNet", Doc: "LesionNet does lesion of network with given proportion of neurons damaged\n0 < proportion < 1.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"les", "proportion"}}, {Name: "LesionUnit", Doc: "LesionUnit lesions (off = true) or restores (off = false) the single unit\nat given index in given layer, which then has zero activity, to test its\ncausal contribution.  It tests all the items before and after the change,\nand reports the effect on the percent of errors (PctErr); the results\nare also shown in the Test Epoch plot.  This adds to any LesionNet\nlesion, and is undone by it.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"layer", "idx", "off"}, Returns: []string{"error"}}, {Name: "AddWtNoise", Doc: "AddWtNoise adds a fixed perturbation of Gaussian noise, with standard\ndeviation WtNoise, to all of the weights in the network, to test the\nrobustness of the trained network to synaptic variability.\nThe noise is drawn from the network random number generator, so it is\nreproducible for a given run.  Open the trained weights again to undo it.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "WtNoiseSweep", Doc: "WtNoiseSweep tests all the items with weight noise at the given number\nof levels from 0 to maxNoise, starting from the current (e.g., trained)\nweights each time, and records the percent of errors (PctErr) at each\nlevel in the WtNoise plot, to show how performance degrades with the\nmagnitude of the noise.  The noise is a fixed perturbation, or drawn on\nevery cycle if WtNoisePerCycle is set.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"maxNoise", "levels"}}}, Fields: []types.Field{{Name: "Lesion", Doc: "type of lesion -- use Lesion button to lesion"}, {Name: "LesionProp", Doc: "proportion of neurons lesioned -- use Lesion button to lesion"}, {Name: "UnitLesions", Doc: "individual units lesioned, as Layer[index] -- use Lesion Unit button to lesion"}, {Name: "DevEpochs", Doc: "number of epochs the network was trained for in a DevelopmentalDyslexia\nrun, or 0 for the fully trained network -- use Developmental button"}, {Name: "WtNoise", Doc: "standard deviation of Gaussian noise added to the weights, either once\nwith the Add Wt Noise button, or on every cycle of testing if WtNoisePerCycle"}, {Name: "WtNoisePerCycle", Doc: "draw new weight noise on every cycle of testing, instead of\na fixed perturbation of the weights"}, {Name: "Clamps", Doc: "units clamped to a fixed activation during settling, to test their causal role\n-- use the Clamp Unit buttons to add"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Train", Doc: "training patterns"}, {Name: "Semantics", Doc: "properties of semnatic features"}, {Name: "CloseOrthos", Doc: "close orthography outputs"}, {Name: "CloseSems", Doc: "close semantic outputs"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
@@ -0,0 +1,185 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/math32"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// AddWtNoise adds a fixed perturbation of Gaussian noise, with standard
+// deviation WtNoise, to all of the weights in the network, to test the
+// robustness of the trained network to synaptic variability.
+// The noise is drawn from the network random number generator, so it is
+// reproducible for a given run.  Open the trained weights again to undo it.
+func (ss *Sim) AddWtNoise() { //types:add
+	ss.addWtNoise(ss.WtNoise)
+	ss.ViewUpdate.RecordSyns()
+	ss.ViewUpdate.Update()
+}
+
+// addWtNoise adds Gaussian noise with given standard deviation to all
+// of the weights, keeping them in the 0-1 range, and updates the linear
+// weights so that the perturbation persists through any further learning.
+func (ss *Sim) addWtNoise(sd float32) {
+	if sd <= 0 {
+		return
+	}
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			for si := range pt.Syns {
+				sy := &pt.Syns[si]
+				sy.Wt = math32.Clamp(sy.Wt+sd*float32(ss.Net.Rand.NormFloat64()), 0, 1)
+				sy.LWt = pt.Learn.WtSig.LinFromSigWt(sy.Wt)
+			}
+		}
+	}
+}
+
+// saveWts returns a copy of all of the weights in the network,
+// to restore them after adding noise with restoreWts.
+func (ss *Sim) saveWts() [][]float32 {
+	var wts [][]float32
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			pw := make([]float32, len(pt.Syns))
+			for si := range pt.Syns {
+				pw[si] = pt.Syns[si].Wt
+			}
+			wts = append(wts, pw)
+		}
+	}
+	return wts
+}
+
+// restoreWts restores the weights saved by saveWts.
+func (ss *Sim) restoreWts(wts [][]float32) {
+	pi := 0
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			for si := range pt.Syns {
+				sy := &pt.Syns[si]
+				sy.Wt = wts[pi][si]
+				sy.LWt = pt.Learn.WtSig.LinFromSigWt(sy.Wt)
+			}
+			pi++
+		}
+	}
+}
+
+// WtNoiseTrialStart saves the weights at the start of each Test trial,
+// when using WtNoisePerCycle, and draws the noise for the first cycle.
+func (ss *Sim) WtNoiseTrialStart() {
+	if ss.wtNoiseBase != nil { // restore from an interrupted trial
+		ss.restoreWts(ss.wtNoiseBase)
+		ss.wtNoiseBase = nil
+	}
+	if !ss.WtNoisePerCycle || ss.WtNoise <= 0 {
+		return
+	}
+	ss.wtNoiseBase = ss.saveWts()
+	ss.addWtNoise(ss.WtNoise)
+}
+
+// WtNoiseCycle draws new weight noise around the saved weights at the
+// end of each Test cycle, for the next cycle, when using WtNoisePerCycle.
+func (ss *Sim) WtNoiseCycle() {
+	if ss.wtNoiseBase == nil {
+		return
+	}
+	ss.restoreWts(ss.wtNoiseBase)
+	ss.addWtNoise(ss.WtNoise)
+}
+
+// WtNoiseTrialEnd restores the saved weights at the end of each Test trial.
+func (ss *Sim) WtNoiseTrialEnd() {
+	if ss.wtNoiseBase == nil {
+		return
+	}
+	ss.restoreWts(ss.wtNoiseBase)
+	ss.wtNoiseBase = nil
+}
+
+// WtNoiseSweep tests all the items with weight noise at the given number
+// of levels from 0 to maxNoise, starting from the current (e.g., trained)
+// weights each time, and records the percent of errors (PctErr) at each
+// level in the WtNoise plot, to show how performance degrades with the
+// magnitude of the noise.  The noise is a fixed perturbation, or drawn on
+// every cycle if WtNoisePerCycle is set.
+func (ss *Sim) WtNoiseSweep(maxNoise float32, levels int) { //types:add
+	if ss.GUI.Body == nil {
+		ss.wtNoiseSweep(maxNoise, levels)
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.wtNoiseSweep(maxNoise, levels)
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) wtNoiseSweep(maxNoise float32, levels int) {
+	levels = max(levels, 2)
+	ss.GUI.StopNow = false
+	dt := ss.Logs.MiscTable("WtNoise")
+	typ := "Fixed"
+	if ss.WtNoisePerCycle {
+		typ = "PerCycle"
+	}
+	noise := ss.WtNoise
+	base := ss.saveWts()
+	for li := range levels {
+		if ss.GUI.StopNow {
+			break
+		}
+		ss.WtNoise = maxNoise * float32(li) / float32(levels-1)
+		if !ss.WtNoisePerCycle {
+			ss.addWtNoise(ss.WtNoise)
+		}
+		pe := ss.TestPctErr()
+		ss.restoreWts(base)
+		row := dt.Rows
+		dt.SetNumRows(row + 1)
+		dt.SetString("Type", row, typ)
+		dt.SetFloat("Noise", row, float64(ss.WtNoise))
+		dt.SetFloat("PctErr", row, pe)
+		if plt := ss.GUI.PlotByName("WtNoise"); plt != nil {
+			plt.GoUpdatePlot()
+		} else {
+			fmt.Printf("%s weight noise: %.4g\tPctErr: %.4g\n", typ, ss.WtNoise, pe)
+		}
+	}
+	ss.WtNoise = noise
+}
+
+// ConfigWtNoise configures the table of results from WtNoiseSweep,
+// with the PctErr for each level of weight noise.
+func (ss *Sim) ConfigWtNoise(dt *table.Table) {
+	dt.SetMetaData("name", "WtNoise")
+	dt.SetMetaData("desc", "percent errors as a function of weight noise")
+	dt.SetMetaData("read-only", "true")
+	dt.SetMetaData("LegendCol", "Type")
+	if dt.NumColumns() == 0 {
+		dt.AddStringColumn("Type")
+		dt.AddFloat64Column("Noise")
+		dt.AddFloat64Column("PctErr")
+	}
+	dt.SetNumRows(0)
+}
+
+// resetWtNoisePlot clears the WtNoiseSweep results.
+// It must be called from the event loop, e.g., from a toolbar button.
+func (ss *Sim) resetWtNoisePlot() {
+	ss.ConfigWtNoise(ss.Logs.MiscTable("WtNoise"))
+	if plt := ss.GUI.PlotByName("WtNoise"); plt != nil {
+		plt.UpdatePlot()
+	}
+}
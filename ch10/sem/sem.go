@@ -17,6 +17,7 @@ import (
 	"embed"
 	"strings"
 
+	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
@@ -100,6 +101,17 @@ type Config struct {
 
 	// total number of epochs per run
 	NEpochs int `default:"50"`
+
+	// text file to train on instead of the textbook text, e.g., a large corpus,
+	// with a blank line between paragraphs.  It must be filtered to only have
+	// the words in cecn_lg_f5.words, which determine the Input units.
+	TextFile string
+
+	// memory-map the TextFile instead of reading it into memory, so the
+	// paragraphs are read from the file as they are presented, which greatly
+	// reduces memory use for a large corpus.  The file is read into memory
+	// anyway if memory mapping is not supported.
+	MMap bool
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -207,7 +219,11 @@ func (ss *Sim) ConfigEnv() {
 
 	// note: names must be standard here!
 	trn.Name = etime.Train.String()
-	trn.OpenTextsFS(content, "cecn_lg_f5.text")
+	if ss.Config.TextFile != "" {
+		errors.Log(trn.OpenTextFiles(ss.Config.MMap, ss.Config.TextFile))
+	} else {
+		trn.OpenTextsFS(content, "cecn_lg_f5.text")
+	}
 	trn.OpenWordsFS(content, "cecn_lg_f5.words")
 
 	tst.Name = etime.Test.String()
@@ -289,6 +305,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -402,8 +419,10 @@ func (ss *Sim) SetInputActAvg(net *leabra.Network) {
 func (ss *Sim) NewRun() {
 	ctx := &ss.Context
 	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
-	ss.Envs.ByMode(etime.Train).Init(0)
-	ss.Envs.ByMode(etime.Test).Init(0)
+	// reset all envs, including the extra analysis / test envs
+	for _, ev := range ss.Envs {
+		ev.Init(0)
+	}
 	ctx.Reset()
 	ctx.Mode = etime.Train
 	ss.InitWeights(ss.Net)
@@ -714,6 +733,24 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 		},
 	})
 
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Open Wts File",
+		Icon:    icons.Open,
+		Tooltip: "Open weights from a file, e.g., pretrained weights distributed separately. The weights must match the network structure.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.OpenWeights)
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Open Wts URL",
+		Icon:    icons.Open,
+		Tooltip: "Open weights from a URL. The weights must match the network structure.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.OpenWeightsURL)
+		},
+	})
+
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Wt Words",
 		Icon:    icons.RunCircle,
 		Tooltip: "reports the words associated with the strong weights shown in Hidden unit selected in the Network ",
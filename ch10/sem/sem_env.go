@@ -16,6 +16,7 @@ import (
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/mmapfile"
 	"github.com/emer/emergent/v2/env"
 	"github.com/emer/emergent/v2/etime"
 	"github.com/emer/etensor/tensor"
@@ -53,6 +54,19 @@ type SemEnv struct {
 
 	// trial is the step counter within epoch -- this is the index into Paras
 	Trial env.Counter `display:"inline"`
+
+	// text files opened by OpenTextFiles, which are memory-mapped if
+	// possible, in which case the paragraphs are parsed from the spans
+	// as they are presented, instead of being stored in Paras
+	texts []*mmapfile.File
+
+	// the locations of the paragraphs in the texts
+	spans []paraSpan
+}
+
+// paraSpan is the location of a paragraph in the texts of a SemEnv.
+type paraSpan struct {
+	text, start, end int
 }
 
 func (ev *SemEnv) Label() string { return ev.Name }
@@ -76,7 +90,7 @@ func (ev *SemEnv) Init(run int) {
 
 // InitOrder initializes the order based on current Paras, resets Trial.Cur = -1 too
 func (ev *SemEnv) InitOrder() {
-	np := len(ev.Paras)
+	np := ev.NumParas()
 	ev.Order = rand.Perm(np) // always start with new one so random order is identical
 	// and always maintain Order so random number usage is same regardless, and if
 	// user switches between Sequential and random at any point, it all works..
@@ -116,6 +130,7 @@ func (ev *SemEnv) ScanText(fp io.Reader) {
 // OpenTextsFS opens multiple text files from filesystem file.
 // use this as main API even if only opening one text
 func (ev *SemEnv) OpenTextsFS(fsys fs.FS, txts ...string) {
+	ev.CloseTexts()
 	ev.TextFiles = txts
 	ev.Paras = make([][]string, 0, 2000)
 	ev.ParaLabels = make([]string, 0, 2000)
@@ -128,6 +143,91 @@ func (ev *SemEnv) OpenTextsFS(fsys fs.FS, txts ...string) {
 	}
 }
 
+// OpenTextFiles opens the given text files, which can be very large,
+// memory-mapping them if mmap is true, so the paragraphs are read from
+// the files as they are presented, instead of all being loaded into memory.
+// Otherwise, or if the files cannot be mapped, they are read into memory,
+// but the paragraphs are still only split into words as they are presented.
+// The paragraphs have the same format as for ScanText, but the ParaLabels
+// are not recorded.  The files stay open until the next texts are opened.
+func (ev *SemEnv) OpenTextFiles(mmap bool, txts ...string) error {
+	ev.CloseTexts()
+	ev.TextFiles = txts
+	for _, tf := range txts {
+		f, err := mmapfile.Open(tf, mmap)
+		if err != nil {
+			ev.CloseTexts()
+			return err
+		}
+		ev.texts = append(ev.texts, f)
+		ev.spans = append(ev.spans, paraSpans(len(ev.texts)-1, f.Data)...)
+	}
+	return nil
+}
+
+// CloseTexts closes any text files opened by OpenTextFiles,
+// and resets all of the paragraphs.
+func (ev *SemEnv) CloseTexts() {
+	for _, f := range ev.texts {
+		errors.Log(f.Close())
+	}
+	ev.texts = nil
+	ev.spans = nil
+	ev.Paras = nil
+	ev.ParaLabels = nil
+}
+
+// paraSpans returns the locations of the paragraphs in the given text,
+// which are separated by blank lines, as in ScanText.
+func paraSpans(text int, b []byte) []paraSpan {
+	var sps []paraSpan
+	st, words := 0, false
+	for pos := 0; pos < len(b); {
+		end := bytes.IndexByte(b[pos:], '\n')
+		if end < 0 {
+			end = len(b)
+		} else {
+			end += pos
+		}
+		if len(bytes.TrimSpace(b[pos:end])) == 0 {
+			sps = append(sps, paraSpan{text, st, pos})
+			st, words = end+1, false
+		} else {
+			words = true
+		}
+		pos = end + 1
+	}
+	if words {
+		sps = append(sps, paraSpan{text, st, len(b)})
+	}
+	return sps
+}
+
+// NumParas returns the number of paragraphs.
+func (ev *SemEnv) NumParas() int {
+	if ev.spans != nil {
+		return len(ev.spans)
+	}
+	return len(ev.Paras)
+}
+
+// Para returns the words of the given paragraph.
+func (ev *SemEnv) Para(idx int) []string {
+	if ev.spans == nil {
+		return ev.Paras[idx]
+	}
+	sp := ev.spans[idx]
+	var words []string
+	for _, ln := range bytes.Split(ev.texts[sp.text].Data[sp.start:sp.end], []byte{'\n'}) {
+		wl := strings.Fields(string(ln))
+		if len(wl) > 0 && strings.Index(wl[0], ":") > 0 {
+			wl = wl[1:] // label
+		}
+		words = append(words, wl...)
+	}
+	return words
+}
+
 // CheckWords checks that the words in the slice (one word per index) are in the list.
 // Returns error for any missing words.
 func (ev *SemEnv) CheckWords(wrds []string) error {
@@ -148,6 +248,7 @@ func (ev *SemEnv) CheckWords(wrds []string) error {
 // calls InitOrder after to reset.
 // returns error for any missing words (from CheckWords)
 func (ev *SemEnv) SetParas(paras []string) error {
+	ev.CloseTexts()
 	ev.Paras = make([][]string, len(paras))
 	ev.ParaLabels = make([]string, len(paras))
 	var err error
@@ -221,8 +322,8 @@ func (ev *SemEnv) WordsFmWordMap() {
 
 func (ev *SemEnv) WordsFmText() {
 	ev.WordMap = make(map[string]int, len(ev.Words))
-	for _, para := range ev.Paras {
-		for _, wrd := range para {
+	for i := range ev.NumParas() {
+		for _, wrd := range ev.Para(i) {
 			ev.WordMap[wrd] = -1
 		}
 	}
@@ -271,8 +372,8 @@ func (ev *SemEnv) ParaIndex() int {
 // CurPara returns the current paragraph
 func (ev *SemEnv) CurPara() []string {
 	pidx := ev.ParaIndex()
-	if pidx >= 0 && pidx < len(ev.Paras) {
-		return ev.Paras[pidx]
+	if pidx >= 0 && pidx < ev.NumParas() {
+		return ev.Para(pidx)
 	}
 	return nil
 }
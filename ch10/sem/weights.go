@@ -0,0 +1,111 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/weights"
+)
+
+// OpenWeights opens network weights from the given file, which can be
+// gzipped (.gz). The weights are checked against the current network
+// structure first, and nothing is loaded if they do not match.
+func (ss *Sim) OpenWeights(filename core.Filename) error { //types:add
+	f, err := os.Open(string(filename))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return ss.ReadWeights(f, strings.HasSuffix(string(filename), ".gz"))
+}
+
+// OpenWeightsURL opens network weights from the given URL, which can be
+// gzipped (.gz), for example pretrained weights distributed separately
+// from the sim. The weights are checked as in OpenWeights.
+func (ss *Sim) OpenWeightsURL(url string) error { //types:add
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OpenWeightsURL: %s: %s", url, resp.Status)
+	}
+	return ss.ReadWeights(resp.Body, strings.HasSuffix(url, ".gz"))
+}
+
+// ReadWeights reads weights in JSON format from given reader,
+// validates them against the network, and then sets them.
+func (ss *Sim) ReadWeights(r io.Reader, gz bool) error {
+	if gz {
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gzr.Close()
+		r = gzr
+	}
+	wn, err := weights.NetReadJSON(r)
+	if err != nil {
+		return err
+	}
+	if err := ss.ValidateWeights(wn); err != nil {
+		return err
+	}
+	if err := ss.Net.SetWeights(wn); err != nil {
+		return err
+	}
+	ss.ViewUpdate.RecordSyns()
+	ss.ViewUpdate.Update()
+	return nil
+}
+
+// ValidateWeights checks that the given weights match the layers,
+// pathways and unit counts of the current network, returning an error
+// that lists all of the mismatches found.
+func (ss *Sim) ValidateWeights(wn *weights.Network) error {
+	var errs []error
+	for li := range wn.Layers {
+		lw := &wn.Layers[li]
+		ly := ss.Net.LayerByName(lw.Layer)
+		if ly == nil {
+			errs = append(errs, fmt.Errorf("layer %s is not in the network", lw.Layer))
+			continue
+		}
+		for pi := range lw.Paths {
+			pw := &lw.Paths[pi]
+			if _, err := ly.RecvPathBySendName(pw.From); err != nil {
+				errs = append(errs, fmt.Errorf("layer %s has no pathway from %s", lw.Layer, pw.From))
+				continue
+			}
+			sly := ss.Net.LayerByName(pw.From)
+			for ri := range pw.Rs {
+				rw := &pw.Rs[ri]
+				if rw.Ri >= len(ly.Neurons) {
+					errs = append(errs, fmt.Errorf("pathway %sTo%s: recv unit %d is out of range for %d units", pw.From, lw.Layer, rw.Ri, len(ly.Neurons)))
+					break
+				}
+				for _, si := range rw.Si {
+					if si >= len(sly.Neurons) {
+						errs = append(errs, fmt.Errorf("pathway %sTo%s: send unit %d is out of range for %d units", pw.From, lw.Layer, si, len(sly.Neurons)))
+						break
+					}
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("weights do not match network %s: %w", ss.Net.Name, errors.Join(errs...))
+	}
+	return nil
+}
@@ -423,6 +423,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -577,8 +578,10 @@ func (ss *Sim) ApplyInputs() {
 func (ss *Sim) NewRun() {
 	ctx := &ss.Context
 	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
-	ss.Envs.ByMode(etime.Train).Init(0)
-	ss.Envs.ByMode(etime.Test).Init(0)
+	// reset all envs, including the extra analysis / test envs
+	for _, ev := range ss.Envs {
+		ev.Init(0)
+	}
 	ctx.Reset()
 	ctx.Mode = etime.Train
 	ss.Net.InitWeights()
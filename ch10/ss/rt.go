@@ -0,0 +1,122 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"strings"
+
+	"cogentcore.org/core/math32"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/stats/split"
+	"github.com/emer/etensor/tensor/stats/stats"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// WordClasses are the classes of test items for the mean settling time
+// (RT) in the RTClass plot: the regular words (consistent and inconsistent),
+// exception words and ambiguous words of the Probe set, and the nonwords
+// of the other test sets.
+var WordClasses = []string{"Regular", "Exception", "Ambiguous", "Nonword"}
+
+// WordClass returns the class of a test item with the given Type,
+// which is the Probe code for the frequency (H or L) and consistency,
+// or Nonword for the other test sets.
+func (ss *Sim) WordClass(typ string) string {
+	if ss.TestingEnv != Probe {
+		return "Nonword"
+	}
+	switch {
+	case strings.HasSuffix(typ, "RC"), strings.HasSuffix(typ, "RI"):
+		return "Regular"
+	case strings.HasSuffix(typ, "EX"):
+		return "Exception"
+	case strings.HasSuffix(typ, "AM"):
+		return "Ambiguous"
+	}
+	return typ
+}
+
+// SettleCycle updates the RT settling time on each cycle: the output is
+// settled when the max activity in the Phon layer is above .5 and changes
+// by less than RTThreshold from one cycle to the next, for RTStable cycles
+// in a row, and RT is the first of these cycles.  Requiring a stable run
+// of cycles keeps a momentary plateau from counting as settling.
+// RT stays at 100 if the output does not settle.
+func (ss *Sim) SettleCycle() {
+	if ss.Stats.Float("RT") != 100 {
+		return
+	}
+	pmax := ss.Stats.Float32("MaxAct")
+	phn := ss.Net.LayerByName("Phon")
+	mxact := phn.Pools[0].Inhib.Act.Max
+	da := math32.Abs(mxact - pmax)
+	ss.Stats.SetFloat32("MaxAct", mxact)
+	if mxact <= 0.5 || da >= ss.Config.RTThreshold {
+		ss.Stats.SetInt("RTStable", 0)
+		return
+	}
+	n := ss.Stats.Int("RTStable") + 1
+	if n == 1 {
+		ss.Stats.SetInt("RTStart", int(ss.Context.Cycle))
+	}
+	ss.Stats.SetInt("RTStable", n)
+	if n >= ss.Config.RTStable {
+		ss.Stats.SetFloat("RT", float64(ss.Stats.Int("RTStart")))
+	}
+}
+
+// RTClassStats adds a row to the RTClass table with the mean RT for
+// each of the WordClasses in the given Test Trial log, at the current
+// training epoch, so the plot shows how the settling time for each
+// class changes over training (with testing every TestInterval epochs).
+// Classes that are not in the current test set are NaN.
+func (ss *Sim) RTClassStats(tix *table.IndexView) {
+	spl := split.GroupBy(tix, "Class")
+	split.AggColumn(spl, "RT", stats.Mean)
+	ct := spl.AggsToTable(table.ColumnNameOnly)
+
+	dt := ss.Logs.MiscTable("RTClass")
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetFloat("Epoch", row, float64(ss.Loops.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur))
+	for _, cl := range WordClasses {
+		dt.SetFloat(cl, row, math.NaN())
+	}
+	for r := range ct.Rows {
+		cl := ct.StringValue("Class", r)
+		if _, err := dt.ColumnByName(cl); err == nil {
+			dt.SetFloat(cl, row, ct.Float("RT", r))
+		}
+	}
+	if plt := ss.GUI.PlotByName("RTClass"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+}
+
+// ConfigRTClass configures the table of mean RT by word class,
+// from RTClassStats.
+func (ss *Sim) ConfigRTClass(dt *table.Table) {
+	dt.SetMetaData("name", "RTClass")
+	dt.SetMetaData("desc", "mean settling time (RT) by word class, for each test over training")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Epoch")
+	for _, cl := range WordClasses {
+		dt.AddFloat64Column(cl)
+	}
+}
+
+// ConfigRTClassPlot configures the plot of the RTClass table.
+func (ss *Sim) ConfigRTClassPlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("RTClass"), "RTClass Plot")
+	plt.Options.Title = "Settling Time by Word Class over Training"
+	plt.Options.XAxis = "Epoch"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable("RTClass"))
+	for _, cl := range WordClasses {
+		plt.SetColumnOptions(cl, plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 0)
+	}
+}
@@ -134,6 +134,12 @@ type Config struct {
 
 	// RTThreshold is the threshold for change in max activity level from once cycle to the next
 	RTThreshold float32 `default:"0.000001"`
+
+	// RTStable is the number of cycles in a row that the change in max activity
+	// must be below RTThreshold for the output to count as settled.
+	// The default of 1 is the original criterion: increase it so that a brief
+	// plateau in the activity is not counted as settling.
+	RTStable int `default:"1" min:"1"`
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -353,6 +359,7 @@ func (ss *Sim) Init() {
 	// selected or patterns have been modified etc
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -446,9 +453,11 @@ func (ss *Sim) ApplyInputs() {
 	} else {
 		ss.Stats.SetString("TrialName", evi.(*env.FixedTable).TrialName.Cur)
 		ss.Stats.SetString("Type", evi.(*env.FixedTable).GroupName.Cur)
+		ss.Stats.SetString("Class", ss.WordClass(ss.Stats.String("Type")))
 	}
 	ss.Stats.SetFloat("RT", 100)
 	ss.Stats.SetFloat("MaxAct", 0)
+	ss.Stats.SetInt("RTStable", 0)
 
 	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer)
 	for _, lnm := range lays {
@@ -474,6 +483,7 @@ func (ss *Sim) NewRun() {
 	ss.StatCounters()
 	ss.Logs.ResetLog(etime.Train, etime.Epoch)
 	ss.Logs.ResetLog(etime.Test, etime.Epoch)
+	ss.Logs.MiscTable("RTClass").SetNumRows(0)
 }
 
 // TestAll runs through the full set of testing items
@@ -506,6 +516,7 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("RT", 0.0)
 	ss.Stats.SetString("TrialName", "")
 	ss.Stats.SetString("Type", "")
+	ss.Stats.SetString("Class", "")
 	ss.Stats.SetString("Phon", "")
 	ss.Stats.SetFloat("PhonSSE", 0.0)
 	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
@@ -624,6 +635,8 @@ func (ss *Sim) TestEpochStats() {
 
 	plt.SetTable(rt)
 	plt.GoUpdatePlot()
+
+	ss.RTClassStats(tix)
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -635,6 +648,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
 	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "Type", "TrialName", "Phon")
+	ss.Logs.AddStatStringItem(etime.Test, etime.Trial, "Class")
 
 	ss.Logs.AddStatAggItem("SSE", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("AvgSSE", etime.Run, etime.Epoch, etime.Trial)
@@ -658,6 +672,8 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.NoPlot(etime.Test, etime.Run)
 	// note: Analyze not plotted by default
 	ss.Logs.SetMeta(etime.Test, etime.Trial, "Err:On", "+")
+
+	ss.ConfigRTClass(ss.Logs.MiscTable("RTClass"))
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -670,17 +686,7 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 
 	switch {
 	case time == etime.Cycle:
-		rtc := ss.Stats.Float("RT")
-		if rtc == 100 {
-			pmax := ss.Stats.Float32("MaxAct")
-			phn := ss.Net.LayerByName("Phon")
-			mxact := phn.Pools[0].Inhib.Act.Max
-			da := math32.Abs(mxact - pmax)
-			ss.Stats.SetFloat32("MaxAct", mxact)
-			if mxact > 0.5 && da < ss.Config.RTThreshold {
-				ss.Stats.SetFloat("RT", float64(ss.Context.Cycle))
-			}
-		}
+		ss.SettleCycle()
 		return
 	case time == etime.Trial:
 		ss.TrialStats()
@@ -736,6 +742,8 @@ func (ss *Sim) ConfigGUI() {
 	plt.Options.XAxis = "Type"
 	plt.SetTable(dt)
 
+	ss.ConfigRTClassPlot()
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -12,6 +12,7 @@ package main
 
 import (
 	"embed"
+	"fmt"
 	"log"
 	"reflect"
 
@@ -31,6 +32,8 @@ import (
 	"github.com/emer/emergent/v2/netview"
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor"
 	"github.com/emer/etensor/tensor/table"
 	"github.com/emer/leabra/v2/leabra"
 )
@@ -80,6 +83,10 @@ type Sim struct {
 	// input conductance to determine how hard it is to activate the receiving unit
 	GbarL float32 `default:"2" min:"0" max:"4" step:"0.05"`
 
+	// the strength of the input patterns, which multiplies the pattern values
+	// (which can themselves be graded values between 0 and 1 in the Patterns table)
+	InputStrength float32 `default:"1" min:"0" max:"1" step:"0.1"`
+
 	// the network -- click to view / edit parameters for layers, paths, etc
 	Net *leabra.Network `new-window:"+" display:"no-inline"`
 
@@ -128,6 +135,7 @@ func (ss *Sim) New() {
 
 func (ss *Sim) Defaults() {
 	ss.GbarL = 2
+	ss.InputStrength = 1
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -269,15 +277,29 @@ func (ss *Sim) ApplyInputs() {
 	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer)
 	net.InitExt()
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
+	ss.Stats.SetFloat32("Strength", ss.InputStrength)
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm)
 		pats := ev.State(ly.Name)
 		if pats != nil {
-			ly.ApplyExt(pats)
+			ly.ApplyExt(ss.ScaleInput(pats))
 		}
 	}
 }
 
+// ScaleInput returns the given input pattern with graded values
+// multiplied by the InputStrength.
+func (ss *Sim) ScaleInput(pats tensor.Tensor) tensor.Tensor {
+	if ss.InputStrength == 1 {
+		return pats
+	}
+	sp := pats.Clone()
+	for i := range sp.Len() {
+		sp.SetFloat1D(i, sp.Float1D(i)*float64(ss.InputStrength))
+	}
+	return sp
+}
+
 // NewRun intializes a new run of the model, using the TrainEnv.Run counter
 // for the new run value
 func (ss *Sim) NewRun() {
@@ -311,6 +333,7 @@ func (ss *Sim) OpenPatterns() {
 // called at start of new run
 func (ss *Sim) InitStats() {
 	ss.Stats.SetString("TrialName", "")
+	ss.Stats.SetFloat32("Strength", ss.InputStrength)
 }
 
 // StatCounters saves current counters to Stats, so they are available for logging etc
@@ -346,6 +369,7 @@ func (ss *Sim) TrialStats() {
 func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddCounterItems(etime.Trial, etime.Cycle)
 	ss.Logs.AddStatStringItem(etime.Test, etime.Trial, "TrialName")
+	ss.Logs.AddStatFloatNoAggItem(etime.Test, etime.Trial, "Strength")
 
 	// ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "InputLayer")
 
@@ -369,6 +393,71 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
 	ss.Logs.NoPlot(etime.Test, etime.Cycle)
 	ss.Logs.PlotItems("Ge", "Act")
+
+	ss.ConfigStrengthCurve(ss.Logs.MiscTable("StrengthCurve"))
+}
+
+// ConfigStrengthCurve configures the table of responses of the
+// receiving neuron as a function of input strength, from StrengthSweep.
+func (ss *Sim) ConfigStrengthCurve(dt *table.Table) {
+	dt.SetMetaData("name", "StrengthCurve")
+	dt.SetMetaData("desc", "receiving neuron response as a function of input strength")
+	dt.SetMetaData("read-only", "true")
+	dt.SetMetaData("LegendCol", "Digit")
+	if dt.NumColumns() == 0 {
+		dt.AddStringColumn("Digit")
+		dt.AddFloat64Column("Strength")
+		dt.AddFloat64Column("Ge")
+		dt.AddFloat64Column("Act")
+	}
+	dt.SetNumRows(0)
+}
+
+// StrengthSweep tests all the digits at the given number of input strengths
+// from 0 to 1, and plots the response of the receiving neuron to the given
+// digit as a function of the strength in the StrengthCurve plot, showing
+// how it varies smoothly with graded input values.
+func (ss *Sim) StrengthSweep(digit int, levels int) { //types:add
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.strengthSweep(digit, levels)
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) strengthSweep(digit int, levels int) {
+	levels = max(levels, 2)
+	ss.GUI.StopNow = false
+	dt := ss.Logs.MiscTable("StrengthCurve")
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	dnm := fmt.Sprintf("%d", digit)
+	strength := ss.InputStrength
+	for li := range levels {
+		if ss.GUI.StopNow {
+			break
+		}
+		ss.InputStrength = float32(li) / float32(levels-1)
+		ss.Loops.ResetAndRun(etime.Test)
+		for ri := range trl.Rows {
+			if trl.StringValue("TrialName", ri) != dnm {
+				continue
+			}
+			row := dt.Rows
+			dt.SetNumRows(row + 1)
+			dt.SetString("Digit", row, dnm)
+			dt.SetFloat("Strength", row, float64(ss.InputStrength))
+			dt.SetFloat("Ge", row, trl.Float("Ge", ri))
+			dt.SetFloat("Act", row, trl.Float("Act", ri))
+		}
+		if plt := ss.GUI.PlotByName("StrengthCurve"); plt != nil {
+			plt.GoUpdatePlot()
+		}
+	}
+	ss.InputStrength = strength
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -416,6 +505,15 @@ func (ss *Sim) ConfigGUI() {
 
 	ss.GUI.AddPlots(title, &ss.Logs)
 
+	stnm := "StrengthCurve"
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey(stnm), stnm+" Plot")
+	plt.Options.Title = "Response by Input Strength"
+	plt.Options.XAxis = "Strength"
+	plt.SetTable(ss.Logs.MiscTable(stnm))
+	// order of params: on, fixMin, min, fixMax, max
+	plt.SetColumnOptions("Act", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("Ge", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -432,6 +530,25 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.GUI.UpdatePlot(etime.Test, etime.Trial)
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Strength Sweep",
+		Icon:    icons.PlayArrow,
+		Tooltip: "Tests the digits at increasing input strengths, and plots the response to the given digit as a function of strength in the StrengthCurve Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.StrengthSweep)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset Strength Curve",
+		Icon:    icons.Reset,
+		Tooltip: "Reset the StrengthCurve plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.ConfigStrengthCurve(ss.Logs.MiscTable("StrengthCurve"))
+			if plt := ss.GUI.PlotByName("StrengthCurve"); plt != nil {
+				plt.UpdatePlot()
+			}
+		},
+	})
 	////////////////////////////////////////////////
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Defaults", Icon: icons.Update,
 		Tooltip: "Restore initial default parameters.",
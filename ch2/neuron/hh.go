@@ -0,0 +1,213 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cogentcore.org/core/math32"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// HHParams are the parameters and state for the Hodgkin-Huxley (1952)
+// conductance-based model of the squid giant axon, using the standard
+// biological units (mV, ms, mS/cm^2, uA/cm^2), for comparison with the
+// simplified point neuron.  Vm is reported on the normalized point neuron
+// scale, where 0 = -100 mV and 1 = 0 mV.
+type HHParams struct {
+
+	// maximal sodium conductance, in mS/cm^2
+	GbarNa float32 `default:"120"`
+
+	// maximal potassium conductance, in mS/cm^2
+	GbarK float32 `default:"36"`
+
+	// leak conductance, in mS/cm^2
+	GbarL float32 `default:"0.3"`
+
+	// sodium reversal potential, in mV
+	ENa float32 `default:"50"`
+
+	// potassium reversal potential, in mV
+	EK float32 `default:"-77"`
+
+	// leak reversal potential, in mV
+	EL float32 `default:"-54.387"`
+
+	// membrane capacitance, in uF/cm^2
+	Cm float32 `default:"1"`
+
+	// input current in uA/cm^2 for an excitatory input (Ge) of 1
+	IScale float32 `default:"30"`
+
+	// integration time step in ms -- there are 1/Dt steps per cycle, which is 1 ms
+	Dt float32 `default:"0.01" min:"0.001" max:"0.1"`
+
+	// maximum firing rate in Hz, for computing Act from the interspike interval
+	MaxHz float32 `default:"100" min:"1"`
+
+	// factor by which the gating rates are sped up by the temperature -- see Sim.Temp
+	Phi float32 `edit:"-"`
+
+	// membrane potential, in mV
+	V float32 `edit:"-"`
+
+	// sodium activation gating variable
+	M float32 `edit:"-"`
+
+	// sodium inactivation gating variable
+	H float32 `edit:"-"`
+
+	// potassium activation gating variable
+	N float32 `edit:"-"`
+}
+
+func (hp *HHParams) Defaults() {
+	hp.GbarNa = 120
+	hp.GbarK = 36
+	hp.GbarL = 0.3
+	hp.ENa = 50
+	hp.EK = -77
+	hp.EL = -54.387
+	hp.Cm = 1
+	hp.IScale = 30
+	hp.Dt = 0.01
+	hp.MaxHz = 100
+	hp.Phi = 1
+	hp.Init()
+}
+
+// Init initializes the state to rest, with the gating variables
+// at their steady-state values.
+func (hp *HHParams) Init() {
+	hp.V = -65
+	am, bm, ah, bh, an, bn := hp.Rates(hp.V)
+	hp.M = am / (am + bm)
+	hp.H = ah / (ah + bh)
+	hp.N = an / (an + bn)
+}
+
+// Rates returns the opening (alpha) and closing (beta) rates per ms
+// of the m, h, and n gating variables at membrane potential v in mV.
+func (hp *HHParams) Rates(v float32) (am, bm, ah, bh, an, bn float32) {
+	am = hhRate(0.1, v+40, 10)
+	bm = 4 * math32.Exp(-(v+65)/18)
+	ah = 0.07 * math32.Exp(-(v+65)/20)
+	bh = 1 / (1 + math32.Exp(-(v+35)/10))
+	an = hhRate(0.01, v+55, 10)
+	bn = 0.125 * math32.Exp(-(v+65)/80)
+	return
+}
+
+// hhRate returns a * x / (1 - exp(-x / k)), which goes to a * k at x = 0.
+func hhRate(a, x, k float32) float32 {
+	if math32.Abs(x) < 1e-4 {
+		return a * k
+	}
+	return a * x / (1 - math32.Exp(-x/k))
+}
+
+// Step integrates the model over one time step of Dt ms with given
+// input current in uA/cm^2, returning true if the neuron spiked,
+// which is when V crosses 0 mV going up.  The gating rates are
+// multiplied by Phi.
+func (hp *HHParams) Step(inet float32) bool {
+	dt := hp.Dt
+	gdt := dt * hp.Phi
+	am, bm, ah, bh, an, bn := hp.Rates(hp.V)
+	hp.M += gdt * (am*(1-hp.M) - bm*hp.M)
+	hp.H += gdt * (ah*(1-hp.H) - bh*hp.H)
+	hp.N += gdt * (an*(1-hp.N) - bn*hp.N)
+	ina := hp.GbarNa * hp.M * hp.M * hp.M * hp.H * (hp.V - hp.ENa)
+	ik := hp.GbarK * hp.N * hp.N * hp.N * hp.N * (hp.V - hp.EK)
+	il := hp.GbarL * (hp.V - hp.EL)
+	prv := hp.V
+	hp.V += dt * (inet - ina - ik - il) / hp.Cm
+	return prv < 0 && hp.V >= 0
+}
+
+// NormVm returns the membrane potential on the normalized
+// point neuron scale, where 0 = -100 mV and 1 = 0 mV.
+func (hp *HHParams) NormVm() float32 {
+	return (hp.V + 100) / 100
+}
+
+// HHUpdate updates the neuron using the Hodgkin-Huxley model over one
+// cycle (1 ms), in place of the point neuron equations, setting the
+// neuron variables that are plotted.  Act is the firing rate computed
+// from the average interspike interval, relative to MaxHz.
+func (ss *Sim) HHUpdate(nt *leabra.Network, inputOn bool) {
+	ly := ss.Net.LayerByName("Neuron")
+	nrn := &(ly.Neurons[0])
+	hp := &ss.HHParams
+	nrn.Ge = nrn.Ge * ly.Act.Gbar.E // effective Ge, as displayed
+	inet := hp.IScale * nrn.Ge
+	nsteps := max(int(math32.Round(1/hp.Dt)), 1)
+	spiked := false
+	for range nsteps {
+		if hp.Step(inet) {
+			spiked = true
+		}
+	}
+	nrn.Vm = hp.NormVm()
+	nrn.Inet = inet / hp.IScale
+	nrn.Gk = hp.N * hp.N * hp.N * hp.N
+	if nrn.ISI >= 0 { // -1 until first spike
+		nrn.ISI += 1
+	}
+	if spiked {
+		nrn.Spike = 1
+		if nrn.ISI > 0 {
+			if nrn.ISIAvg <= 0 {
+				nrn.ISIAvg = nrn.ISI
+			} else {
+				nrn.ISIAvg += 0.2 * (nrn.ISI - nrn.ISIAvg)
+			}
+		}
+		nrn.ISI = 0
+	} else {
+		nrn.Spike = 0
+		if nrn.ISIAvg > 0 && nrn.ISI > 1.2*nrn.ISIAvg { // slowing down
+			nrn.ISIAvg += 0.2 * (nrn.ISI - nrn.ISIAvg)
+		}
+	}
+	if nrn.ISIAvg > 0 {
+		nrn.Act = math32.Min(1000/(nrn.ISIAvg*hp.MaxHz), 1)
+	} else {
+		nrn.Act = 0
+	}
+}
+
+// HHVsSpike runs the neuron with the same input using the spiking point
+// neuron and then the Hodgkin-Huxley model, and plots the Vm traces of the
+// two models together in the HHVsSpike plot, on the normalized Vm scale.
+func (ss *Sim) HHVsSpike() {
+	tcl := ss.Logs.Table(etime.Test, etime.Cycle)
+	dt := ss.Logs.MiscTable("HHVsSpike")
+	spk, hh := ss.Spike, ss.HH
+	ss.Spike = true
+	ss.HH = false
+	tcl.Rows = 0
+	ss.RunCycles(false)
+	dt.SetNumRows(0)
+	dt.SetNumRows(tcl.Rows)
+	for ri := range tcl.Rows {
+		dt.SetFloat("Cycle", ri, tcl.Float("Cycle", ri))
+		dt.SetFloat("SpikeVm", ri, tcl.Float("Vm", ri))
+	}
+	if !ss.GUI.StopNow {
+		ss.HH = true
+		tcl.Rows = 0
+		ss.RunCycles(false)
+		for ri := range min(tcl.Rows, dt.Rows) {
+			dt.SetFloat("HHVm", ri, tcl.Float("Vm", ri))
+		}
+	}
+	ss.Spike, ss.HH = spk, hh
+	if plt := ss.GUI.PlotByName("HHVsSpike"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+	ss.GUI.IsRunning = false
+	ss.GUI.UpdateWindow()
+}
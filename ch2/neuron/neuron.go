@@ -21,6 +21,8 @@ import (
 	"cogentcore.org/core/math32/minmax"
 	"cogentcore.org/core/system"
 	"cogentcore.org/core/tree"
+	"github.com/compcogneuro/sims/v2/downsample"
+	"github.com/compcogneuro/sims/v2/inspect"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
 	"github.com/emer/emergent/v2/emer"
@@ -69,6 +71,10 @@ type Sim struct {
 	// use discrete spiking equations -- otherwise use Noisy X-over-X-plus-1 rate code activation function
 	Spike bool
 
+	// use the Hodgkin-Huxley conductance-based spiking model in HHParams instead
+	// of the point neuron -- overrides Spike.  Only GbarE and Noise apply to it.
+	HH bool
+
 	// excitatory conductance multiplier -- determines overall value of Ge which drives neuron to be more excited -- pushes up over threshold to fire if strong enough
 	GbarE float32 `min:"0" step:"0.01" def:"0.3"`
 
@@ -87,6 +93,16 @@ type Sim struct {
 	// apply sodium-gated potassium adaptation mechanisms that cause the neuron to reduce spiking over time
 	KNaAdapt bool
 
+	// temperature in degrees C, which scales the speed of the channel kinetics
+	// (membrane, adaptation and Hodgkin-Huxley gating time constants) relative to RefTemp
+	Temp float32 `min:"20" max:"45" step:"0.5" def:"37" display:"slider"`
+
+	// reference temperature in degrees C, at which the standard time constants apply
+	RefTemp float32 `min:"0" def:"37"`
+
+	// temperature coefficient: the factor by which the kinetics speed up for every 10 degrees C
+	Q10 float32 `min:"1" step:"0.1" def:"3"`
+
 	// total number of cycles to run
 	NCycles int `min:"10" def:"200"`
 
@@ -99,10 +115,19 @@ type Sim struct {
 	// how often to update display (in cycles)
 	UpdateInterval int `min:"1" def:"10"`
 
+	// maximum number of points in the Test Cycle plot, which is downsampled
+	// by averaging the cycles within equal-sized bins when many cycles have been
+	// run, keeping the plot fast and readable; the log is unaffected.
+	// 0 = plot every cycle
+	PlotPoints int `min:"0" step:"100" def:"0"`
+
 	// the network -- click to view / edit parameters for layers, paths, etc
 	Net *leabra.Network `display:"-"`
 
 	SpikeParams spike.ActParams `view:"no-inline"`
+
+	// parameters and state for the Hodgkin-Huxley model, used if HH is on
+	HHParams HHParams `view:"no-inline"`
 	// testing trial-level log data -- click to see record of network's response to each input
 
 	// leabra timing parameters and state
@@ -125,6 +150,13 @@ type Sim struct {
 
 	// map of values for detailed debugging / testing
 	ValMap map[string]float32 `display:"-"`
+
+	// downsampling of the Test Cycle log shown in its plot
+	cycPlot downsample.Plot
+
+	// time constants of the Neuron layer at RefTemp, and as last
+	// scaled by ApplyTemp for the current Temp
+	tempBase, tempScaled tempTaus
 }
 
 // New creates new blank elements and initializes defaults
@@ -137,15 +169,21 @@ func (ss *Sim) New() {
 
 func (ss *Sim) Defaults() {
 	ss.SpikeParams.Defaults()
+	ss.HHParams.Defaults()
 	ss.Params.Config(ParamSets, "", "", ss.Net)
 	ss.UpdateInterval = 10
+	ss.PlotPoints = 0
 	ss.Spike = true
+	ss.HH = false
 	ss.GbarE = 0.3
 	ss.GbarL = 0.3
 	ss.ErevE = 1
 	ss.ErevL = 0.3
 	ss.Noise = 0
 	ss.KNaAdapt = true
+	ss.Temp = 37
+	ss.RefTemp = 37
+	ss.Q10 = 3
 	ss.NCycles = 200
 	ss.OnCycle = 10
 	ss.OffCycle = 160
@@ -197,11 +235,17 @@ func (ss *Sim) Counters() string {
 }
 
 func (ss *Sim) UpdateView() {
-	ss.GUI.GoUpdatePlot(etime.Test, etime.Cycle)
+	ss.UpdateCyclePlot()
 	ss.GUI.ViewUpdate.Text = ss.Counters()
 	ss.GUI.ViewUpdate.UpdateCycle(int(ss.Context.Cycle))
 }
 
+// UpdateCyclePlot updates the Test Cycle plot, showing the Test Cycle
+// log downsampled to PlotPoints points if it has more rows than that.
+func (ss *Sim) UpdateCyclePlot() {
+	ss.cycPlot.Update(ss.GUI.Plots[etime.Scope(etime.Test, etime.Cycle)], ss.Logs.Table(etime.Test, etime.Cycle), ss.PlotPoints)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // 	    Running the Network, starting bottom-up..
 
@@ -211,6 +255,7 @@ func (ss *Sim) RunCycles(updt bool) {
 	ss.Init()
 	ss.GUI.StopNow = false
 	ss.Net.InitActs()
+	ss.HHParams.Init()
 	ctx.AlphaCycStart()
 	ss.SetParams("", false)
 	ly := ss.Net.LayerByName("Neuron")
@@ -231,9 +276,12 @@ func (ss *Sim) RunCycles(updt bool) {
 		}
 		nrn.Ge += nrn.Noise // GeNoise
 		nrn.Gi = 0
-		if ss.Spike {
+		switch {
+		case ss.HH:
+			ss.HHUpdate(ss.Net, inputOn)
+		case ss.Spike:
 			ss.SpikeUpdate(ss.Net, inputOn)
-		} else {
+		default:
 			ss.RateUpdate(ss.Net, inputOn)
 		}
 		ctx.Cycle = cyc
@@ -358,6 +406,7 @@ func (ss *Sim) SetParams(sheet string, setMsg bool) {
 	ly.Act.Erev.E = float32(ss.ErevE)
 	ly.Act.Erev.L = float32(ss.ErevL)
 	ly.Act.Noise.Var = float64(ss.Noise)
+	ss.ApplyTemp(ly)
 	ly.Act.KNa.On = ss.KNaAdapt
 	ly.Act.Update()
 	ss.SpikeParams.ActParams = ly.Act // keep sync'd
@@ -379,6 +428,18 @@ func (ss *Sim) ConfigLogs() {
 	svr.AddFloat64Column("Spike")
 	svr.AddFloat64Column("Rate")
 	svr.SetMetaData("Rate:On", "+")
+
+	hvs := ss.Logs.MiscTable("HHVsSpike")
+	hvs.AddFloat64Column("Cycle")
+	hvs.AddFloat64Column("SpikeVm")
+	hvs.AddFloat64Column("HHVm")
+	hvs.SetMetaData("SpikeVm:On", "+")
+	hvs.SetMetaData("HHVm:On", "+")
+
+	rvt := ss.Logs.MiscTable("RateVsTemp")
+	rvt.AddFloat64Column("Temp")
+	rvt.AddFloat64Column("Rate")
+	rvt.SetMetaData("Rate:On", "+")
 }
 
 func (ss *Sim) ConfigLogItems() {
@@ -414,6 +475,7 @@ func (ss *Sim) ConfigLogItems() {
 
 func (ss *Sim) ResetTestCyclePlot() {
 	ss.Logs.ResetLog(etime.Test, etime.Cycle)
+	ss.cycPlot.Reset(ss.GUI.Plots[etime.Scope(etime.Test, etime.Cycle)], ss.Logs.Table(etime.Test, etime.Cycle))
 	ss.GUI.UpdatePlot(etime.Test, etime.Cycle)
 }
 
@@ -447,6 +509,18 @@ func (ss *Sim) ConfigGUI() {
 	plt.Options.XAxis = "GBarE"
 	plt.SetTable(dt)
 
+	hvs := "HHVsSpike"
+	hplt := ss.GUI.NewPlotTab(etime.ScopeKey(hvs), hvs+" Plot")
+	hplt.Options.Title = "Vm: Hodgkin-Huxley vs. Spiking Point Neuron"
+	hplt.Options.XAxis = "Cycle"
+	hplt.SetTable(ss.Logs.MiscTable(hvs))
+
+	rvt := "RateVsTemp"
+	rplt := ss.GUI.NewPlotTab(etime.ScopeKey(rvt), rvt+" Plot")
+	rplt.Options.Title = "Firing Rate (Hz) vs. Temperature (C)"
+	rplt.Options.XAxis = "Temp"
+	rplt.SetTable(ss.Logs.MiscTable(rvt))
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -499,6 +573,32 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.GUI.UpdateWindow()
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "HH Vs Spike", Icon: icons.PlayArrow,
+		Tooltip: "Runs the same input through the spiking point neuron and the Hodgkin-Huxley model, and plots the two Vm traces.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.GUI.IsRunning = true
+			go ss.HHVsSpike()
+			ss.GUI.UpdateWindow()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Rate Vs Temp", Icon: icons.PlayArrow,
+		Tooltip: "Generate a plot of the firing rate as a function of temperature, from 10 degrees below to 10 degrees above RefTemp.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.GUI.IsRunning = true
+			go ss.RateVsTemp()
+			ss.GUI.UpdateWindow()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Inspect", Icon: icons.Search,
+		Tooltip: "Shows the exact current values of the neuron's variables in a popup table.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ly := ss.Net.LayerByName("Neuron")
+			inspect.Show(ss.GUI.Body, inspect.LayerTable(ly, "Ge", "Inet", "Vm", "Act", "Spike", "Gk", "ISI", "ISIAvg"), "Inspect Neuron")
+		},
+	})
 
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Defaults", Icon: icons.Update,
 		Tooltip: "Restore initial default parameters.",
@@ -0,0 +1,88 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// TempFactor returns the factor by which the channel kinetics are sped up
+// at the current Temp relative to RefTemp, based on the Q10 temperature
+// coefficient: Q10^((Temp - RefTemp) / 10).
+func (ss *Sim) TempFactor() float32 {
+	return float32(math.Pow(float64(ss.Q10), float64(ss.Temp-ss.RefTemp)/10))
+}
+
+// tempTaus are the time constants of a layer that are scaled by ApplyTemp:
+// Act.Dt.VmTau and the Act.KNa Fast, Med and Slow Tau.
+type tempTaus [4]float32
+
+// tauPtrs returns pointers to the time constants of the given layer
+// that are scaled by ApplyTemp, in the order of tempTaus.
+func tauPtrs(ly *leabra.Layer) [4]*float32 {
+	return [4]*float32{&ly.Act.Dt.VmTau, &ly.Act.KNa.Fast.Tau, &ly.Act.KNa.Med.Tau, &ly.Act.KNa.Slow.Tau}
+}
+
+// ApplyTemp scales the time constants of the given layer's membrane
+// potential and sodium-gated potassium adaptation channels, and the
+// Hodgkin-Huxley gating rates, by the TempFactor.  Each time constant is
+// scaled from its base value at RefTemp, which is saved the first time,
+// and again whenever it has been changed since the last ApplyTemp
+// (e.g., by the params or by editing the layer), so repeated calls
+// do not compound and edits are kept.
+func (ss *Sim) ApplyTemp(ly *leabra.Layer) {
+	tf := ss.TempFactor()
+	for i, tau := range tauPtrs(ly) {
+		if *tau != ss.tempScaled[i] {
+			ss.tempBase[i] = *tau
+		}
+		*tau = ss.tempBase[i] / tf
+		ss.tempScaled[i] = *tau
+	}
+	ss.HHParams.Phi = tf
+}
+
+// RateVsTemp runs the neuron at a range of temperatures around RefTemp
+// and plots the firing rate while the input is on, in Hz, as a function
+// of the temperature in the RateVsTemp plot.  It uses the HH model if on,
+// and otherwise the spiking point neuron.
+func (ss *Sim) RateVsTemp() {
+	tcl := ss.Logs.Table(etime.Test, etime.Cycle)
+	dt := ss.Logs.MiscTable("RateVsTemp")
+	dt.SetNumRows(0)
+	temp, spk := ss.Temp, ss.Spike
+	ss.Spike = true
+	for t := ss.RefTemp - 10; t <= ss.RefTemp+10; t++ {
+		ss.Temp = t
+		tcl.Rows = 0
+		ss.RunCycles(false)
+		if ss.GUI.StopNow {
+			break
+		}
+		nspk := 0.0
+		on, off := ss.OnCycle, min(ss.OffCycle, tcl.Rows)
+		for ri := on; ri < off; ri++ {
+			nspk += tcl.Float("Spike", ri)
+		}
+		rate := 0.0
+		if off > on {
+			rate = 1000 * nspk / float64(off-on) // cycles are 1 ms
+		}
+		row := dt.Rows
+		dt.SetNumRows(row + 1)
+		dt.SetFloat("Temp", row, float64(t))
+		dt.SetFloat("Rate", row, rate)
+		if plt := ss.GUI.PlotByName("RateVsTemp"); plt != nil {
+			plt.GoUpdatePlot()
+		}
+	}
+	ss.Temp, ss.Spike = temp, spk
+	ss.SetParams("", false)
+	ss.GUI.IsRunning = false
+	ss.GUI.UpdateWindow()
+}
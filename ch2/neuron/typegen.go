@@ -6,4 +6,6 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Spike", Doc: "use discrete spiking equations -- otherwise use Noisy X-over-X-plus-1 rate code activation function"}, {Name: "GbarE", Doc: "excitatory conductance multiplier -- determines overall value of Ge which drives neuron to be more excited -- pushes up over threshold to fire if strong enough"}, {Name: "GbarL", Doc: "leak conductance -- determines overall value of Gl which drives neuron to be less excited (inhibited) -- pushes back to resting membrane potential"}, {Name: "ErevE", Doc: "excitatory reversal (driving) potential -- determines where excitation pushes Vm up to"}, {Name: "ErevL", Doc: "leak reversal (driving) potential -- determines where excitation pulls Vm down to"}, {Name: "Noise", Doc: "the variance parameter for Gaussian noise added to unit activations on every cycle"}, {Name: "KNaAdapt", Doc: "apply sodium-gated potassium adaptation mechanisms that cause the neuron to reduce spiking over time"}, {Name: "NCycles", Doc: "total number of cycles to run"}, {Name: "OnCycle", Doc: "when does excitatory input into neuron come on?"}, {Name: "OffCycle", Doc: "when does excitatory input into neuron go off?"}, {Name: "UpdateInterval", Doc: "how often to update display (in cycles)"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "SpikeParams"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "logging"}, {Name: "Params", Doc: "all parameter management"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "ValMap", Doc: "map of values for detailed debugging / testing"}}})
+var _ = types.AddType(&types.Type{Name: "main.HHParams", IDName: "hh-params", Doc: "HHParams are the parameters and state for the Hodgkin-Huxley (1952)\nconductance-based model of the squid giant axon, using the standard\nbiological units (mV, ms, mS/cm^2, uA/cm^2), for comparison with the\nsimplified point neuron.  Vm is reported on the normalized point neuron\nscale, where 0 = -100 mV and 1 = 0 mV.", Fields: []types.Field{{Name: "GbarNa", Doc: "maximal sodium conductance, in mS/cm^2"}, {Name: "GbarK", Doc: "maximal potassium conductance, in mS/cm^2"}, {Name: "GbarL", Doc: "leak conductance, in mS/cm^2"}, {Name: "ENa", Doc: "sodium reversal potential, in mV"}, {Name: "EK", Doc: "potassium reversal potential, in mV"}, {Name: "EL", Doc: "leak reversal potential, in mV"}, {Name: "Cm", Doc: "membrane capacitance, in uF/cm^2"}, {Name: "IScale", Doc: "input current in uA/cm^2 for an excitatory input (Ge) of 1"}, {Name: "Dt", Doc: "integration time step in ms -- there are 1/Dt steps per cycle, which is 1 ms"}, {Name: "MaxHz", Doc: "maximum firing rate in Hz, for computing Act from the interspike interval"}, {Name: "Phi", Doc: "factor by which the gating rates are sped up by the temperature -- see Sim.Temp"}, {Name: "V", Doc: "membrane potential, in mV"}, {Name: "M", Doc: "sodium activation gating variable"}, {Name: "H", Doc: "sodium inactivation gating variable"}, {Name: "N", Doc: "potassium activation gating variable"}}})
+
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Spike", Doc: "use discrete spiking equations -- otherwise use Noisy X-over-X-plus-1 rate code activation function"}, {Name: "HH", Doc: "use the Hodgkin-Huxley conductance-based spiking model in HHParams instead\nof the point neuron -- overrides Spike.  Only GbarE and Noise apply to it."}, {Name: "GbarE", Doc: "excitatory conductance multiplier -- determines overall value of Ge which drives neuron to be more excited -- pushes up over threshold to fire if strong enough"}, {Name: "GbarL", Doc: "leak conductance -- determines overall value of Gl which drives neuron to be less excited (inhibited) -- pushes back to resting membrane potential"}, {Name: "ErevE", Doc: "excitatory reversal (driving) potential -- determines where excitation pushes Vm up to"}, {Name: "ErevL", Doc: "leak reversal (driving) potential -- determines where excitation pulls Vm down to"}, {Name: "Noise", Doc: "the variance parameter for Gaussian noise added to unit activations on every cycle"}, {Name: "KNaAdapt", Doc: "apply sodium-gated potassium adaptation mechanisms that cause the neuron to reduce spiking over time"}, {Name: "Temp", Doc: "temperature in degrees C, which scales the speed of the channel kinetics\n(membrane, adaptation and Hodgkin-Huxley gating time constants) relative to RefTemp"}, {Name: "RefTemp", Doc: "reference temperature in degrees C, at which the standard time constants apply"}, {Name: "Q10", Doc: "temperature coefficient: the factor by which the kinetics speed up for every 10 degrees C"}, {Name: "NCycles", Doc: "total number of cycles to run"}, {Name: "OnCycle", Doc: "when does excitatory input into neuron come on?"}, {Name: "OffCycle", Doc: "when does excitatory input into neuron go off?"}, {Name: "UpdateInterval", Doc: "how often to update display (in cycles)"}, {Name: "PlotPoints", Doc: "maximum number of points in the Test Cycle plot, which is downsampled\nby averaging the cycles within equal-sized bins when many cycles have been\nrun, keeping the plot fast and readable; the log is unaffected.\n0 = plot every cycle"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "SpikeParams"}, {Name: "HHParams", Doc: "parameters and state for the Hodgkin-Huxley model, used if HH is on"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "logging"}, {Name: "Params", Doc: "all parameter management"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "ValMap", Doc: "map of values for detailed debugging / testing"}}})
@@ -0,0 +1,111 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// FeatureImportance measures how much each of the feature layers
+// (MorphLayers) contributes to the Species decision for a reference item,
+// the prototypical cat (or dog if dog is true), with the features that
+// the network fills in for it as the input.  Each feature layer is ablated
+// in turn, by removing its input, and its importance is how much the
+// proportion of Species activity for the reference species drops as
+// a result, which is shown in the Importance bar plot.  A negative
+// importance means that the feature pulls toward the other species.
+func (ss *Sim) FeatureImportance(dog bool) { //types:add
+	if ss.GUI.Body == nil {
+		ss.featureImportance(dog)
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.featureImportance(dog)
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) featureImportance(dog bool) {
+	ref, nm := 0, "cat"
+	if dog {
+		ref, nm = 1, "dog"
+	}
+	protos := ss.prototypes()
+	nf := len(MorphLayers)
+	pd := ss.blankPatterns(nf + 1)
+	for r := range nf + 1 {
+		pnm := nm
+		if r > 0 {
+			pnm += "_no_" + MorphLayers[r-1]
+		}
+		pd.SetString("PatternName", r, pnm)
+		for li, lnm := range MorphLayers {
+			if r == li+1 { // ablated
+				continue
+			}
+			pt := protos.Tensor(lnm+"_Act", ref)
+			for i := range pt.Len() {
+				pd.SetTensorFloat1D(lnm, r, i, pt.Float1D(i))
+			}
+		}
+	}
+	trl := ss.testPatterns(pd)
+
+	// pref returns the proportion of Species activity for the reference
+	pref := func(row int) float64 {
+		_, _, pdog := speciesResponse(trl, row)
+		if dog {
+			return pdog
+		}
+		return 1 - pdog
+	}
+	full := pref(0)
+	dt := ss.Logs.MiscTable("Importance")
+	dt.SetNumRows(nf)
+	for li, lnm := range MorphLayers {
+		abl := pref(li + 1)
+		dt.SetFloat("Feature", li, float64(li))
+		dt.SetString("Layer", li, lnm)
+		dt.SetFloat("Full", li, full)
+		dt.SetFloat("Ablated", li, abl)
+		dt.SetFloat("Importance", li, full-abl)
+	}
+	if plt := ss.GUI.PlotByName("Importance"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+}
+
+// ConfigImportance configures the table of results from FeatureImportance.
+func (ss *Sim) ConfigImportance(dt *table.Table) {
+	dt.SetMetaData("name", "Importance")
+	dt.SetMetaData("desc", "drop in the Species response for the reference species when each feature layer is ablated")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Feature")
+	dt.AddStringColumn("Layer")
+	dt.AddFloat64Column("Full")
+	dt.AddFloat64Column("Ablated")
+	dt.AddFloat64Column("Importance")
+}
+
+// ConfigImportancePlot configures the bar plot of the Importance table.
+func (ss *Sim) ConfigImportancePlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("Importance"), "Importance Plot")
+	plt.Options.Title = "Feature Importance for the Species Decision"
+	plt.Options.Type = plotcore.Bar
+	plt.Options.XAxis = "Feature"
+	plt.SetTable(ss.Logs.MiscTable("Importance"))
+	// order of params: on, fixMin, min, fixMax, max
+	plt.SetColumnOptions("Layer", plotcore.On, plotcore.FloatMin, 0, plotcore.FloatMax, 0)
+	plt.SetColumnOptions("Full", plotcore.Off, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("Ablated", plotcore.Off, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("Importance", plotcore.On, plotcore.FloatMin, 0, plotcore.FloatMax, 0)
+}
@@ -32,6 +32,8 @@ import (
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
 	"github.com/emer/emergent/v2/relpos"
+	"github.com/emer/etensor/tensor"
+	"github.com/emer/etensor/tensor/stats/metric"
 	"github.com/emer/etensor/tensor/table"
 	"github.com/emer/leabra/v2/leabra"
 )
@@ -101,6 +103,17 @@ type Sim struct {
 	// the patterns to use
 	Patterns *table.Table `new-window:"+" display:"no-inline"`
 
+	// names of the layers whose average activity is plotted on every cycle,
+	// to show the settling dynamics of the network. The activity of all
+	// layers is logged, as <Layer>_AvgAct, so others can be turned on in
+	// the plot. This sets the plot columns that are on when the logs are
+	// configured at startup, so use the plot column toggles to change them.
+	PlotLayers []string `edit:"-"`
+
+	// where the Species response flips from cat to dog in the last MorphSweep:
+	// the blend t at which the proportion of dog activity crosses .5
+	MorphBoundary float64 `edit:"-"`
+
 	// Environments
 	Envs env.Envs `display:"-"`
 
@@ -130,6 +143,7 @@ func (ss *Sim) New() {
 }
 
 func (ss *Sim) Defaults() {
+	ss.PlotLayers = []string{"Identity", "Species"}
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -268,6 +282,8 @@ func (ss *Sim) ConfigLoops() {
 	})
 	leabra.LooperResetLogBelow(ls, &ss.Logs)
 
+	ls.Loop(etime.Test, etime.Epoch).OnEnd.Add("LayerCorrels", ss.LayerCorrels)
+
 	////////////////////////////////////////////
 	// GUI
 
@@ -380,12 +396,72 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddCounterItems(etime.Trial, etime.Cycle)
 	ss.Logs.AddStatStringItem(etime.Test, etime.Trial, "TrialName")
 	ss.Logs.AddStatAggItem("Harmony", etime.Trial, etime.Cycle)
+	lays := make([]string, len(ss.Net.Layers))
+	for i, ly := range ss.Net.Layers {
+		lays[i] = ly.Name
+	}
+	ss.AddLayerActItems(lays...)
 
 	ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "InputLayer", "CompareLayer")
 
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
 	ss.Logs.PlotItems("Harmony")
+	for _, lnm := range ss.PlotLayers {
+		ss.Logs.PlotItems(lnm + "_AvgAct")
+	}
+
+	ss.ConfigMorph(ss.Logs.MiscTable("Morph"))
+	ss.ConfigImportance(ss.Logs.MiscTable("Importance"))
+}
+
+// AddLayerActItems adds a <Layer>_AvgAct log item for each of the given
+// layers, recording the average activity of the layer on every cycle
+// and at the end of each trial. The values are set in LayerActStats.
+func (ss *Sim) AddLayerActItems(lays ...string) {
+	for _, lnm := range lays {
+		ss.Logs.AddStatAggItem(lnm+"_AvgAct", etime.Trial, etime.Cycle)
+	}
+}
+
+// LayerActStats records the current average activity of each layer
+// in Stats, for the <Layer>_AvgAct log items.
+func (ss *Sim) LayerActStats() {
+	for _, ly := range ss.Net.Layers {
+		ss.Stats.SetFloat32(ly.Name+"_AvgAct", ly.Pools[0].Inhib.Act.Avg)
+	}
+}
+
+// LayerCorrels computes the correlation between the average activities of
+// each pair of layers across the trials of the test epoch, from the
+// <Layer>_AvgAct columns of the Test Trial log, into the LayerCorrels SimMat,
+// which is shown as a heatmap in the LayerCorrels tab. Layers with constant
+// activity, e.g., clamped inputs, have undefined (NaN) correlations.
+func (ss *Sim) LayerCorrels() {
+	dt := ss.Logs.Table(etime.Test, etime.Trial)
+	nly := len(ss.Net.Layers)
+	lays := make([]string, nly)
+	acts := make([][]float64, nly)
+	for i, ly := range ss.Net.Layers {
+		lays[i] = ly.Name
+		acts[i] = errors.Log1(dt.ColumnByName(ly.Name + "_AvgAct")).(*tensor.Float64).Values
+	}
+	sm := ss.Stats.SimMat("LayerCorrels")
+	sm.Rows = lays
+	sm.Columns = lays
+	sm.Mat.SetShape([]int{nly, nly})
+	for i := range nly {
+		for j := range nly {
+			sm.Mat.SetFloat([]int{i, j}, metric.Correlation64(acts[i], acts[j]))
+		}
+	}
+	if ss.GUI.Grids == nil {
+		return
+	}
+	gv := ss.GUI.Grid("LayerCorrels")
+	gv.AsyncLock()
+	gv.Update()
+	gv.AsyncUnlock()
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -404,6 +480,7 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 	case time == etime.Cycle:
 		ss.StatCounters()
 		ss.Stats.SetFloat32("Harmony", ss.Harmony(ss.Net))
+		ss.LayerActStats()
 	case time == etime.Trial:
 		ss.StatCounters()
 		ss.Logs.Log(mode, time) // also logs to file, etc
@@ -451,6 +528,13 @@ func (ss *Sim) ConfigGUI() {
 	ss.ConfigNetView(nv)
 
 	ss.GUI.AddPlots(title, &ss.Logs)
+	ss.ConfigMorphPlot()
+	ss.ConfigImportancePlot()
+
+	cg := ss.GUI.AddGridTab("LayerCorrels")
+	sm := ss.Stats.SimMat("LayerCorrels")
+	sm.Mat.SetShape([]int{len(ss.Net.Layers), len(ss.Net.Layers)})
+	cg.SetTensor(sm.Mat)
 
 	ss.GUI.FinalizeGUI(false)
 }
@@ -458,6 +542,24 @@ func (ss *Sim) ConfigGUI() {
 func (ss *Sim) MakeToolbar(p *tree.Plan) {
 	ss.GUI.AddLooperCtrl(p, ss.Loops)
 
+	tree.Add(p, func(w *core.Separator) {})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Morph Sweep",
+		Icon:    icons.Image,
+		Tooltip: "present blends of the prototypical cat and dog features over the given number of steps from t = 0 (cat) to 1 (dog), and plot the Species response in the Morph Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.MorphSweep)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Feature Importance",
+		Icon:    icons.Image,
+		Tooltip: "ablate each feature layer of the prototypical cat (or dog) in turn, and plot how much the Species response drops in the Importance Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.FeatureImportance)
+		},
+	})
+
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "README",
 		Icon:    icons.FileMarkdown,
@@ -0,0 +1,189 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// MorphLayers are the feature layers that are blended between the
+// prototypical cat and dog in MorphSweep.  The Species layer is not
+// given any input, as it is the category response.
+var MorphLayers = []string{"Color", "FavoriteFood", "Size", "FavoriteToy"}
+
+// MorphSweep presents a series of steps+1 blends of the features of the
+// prototypical cat and dog, from t = 0 (cat) to 1 (dog), and plots the
+// category response of the Species layer in the Morph Plot, as the
+// proportion of its activity for dog (PDog).  The prototypes are the
+// features that the network fills in when just cat or dog is on, and
+// each blend is (1-t) * cat + t * dog, for each of the MorphLayers.
+// The features change linearly, so a categorical response shows up as
+// a steeper change in PDog around the MorphBoundary, where it crosses .5.
+func (ss *Sim) MorphSweep(steps int) { //types:add
+	if ss.GUI.Body == nil {
+		ss.morphSweep(steps)
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.morphSweep(steps)
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) morphSweep(steps int) {
+	steps = max(steps, 1)
+	protos := ss.prototypes()
+	md := ss.blankPatterns(steps + 1)
+	for r := range steps + 1 {
+		t := float64(r) / float64(steps)
+		md.SetString("PatternName", r, fmt.Sprintf("morph_%.2f", t))
+		for _, lnm := range MorphLayers {
+			cat := protos.Tensor(lnm+"_Act", 0)
+			dog := protos.Tensor(lnm+"_Act", 1)
+			for i := range cat.Len() {
+				md.SetTensorFloat1D(lnm, r, i, (1-t)*cat.Float1D(i)+t*dog.Float1D(i))
+			}
+		}
+	}
+	trl := ss.testPatterns(md)
+
+	dt := ss.Logs.MiscTable("Morph")
+	dt.SetNumRows(steps + 1)
+	ts := make([]float64, steps+1)
+	ps := make([]float64, steps+1)
+	for r := range steps + 1 {
+		cat, dog, pdog := speciesResponse(trl, r)
+		ts[r] = float64(r) / float64(steps)
+		ps[r] = pdog
+		dt.SetFloat("T", r, ts[r])
+		dt.SetFloat("Cat", r, cat)
+		dt.SetFloat("Dog", r, dog)
+		dt.SetFloat("PDog", r, pdog)
+	}
+	ss.MorphBoundary = boundary(ts, ps)
+	if ss.GUI.SimForm != nil {
+		ss.GUI.SimForm.AsyncLock()
+		ss.GUI.SimForm.Update()
+		ss.GUI.SimForm.AsyncUnlock()
+	}
+	if plt := ss.GUI.PlotByName("Morph"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+}
+
+// prototypes tests the prototypical cat and dog, with just the cat or dog
+// Species unit on, and returns the Test Trial log, which has the features
+// filled in for the cat in row 0, and the dog in row 1.
+func (ss *Sim) prototypes() *table.Table {
+	pd := ss.blankPatterns(2)
+	for i, nm := range []string{"cat", "dog"} {
+		pd.SetString("PatternName", i, nm)
+		pd.SetTensorFloat1D("Species", i, i, 1)
+	}
+	return ss.testPatterns(pd)
+}
+
+// speciesResponse returns the activity of the cat and dog Species units
+// in the given row of the Test Trial log, and the proportion for dog,
+// which is NaN if neither is active.
+func speciesResponse(trl *table.Table, row int) (cat, dog, pdog float64) {
+	sp := trl.Tensor("Species_Act", row)
+	cat, dog = sp.Float1D(0), sp.Float1D(1)
+	pdog = math.NaN()
+	if cat+dog > 0 {
+		pdog = dog / (cat + dog)
+	}
+	return
+}
+
+// boundary returns the t at which p first crosses .5, linearly
+// interpolated between the points, or NaN if it does not cross it.
+func boundary(ts, ps []float64) float64 {
+	for i := 1; i < len(ps); i++ {
+		p0, p1 := ps[i-1]-.5, ps[i]-.5
+		if math.IsNaN(p0) || math.IsNaN(p1) || (p0 < 0) == (p1 < 0) {
+			continue
+		}
+		if p0 == p1 {
+			return ts[i-1]
+		}
+		return ts[i-1] + (ts[i]-ts[i-1])*p0/(p0-p1)
+	}
+	return math.NaN()
+}
+
+// blankPatterns returns a copy of the Patterns with the given
+// number of rows, all with no input.
+func (ss *Sim) blankPatterns(n int) *table.Table {
+	dt := ss.Patterns.Clone()
+	dt.SetNumRows(n)
+	for _, cl := range dt.Columns {
+		if cl.IsString() {
+			continue
+		}
+		for i := range cl.Len() {
+			cl.SetFloat1D(i, 0)
+		}
+	}
+	return dt
+}
+
+// testPatterns tests all of the given patterns, and returns a copy of
+// the resulting Test Trial log, then sets the Test env back to the Patterns.
+func (ss *Sim) testPatterns(dt *table.Table) *table.Table {
+	ss.SetPatternsTable(dt)
+	mode := ss.Loops.Mode
+	ss.GUI.StopNow = false
+	ss.Loops.ResetAndRun(etime.Test)
+	ss.Loops.Mode = mode
+	trl := ss.Logs.Table(etime.Test, etime.Trial).Clone()
+	ss.SetPatternsTable(ss.Patterns)
+	return trl
+}
+
+// SetPatternsTable sets the Test env to present the given patterns,
+// keeping the Test trial loop in sync with the number of them.
+func (ss *Sim) SetPatternsTable(dt *table.Table) {
+	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	ev.Table = table.NewIndexView(dt)
+	ev.Init(0)
+	if ss.Loops != nil {
+		ss.Loops.Stacks[etime.Test].Loops[etime.Trial].Counter.Max = ev.Table.Len()
+	}
+}
+
+// ConfigMorph configures the table of results from MorphSweep.
+func (ss *Sim) ConfigMorph(dt *table.Table) {
+	dt.SetMetaData("name", "Morph")
+	dt.SetMetaData("desc", "species response as a function of the blend between the prototypical cat and dog")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("T")
+	dt.AddFloat64Column("Cat")
+	dt.AddFloat64Column("Dog")
+	dt.AddFloat64Column("PDog")
+}
+
+// ConfigMorphPlot configures the plot of the Morph table.
+func (ss *Sim) ConfigMorphPlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("Morph"), "Morph Plot")
+	plt.Options.Title = "Species Response by Cat-Dog Blend"
+	plt.Options.XAxis = "T"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable("Morph"))
+	plt.SetColumnOptions("PDog", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("Cat", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("Dog", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+}
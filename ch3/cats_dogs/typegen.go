@@ -6,4 +6,4 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Patterns", Doc: "the patterns to use"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "FeatureImportance", Doc: "FeatureImportance measures how much each of the feature layers\n(MorphLayers) contributes to the Species decision for a reference item,\nthe prototypical cat (or dog if dog is true), with the features that\nthe network fills in for it as the input.  Each feature layer is ablated\nin turn, by removing its input, and its importance is how much the\nproportion of Species activity for the reference species drops as\na result, which is shown in the Importance bar plot.  A negative\nimportance means that the feature pulls toward the other species.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"dog"}}, {Name: "MorphSweep", Doc: "MorphSweep presents a series of steps+1 blends of the features of the\nprototypical cat and dog, from t = 0 (cat) to 1 (dog), and plots the\ncategory response of the Species layer in the Morph Plot, as the\nproportion of its activity for dog (PDog).  The prototypes are the\nfeatures that the network fills in when just cat or dog is on, and\neach blend is (1-t) * cat + t * dog, for each of the MorphLayers.\nThe features change linearly, so a categorical response shows up as\na steeper change in PDog around the MorphBoundary, where it crosses .5.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"steps"}}}, Fields: []types.Field{{Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Patterns", Doc: "the patterns to use"}, {Name: "PlotLayers", Doc: "names of the layers whose average activity is plotted on every cycle,\nto show the settling dynamics of the network. The activity of all\nlayers is logged, as <Layer>_AvgAct, so others can be turned on in\nthe plot. This sets the plot columns that are on when the logs are\nconfigured at startup, so use the plot column toggles to change them."}, {Name: "MorphBoundary", Doc: "where the Species response flips from cat to dog in the last MorphSweep:\nthe blend t at which the proportion of dog activity crosses .5"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
@@ -0,0 +1,107 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/errors"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// DPrimeTrial counts the current Test trial for the DPrime stat,
+// where the target category is the DPrimeUnit of the DPrimeLayer:
+// it is present if that unit has the target value, and is
+// responded if that unit is the most active one in the minus phase.
+// It is called from Log, so each trial is only counted once.
+func (ss *Sim) DPrimeTrial() {
+	ly := ss.Net.LayerByName(ss.DPrimeLayer)
+	if ly == nil || ss.DPrimeUnit < 0 || ss.DPrimeUnit >= len(ly.Neurons) {
+		return
+	}
+	target := ly.Neurons[ss.DPrimeUnit].Targ > .5
+	response := maxUnit(ly, "ActM") == ss.DPrimeUnit
+	ss.dprime.Add(target, response)
+}
+
+// DPrimeConditions tests the full and partial faces, and records the DPrime
+// signal detection sensitivity for the target category (DPrimeUnit of
+// DPrimeLayer) in each condition in the DPrime table and plot, along with
+// the hit and false alarm rates it is computed from.
+// The full faces are presented again at the end.
+func (ss *Sim) DPrimeConditions() { //types:add
+	if ss.GUI.Body == nil {
+		errors.Log(ss.dprimeConditions())
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		errors.Log(ss.dprimeConditions())
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) dprimeConditions() error {
+	switch ss.DPrimeLayer {
+	case "Emotion", "Gender", "Identity":
+	default:
+		return fmt.Errorf("DPrimeConditions: DPrimeLayer must be Emotion, Gender, or Identity, not %q", ss.DPrimeLayer)
+	}
+	conds := []struct {
+		name string
+		pats *table.Table
+	}{{"Full", ss.Patterns}, {"Partial", ss.PartialPatterns}}
+	dt := ss.Logs.MiscTable("DPrime")
+	dt.SetNumRows(len(conds))
+	mode := ss.Loops.Mode
+	for ci, cd := range conds {
+		ss.SetPatternsTable(cd.pats)
+		ss.GUI.StopNow = false
+		ss.Loops.ResetAndRun(etime.Test)
+		dt.SetFloat("Cond", ci, float64(ci))
+		dt.SetString("Condition", ci, cd.name)
+		dt.SetFloat("DPrime", ci, ss.dprime.DPrime())
+		dt.SetFloat("HitRate", ci, ss.dprime.HitRate())
+		dt.SetFloat("FARate", ci, ss.dprime.FARate())
+	}
+	ss.SetPatternsTable(ss.Patterns)
+	ss.Loops.Mode = mode
+	if plt := ss.GUI.PlotByName("DPrime"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+	return nil
+}
+
+// ConfigDPrime configures the table of results from DPrimeConditions.
+func (ss *Sim) ConfigDPrime(dt *table.Table) {
+	dt.SetMetaData("name", "DPrime")
+	dt.SetMetaData("desc", "signal detection sensitivity d' for the target category in each test condition")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Cond")
+	dt.AddStringColumn("Condition")
+	dt.AddFloat64Column("DPrime")
+	dt.AddFloat64Column("HitRate")
+	dt.AddFloat64Column("FARate")
+}
+
+// ConfigDPrimePlot configures the plot of the DPrime table.
+func (ss *Sim) ConfigDPrimePlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("DPrime"), "DPrime Plot")
+	plt.Options.Title = "Target Category Sensitivity (d') by Condition"
+	plt.Options.XAxis = "Cond"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable("DPrime"))
+	// order of params: on, fixMin, min, fixMax, max
+	plt.SetColumnOptions("Condition", plotcore.On, plotcore.FloatMin, 0, plotcore.FloatMax, 0)
+	plt.SetColumnOptions("DPrime", plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 1)
+	plt.SetColumnOptions("HitRate", plotcore.Off, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("FARate", plotcore.Off, plotcore.FixMin, 0, plotcore.FixMax, 1)
+}
@@ -17,14 +17,31 @@ package main
 
 import (
 	"embed"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"reflect"
+	"strings"
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
+	"cogentcore.org/core/events"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/gifmovie"
+	"github.com/compcogneuro/sims/v2/inspect"
+	"github.com/compcogneuro/sims/v2/netcolor"
+	"github.com/compcogneuro/sims/v2/plottheme"
+	"github.com/compcogneuro/sims/v2/sdt"
+	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
 	"github.com/emer/emergent/v2/emer"
@@ -40,12 +57,13 @@ import (
 	"github.com/emer/etensor/tensor"
 	"github.com/emer/etensor/tensor/stats/clust"
 	"github.com/emer/etensor/tensor/stats/metric"
+	"github.com/emer/etensor/tensor/stats/stats"
 	"github.com/emer/etensor/tensor/table"
 	"github.com/emer/leabra/v2/leabra"
 	"golang.org/x/exp/rand"
 )
 
-//go:embed faces.tsv partial_faces.tsv faces.wts
+//go:embed faces.tsv partial_faces.tsv ambig_faces.tsv faces.wts
 var content embed.FS
 
 //go:embed *.png README.md
@@ -92,6 +110,69 @@ var ParamSets = params.Sets{
 				"Layer.Inhib.Layer.Gi": "1.3",
 			}},
 	},
+	"Learn": {
+		{Sel: "Path", Desc: "error-driven learning, for the optional Train stack",
+			Params: params.Params{
+				"Path.Learn.Learn":        "true",
+				"Path.Learn.XCal.MLrn":    "1",
+				"Path.Learn.XCal.SetLLrn": "true",
+				"Path.Learn.XCal.LLrn":    "0",
+			}},
+	},
+}
+
+// Config has config parameters related to running the sim
+type Config struct {
+
+	// number of cycles in the minus phase of each trial, where the network
+	// settles on the input face.  The plus phase starts after this.
+	MinusCycles int `default:"15" min:"1"`
+
+	// number of cycles in the plus phase, after the minus phase.
+	// The total cycles per trial are MinusCycles + PlusCycles.
+	PlusCycles int `default:"5" min:"1"`
+
+	// add a Train stack, which learns the face categories from random initial
+	// weights with error-driven learning, instead of loading the hand-set
+	// weights, to compare learned vs. hand-set weights.  Takes effect on restart.
+	Train bool
+
+	// number of training epochs, when Train is on
+	NEpochs int `default:"50" min:"1"`
+
+	// options for the activation movies made by RecordGIF
+	GIF GIFConfig `display:"add-fields"`
+
+	// name of the color map for the unit values in the Network view, e.g.,
+	// Viridis for color blindness, or DarkLight (grayscale) for printing
+	ColorMap string `default:"ColdHot"`
+
+	// use the dark theme for the plots (and the rest of the window),
+	// e.g., for presenting in a dark room
+	Dark bool
+}
+
+// Validate checks that the phase cycle counts are positive and that
+// the total number of cycles is in a sensible range, resetting any
+// invalid values to their defaults, and returning an error if so.
+func (cfg *Config) Validate() error {
+	var errs []string
+	if cfg.MinusCycles < 1 {
+		errs = append(errs, fmt.Sprintf("MinusCycles must be > 0, not %d: using 15", cfg.MinusCycles))
+		cfg.MinusCycles = 15
+	}
+	if cfg.PlusCycles < 1 {
+		errs = append(errs, fmt.Sprintf("PlusCycles must be > 0, not %d: using 5", cfg.PlusCycles))
+		cfg.PlusCycles = 5
+	}
+	if tot := cfg.MinusCycles + cfg.PlusCycles; tot > 1000 {
+		errs = append(errs, fmt.Sprintf("MinusCycles + PlusCycles = %d is more than 1000: using 15 + 5", tot))
+		cfg.MinusCycles, cfg.PlusCycles = 15, 5
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("Config: %s", strings.Join(errs, "; "))
+	}
+	return nil
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -101,6 +182,9 @@ var ParamSets = params.Sets{
 // for the fields which provide hints to how things should be displayed).
 type Sim struct {
 
+	// Config contains misc configuration parameters for running the sim
+	Config Config `new-window:"+" display:"no-inline"`
+
 	// the network -- click to view / edit parameters for layers, paths, etc
 	Net *leabra.Network `new-window:"+" display:"no-inline"`
 
@@ -122,6 +206,23 @@ type Sim struct {
 	// the partial patterns to use
 	PartialPatterns *table.Table `new-window:"+" display:"no-inline"`
 
+	// gender-ambiguous patterns, which blend a male and a female face
+	AmbigPatterns *table.Table `new-window:"+" display:"no-inline"`
+
+	// progressively occluded versions of a face, from GenerateOcclusionSeries
+	OcclusionPatterns *table.Table `new-window:"+" display:"no-inline"`
+
+	// category layer (Emotion, Gender, or Identity) of the target category
+	// for the DPrime signal detection stat
+	DPrimeLayer string
+
+	// unit in the DPrimeLayer for the target category of the DPrime stat,
+	// e.g., 0 = male for Gender, 0 = happy for Emotion
+	DPrimeUnit int
+
+	// blend of two faces presented by Morph
+	MorphPatterns *table.Table `new-window:"+" display:"no-inline"`
+
 	// Environments
 	Envs env.Envs `display:"-"`
 
@@ -136,22 +237,44 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// last identity shown by ReconstructIdentity
+	reconID int
+
+	// last faces blended by Morph, used by the Morph slider
+	morphA, morphB int
+
+	// signal detection counts for the DPrime stat over the current Test epoch
+	dprime sdt.Counts
+
+	// cache of input patterns by env and layer name and table row, see InputState
+	inputCache map[inputKey]*tensor.Float32
+
+	// movie being recorded by RecordGIF, nil if not recording
+	gifMovie *gifmovie.Movie
 }
 
 // New creates new blank elements and initializes defaults
 func (ss *Sim) New() {
+	econfig.Config(&ss.Config, "config.toml")
 	ss.Defaults()
 	ss.Net = leabra.NewNetwork("Faces")
 	ss.Params.Config(ParamSets, "", "", ss.Net)
 	ss.Stats.Init()
 	ss.Patterns = &table.Table{}
 	ss.PartialPatterns = &table.Table{}
+	ss.AmbigPatterns = &table.Table{}
+	ss.OcclusionPatterns = &table.Table{}
+	ss.MorphPatterns = &table.Table{}
+	ss.morphB = 1
 	ss.RandSeeds.Init(100) // max 100 runs
 	ss.InitRandSeed(0)
 	ss.Context.Defaults()
 }
 
 func (ss *Sim) Defaults() {
+	ss.DPrimeLayer = "Gender"
+	ss.DPrimeUnit = 0
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -167,6 +290,7 @@ func (ss *Sim) ConfigAll() {
 }
 
 func (ss *Sim) ConfigEnv() {
+	ss.ResetInputCache() // patterns may have changed
 	// Can be called multiple times -- don't re-create
 	var tst *env.FixedTable
 	if len(ss.Envs) == 0 {
@@ -182,6 +306,17 @@ func (ss *Sim) ConfigEnv() {
 
 	// note: names must be in place when adding
 	ss.Envs.Add(tst)
+
+	if ss.Config.Train {
+		trn, ok := ss.Envs.ByMode(etime.Train).(*env.FixedTable)
+		if !ok {
+			trn = &env.FixedTable{}
+		}
+		trn.Name = etime.Train.String()
+		trn.Config(table.NewIndexView(ss.Patterns))
+		trn.Init(0)
+		ss.Envs.Add(trn)
+	}
 }
 
 func (ss *Sim) ConfigNet(net *leabra.Network) {
@@ -214,14 +349,56 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 	ss.InitWeights(net)
 }
 
-// InitWeights initializes weights to digit 8
+// InitWeights initializes weights to the hand-set face weights,
+// or leaves them random if they are to be learned with Config.Train.
 func (ss *Sim) InitWeights(net *leabra.Network) {
 	net.InitWeights()
-	net.OpenWeightsFS(content, "faces.wts")
+	if !ss.Config.Train {
+		net.OpenWeightsFS(content, "faces.wts")
+	}
 }
 
 func (ss *Sim) ApplyParams() {
 	ss.Params.SetAll()
+	if ss.Config.Train {
+		ss.Params.SetAllSheet("Learn")
+	}
+	if ss.Loops != nil {
+		ss.UpdateCycles()
+	}
+}
+
+// UpdateCycles applies the Config phase cycle counts to the Cycle loops,
+// setting the number of cycles per trial and the timing of the minus and
+// plus phase events, as in ConfigLoops, so that they can be changed during
+// a session without rebuilding the loops.  The Quarter1 and Quarter2 events
+// keep their standard timing, as set by leabra.LooperStdPhases.
+func (ss *Sim) UpdateCycles() {
+	errors.Log(ss.Config.Validate())
+	minus := ss.Config.MinusCycles
+	evs := map[string]int{"MinusPhase:End": minus, "PlusPhase:Start": minus}
+	for _, stack := range ss.Loops.Stacks {
+		cyc := stack.Loops[etime.Cycle]
+		cyc.Counter.Max = minus + ss.Config.PlusCycles
+		for nm, at := range evs {
+			if ev := cyc.EventByName(nm); ev != nil {
+				ev.AtCounter = at
+			}
+		}
+	}
+}
+
+// SetCycles sets the number of cycles in the minus and plus phases of each
+// trial, and re-initializes the sim so that the new timing takes effect
+// from the start of the next trial.  Shorter settling in the minus phase
+// shows how much time the network needs to converge on each face.
+func (ss *Sim) SetCycles(minusCycles, plusCycles int) { //types:add
+	ss.Config.MinusCycles = minusCycles
+	ss.Config.PlusCycles = plusCycles
+	ss.Init()
+	if ss.GUI.SimForm != nil {
+		ss.GUI.SimForm.Update()
+	}
 }
 
 // SetInput sets whether the input to the network comes in bottom-up
@@ -246,16 +423,91 @@ func (ss *Sim) SetInput(topDown bool) { //types:add
 
 // SetPatterns selects which patterns to present: full or partial faces
 func (ss *Sim) SetPatterns(partial bool) { //types:add
-	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
 	if partial {
-		ev.Table = table.NewIndexView(ss.PartialPatterns)
-		ev.Init(0)
+		ss.SetPatternsTable(ss.PartialPatterns)
 	} else {
-		ev.Table = table.NewIndexView(ss.Patterns)
-		ev.Init(0)
+		ss.SetPatternsTable(ss.Patterns)
+	}
+}
+
+// SetAmbigPatterns selects the gender-ambiguous faces, which average the
+// inputs of a male and a female face, to show graded gender categorization.
+// Use SetPatterns to go back to the full or partial faces.
+func (ss *Sim) SetAmbigPatterns() { //types:add
+	ss.SetPatternsTable(ss.AmbigPatterns)
+}
+
+// SetPatternsTable sets the Test env to present the given patterns.
+func (ss *Sim) SetPatternsTable(dt *table.Table) {
+	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	ss.ResetInputCache()
+	ev.Table = table.NewIndexView(dt)
+	ev.Init(0)
+	// keep the Test trial loop in sync with the newly selected table
+	if ss.Loops != nil {
+		ss.Loops.Stacks[etime.Test].Loops[etime.Trial].Counter.Max = ev.Table.Len()
 	}
 }
 
+// ReconstructIdentity clamps the given Identity unit (0-9) on top-down,
+// with no other input, and settles the network for the standard number of
+// test cycles, so that the Input layer shows the network's "mental image"
+// of that person, in the Network view. See NextIdentity to step through them.
+func (ss *Sim) ReconstructIdentity(id int) { //types:add
+	net := ss.Net
+	ctx := &ss.Context
+	iden := net.LayerByName("Identity")
+	if id < 0 || id >= iden.Shape.Len() {
+		errors.Log(fmt.Errorf("ReconstructIdentity: id %d out of range 0-%d", id, iden.Shape.Len()-1))
+		return
+	}
+	ss.reconID = id
+	// only Identity is clamped: all other layers settle freely
+	types := make([]leabra.LayerTypes, len(net.Layers))
+	for i, ly := range net.Layers {
+		types[i] = ly.Type
+		ly.Type = leabra.CompareLayer
+	}
+	iden.Type = leabra.InputLayer
+	pat := tensor.NewFloat32(iden.Shape.Sizes)
+	pat.Values[id] = 1
+	net.InitExt()
+	iden.ApplyExt(pat)
+	net.InitActs()
+	net.AlphaCycInit(false)
+	ctx.AlphaCycStart()
+	ncyc := ss.Loops.Stacks[etime.Test].Loops[etime.Cycle].Counter.Max
+	for range ncyc {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	for i, ly := range net.Layers {
+		ly.Type = types[i]
+	}
+	ss.ViewUpdate.Text = fmt.Sprintf("Reconstruct Identity: %d %s", id, ss.IdentityName(id))
+	ss.ViewUpdate.Update()
+}
+
+// NextIdentity reconstructs the next Identity after the last one shown by
+// ReconstructIdentity, wrapping around after the last unit.
+func (ss *Sim) NextIdentity() { //types:add
+	ss.ReconstructIdentity((ss.reconID + 1) % ss.Net.LayerByName("Identity").Shape.Len())
+}
+
+// IdentityName returns the name of the person for the given Identity unit,
+// from the first face pattern with that identity, or "" if there is none.
+func (ss *Sim) IdentityName(id int) string {
+	dt := ss.Patterns
+	for r := 0; r < dt.Rows; r++ {
+		if dt.TensorFloat1D("Identity", r, id) == 1 {
+			nm := dt.StringValue("Name", r)
+			nm, _, _ = strings.Cut(nm, "_")
+			return nm
+		}
+	}
+	return ""
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // 	    Init, utils
 
@@ -264,6 +516,7 @@ func (ss *Sim) SetPatterns(partial bool) { //types:add
 func (ss *Sim) Init() {
 	ss.Loops.ResetCounters()
 	ss.InitRandSeed(0)
+	ss.ResetInputCache()
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
 	ss.NewRun()
@@ -271,6 +524,12 @@ func (ss *Sim) Init() {
 	ss.ViewUpdate.Update()
 }
 
+// TestInit initializes the Test env for testing the trained network
+// with Config.Train, without initializing the weights as Init does.
+func (ss *Sim) TestInit() {
+	ss.Envs.ByMode(etime.Test).Init(0)
+}
+
 // InitRandSeed initializes the random seed based on current training run number
 func (ss *Sim) InitRandSeed(run int) {
 	ss.RandSeeds.Set(run)
@@ -283,15 +542,31 @@ func (ss *Sim) ConfigLoops() {
 
 	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
 	ntrls := ev.Table.Len()
+	errors.Log(ss.Config.Validate())
+	cycles := ss.Config.MinusCycles + ss.Config.PlusCycles
+
+	if ss.Config.Train {
+		ls.AddStack(etime.Train).
+			AddTime(etime.Run, 1).
+			AddTime(etime.Epoch, ss.Config.NEpochs).
+			AddTime(etime.Trial, ss.Envs.ByMode(etime.Train).(*env.FixedTable).Table.Len()).
+			AddTime(etime.Cycle, cycles)
+	}
 
 	ls.AddStack(etime.Test).
 		AddTime(etime.Epoch, 1).
 		AddTime(etime.Trial, ntrls).
-		AddTime(etime.Cycle, 20)
+		AddTime(etime.Cycle, cycles)
 
-	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, 15, 19)
+	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, ss.Config.MinusCycles, cycles-1)
 	leabra.LooperSimCycleAndLearn(ls, ss.Net, &ss.Context, &ss.ViewUpdate) // std algo code
-	ls.Stacks[etime.Test].OnInit.Add("Init", func() { ss.Init() })
+	if ss.Config.Train {
+		ls.Stacks[etime.Train].OnInit.Add("Init", func() { ss.Init() })
+		ls.Stacks[etime.Test].OnInit.Add("Init", func() { ss.TestInit() })
+		ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
+	} else {
+		ls.Stacks[etime.Test].OnInit.Add("Init", func() { ss.Init() })
+	}
 
 	for m, _ := range ls.Stacks {
 		stack := ls.Stacks[m]
@@ -299,6 +574,9 @@ func (ss *Sim) ConfigLoops() {
 			ss.ApplyInputs()
 		})
 	}
+	ls.Loop(etime.Test, etime.Cycle).OnEnd.Add("GIFFrame", ss.GIFFrame)
+	ls.Loop(etime.Test, etime.Epoch).OnStart.Add("DPrimeReset", ss.dprime.Reset)
+	ls.Loop(etime.Test, etime.Epoch).OnEnd.Add("MIStats", ss.MIStats)
 
 	/////////////////////////////////////////////
 	// Logging
@@ -314,6 +592,9 @@ func (ss *Sim) ConfigLoops() {
 	leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
 	leabra.LooperUpdatePlots(ls, &ss.GUI)
 	ls.Stacks[etime.Test].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+	if ss.Config.Train {
+		ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+	}
 
 	ss.Loops = ls
 }
@@ -327,18 +608,70 @@ func (ss *Sim) ApplyInputs() {
 	net := ss.Net
 	ev := ss.Envs.ByMode(ctx.Mode).(*env.FixedTable)
 	ev.Step()
-	lays := net.LayersByType(leabra.InputLayer, leabra.CompareLayer)
+	// category layers are targets for learning in training, and only compared in testing
+	for _, lnm := range []string{"Emotion", "Gender", "Identity"} {
+		ly := net.LayerByName(lnm)
+		switch {
+		case ctx.Mode == etime.Train:
+			ly.Type = leabra.TargetLayer
+		case ly.Type == leabra.TargetLayer:
+			ly.Type = leabra.CompareLayer
+		}
+	}
+	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer, leabra.CompareLayer)
 	net.InitExt()
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
+		pats := ss.InputState(ev, ly.Name)
 		if pats != nil {
 			ly.ApplyExt(pats)
 		}
 	}
 }
 
+// InputState returns the pattern for given layer name on the current trial
+// of given env.  The patterns are fixed across epochs, so each one is copied
+// out of the table only once, the first time its row is presented, and the
+// cached copy is used for all later presentations, instead of env.State
+// which allocates a new sub-tensor every time.  The cache is reset by
+// ResetInputCache whenever the patterns change.
+// Returns nil if the env table has no column for the layer.
+func (ss *Sim) InputState(ev *env.FixedTable, lnm string) *tensor.Float32 {
+	key := inputKey{ev.Name, lnm, ev.Row()}
+	if tsr, ok := ss.inputCache[key]; ok {
+		return tsr
+	}
+	col, err := ev.Table.Table.ColumnByName(lnm)
+	if err != nil {
+		return nil
+	}
+	tsr := tensor.NewFloat32(col.Shape().Sizes[1:])
+	st := key.row * tsr.Len()
+	for i := range tsr.Values {
+		tsr.Values[i] = float32(col.Float1D(st + i))
+	}
+	if ss.inputCache == nil {
+		ss.inputCache = make(map[inputKey]*tensor.Float32)
+	}
+	ss.inputCache[key] = tsr
+	return tsr
+}
+
+// inputKey is the key of a cached input pattern, see InputState.
+type inputKey struct {
+	env, layer string
+	row        int
+}
+
+// ResetInputCache clears the cached input patterns used by InputState.
+// Must be called when the patterns presented by the env change:
+// this is done by OpenPatterns, ConfigEnv, SetPatterns and Init,
+// and whenever the patterns are edited in the GUI.
+func (ss *Sim) ResetInputCache() {
+	ss.inputCache = nil
+}
+
 // NewRun intializes a new run of the model, using the TrainEnv.Run counter
 // for the new run value
 func (ss *Sim) NewRun() {
@@ -346,6 +679,11 @@ func (ss *Sim) NewRun() {
 	ss.Envs.ByMode(etime.Test).Init(0)
 	ctx.Reset()
 	ctx.Mode = etime.Test
+	if ss.Config.Train {
+		ss.Envs.ByMode(etime.Train).Init(0)
+		ctx.Mode = etime.Train
+		ss.Logs.ResetLog(etime.Train, etime.Epoch)
+	}
 	ss.InitWeights(ss.Net)
 	ss.InitStats()
 	ss.StatCounters()
@@ -356,14 +694,21 @@ func (ss *Sim) NewRun() {
 //   Patterns
 
 func (ss *Sim) OpenPatterns() {
-	dt := ss.Patterns
-	dt.SetMetaData("name", "Faces")
-	dt.SetMetaData("desc", "Face testing patterns")
-	errors.Log(dt.OpenFS(content, "faces.tsv", table.Tab))
-	dt = ss.PartialPatterns
-	dt.SetMetaData("name", "FacesPartial")
-	dt.SetMetaData("desc", "Patrial face testing patterns")
-	errors.Log(dt.OpenFS(content, "partial_faces.tsv", table.Tab))
+	ss.ResetInputCache()
+	pats := ss.Patterns
+	pats.SetMetaData("name", "Faces")
+	pats.SetMetaData("desc", "Face testing patterns")
+	errors.Log(pats.OpenFS(content, "faces.tsv", table.Tab))
+
+	partial := ss.PartialPatterns
+	partial.SetMetaData("name", "FacesPartial")
+	partial.SetMetaData("desc", "Partial face testing patterns")
+	errors.Log(partial.OpenFS(content, "partial_faces.tsv", table.Tab))
+
+	ambig := ss.AmbigPatterns
+	ambig.SetMetaData("name", "FacesAmbig")
+	ambig.SetMetaData("desc", "Gender-ambiguous face testing patterns, averaging a male and a female face")
+	errors.Log(ambig.OpenFS(content, "ambig_faces.tsv", table.Tab))
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////
@@ -373,6 +718,7 @@ func (ss *Sim) OpenPatterns() {
 // called at start of new run
 func (ss *Sim) InitStats() {
 	ss.Stats.SetString("TrialName", "")
+	ss.Stats.SetFloat("TrlCor", 0)
 }
 
 // StatCounters saves current counters to Stats, so they are available for logging etc
@@ -400,21 +746,270 @@ func (ss *Sim) NetViewCounters(tm etime.Times) {
 // TrialStats computes the trial-level statistics.
 // Aggregation is done directly from log data.
 func (ss *Sim) TrialStats() {
+	cor := 1.0
+	for _, lnm := range []string{"Emotion", "Gender", "Identity"} {
+		ly := ss.Net.LayerByName(lnm)
+		if maxUnit(ly, "ActM") != maxUnit(ly, "Targ") {
+			cor = 0
+		}
+	}
+	ss.Stats.SetFloat("TrlCor", cor)
+}
+
+// maxUnit returns the index of the unit in given layer
+// with the largest value of the given variable.
+func maxUnit(ly *leabra.Layer, varNm string) int {
+	vi, err := ly.UnitVarIndex(varNm)
+	if errors.Log(err) != nil {
+		return 0
+	}
+	mi, mx := 0, float32(-1)
+	for ui := range ly.Neurons {
+		if v := ly.UnitValue1D(vi, ui, 0); v > mx {
+			mi, mx = ui, v
+		}
+	}
+	return mi
+}
+
+// Harmony computes the harmony (excitatory net input Ge * Act)
+func (ss *Sim) Harmony(nt *leabra.Network) float32 {
+	harm := float32(0)
+	nu := 0
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		for i := range ly.Neurons {
+			nrn := &(ly.Neurons[i])
+			harm += nrn.Ge * nrn.Act
+			nu++
+		}
+	}
+	if nu > 0 {
+		harm /= float32(nu)
+	}
+	return harm
+}
+
+// GenderErr returns 1 if the most active Gender unit is not the target gender,
+// and 0 if it is. For ambiguous faces, where both genders have the same target
+// value, there is no single correct answer, so it returns NaN, which is
+// skipped when computing the mean over trials.
+func (ss *Sim) GenderErr() float64 {
+	ly := ss.Net.LayerByName("Gender")
+	act0, act1 := ly.Neurons[0].Act, ly.Neurons[1].Act
+	targ0, targ1 := ly.Neurons[0].Targ, ly.Neurons[1].Targ
+	if targ0 == targ1 {
+		return math.NaN()
+	}
+	if (act0 > act1) == (targ0 > targ1) {
+		return 0
+	}
+	return 1
+}
+
+// Entropy returns the entropy, in bits, of the distribution of activity
+// over the units of the given layer, normalized to sum to 1.
+// Low entropy means one clear winner, i.e., a confident decision,
+// while high entropy means an ambiguous pattern, up to log2 of the
+// number of units when all units are equally active.
+func (ss *Sim) Entropy(lnm string) float32 {
+	ly := ss.Net.LayerByName(lnm)
+	sum := float32(0)
+	for i := range ly.Neurons {
+		sum += ly.Neurons[i].Act
+	}
+	if sum <= 0 {
+		return 0
+	}
+	ent := float32(0)
+	for i := range ly.Neurons {
+		p := ly.Neurons[i].Act / sum
+		if p > 0 {
+			ent -= p * math32.Log2(p)
+		}
+	}
+	return ent
+}
+
+// IdentityWeights copies the weights from the Input layer into each
+// Identity unit into the IdentityWeights stats tensor, shaped as a
+// 1 x Identity units grid of Input-shaped images.
+func (ss *Sim) IdentityWeights() *tensor.Float32 {
+	inp := ss.Net.LayerByName("Input")
+	iden := ss.Net.LayerByName("Identity")
+	isz := inp.Shape.Len()
+	nid := iden.Shape.Len()
+	wt := ss.Stats.F32Tensor("IdentityWeights")
+	wt.SetShape([]int{1, nid, inp.Shape.DimSize(0), inp.Shape.DimSize(1)})
+	for ui := 0; ui < nid; ui++ {
+		vls := wt.Values[ui*isz : (ui+1)*isz]
+		inp.SendPathValues(&vls, "Wt", iden, ui, "")
+	}
+	return wt
+}
+
+// ShowIdentityWeights shows the weights from the Input layer into each
+// Identity unit as an image in the IdentityWeights tab, which is the
+// face "template" that each identity unit detects.
+func (ss *Sim) ShowIdentityWeights() { //types:add
+	ss.IdentityWeights()
+	if ss.GUI.Grids == nil {
+		return
+	}
+	ss.GUI.Grid("IdentityWeights").Update()
+}
+
+// InspectLayer shows the current values of the units in the layer of the
+// unit that was last clicked on in the Network view, in a popup table.
+func (ss *Sim) InspectLayer() { //types:add
+	nv := ss.GUI.ViewUpdate.View
+	ly := ss.Net.LayerByName(nv.Data.PathLay)
+	if ly == nil {
+		core.MessageDialog(ss.GUI.Body, "InspectLayer: first click on a unit in the layer to inspect in the Network view")
+		return
+	}
+	inspect.Show(ss.GUI.Body, inspect.LayerTable(ly), "Inspect "+ly.Name)
+}
+
+// SaveIdentityWeightsPNG saves the weights from the Input layer into each
+// Identity unit as a row of grayscale images in a PNG file, with white
+// for a weight of 1 and the bottom of the Input layer at the bottom.
+func (ss *Sim) SaveIdentityWeightsPNG(filename core.Filename) error { //types:add
+	wt := ss.IdentityWeights()
+	const pix = 8 // pixels per input unit, and between images
+	nid, ny, nx := wt.DimSize(1), wt.DimSize(2), wt.DimSize(3)
+	img := image.NewGray(image.Rect(0, 0, nid*(nx+1)*pix+pix, (ny+2)*pix))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{128}), image.Point{}, draw.Src)
+	for ui := 0; ui < nid; ui++ {
+		for y := 0; y < ny; y++ {
+			for x := 0; x < nx; x++ {
+				v := math32.Clamp(wt.Value([]int{0, ui, y, x}), 0, 1)
+				px := pix + ui*(nx+1)*pix + x*pix
+				py := pix + (ny-1-y)*pix
+				r := image.Rect(px, py, px+pix, py+pix)
+				draw.Draw(img, r, image.NewUniform(color.Gray{uint8(255 * v)}), image.Point{}, draw.Src)
+			}
+		}
+	}
+	f, err := os.Create(string(filename))
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, img)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
 }
 
 //////////////////////////////////////////////////////////////////////////////
 // 		Logging
 
 func (ss *Sim) ConfigLogs() {
-	ss.Logs.AddCounterItems(etime.Trial, etime.Cycle)
+	if ss.Config.Train {
+		ss.Logs.AddCounterItems(etime.Epoch, etime.Trial, etime.Cycle)
+		ss.Logs.AddStatStringItem(etime.Train, etime.Trial, "TrialName")
+		// proportion of training trials with all three categories correct
+		ss.Logs.AddItem(&elog.Item{
+			Name:   "PctCor",
+			Type:   reflect.Float64,
+			FixMin: true,
+			FixMax: true,
+			Range:  minmax.F32{Max: 1},
+			Write: elog.WriteMap{
+				etime.Scope(etime.Train, etime.Trial): func(ctx *elog.Context) {
+					ctx.SetStatFloat("TrlCor")
+				}, etime.Scope(etime.Train, etime.Epoch): func(ctx *elog.Context) {
+					ctx.SetAgg(ctx.Mode, etime.Trial, stats.Mean)
+				}}})
+	} else {
+		ss.Logs.AddCounterItems(etime.Trial, etime.Cycle)
+	}
 	ss.Logs.AddStatStringItem(etime.Test, etime.Trial, "TrialName")
 
 	ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "InputLayer", "CompareLayer")
 
+	// Harmony is computed from the current network state at the Cycle and
+	// Trial levels, rather than aggregated from the level below, so it is
+	// valid even though Cycle rows are not logged.
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "Harmony",
+		Type:   reflect.Float64,
+		FixMin: true,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Cycle): func(ctx *elog.Context) {
+				ctx.SetFloat32(ss.Harmony(ss.Net))
+			}, etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetFloat32(ss.Harmony(ss.Net))
+			}, etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, stats.Mean)
+			}}})
+
+	// GenderErr is NaN for ambiguous faces, which are left out of the mean
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "GenderErr",
+		Type:   reflect.Float64,
+		FixMin: true,
+		FixMax: true,
+		Range:  minmax.F32{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetFloat64(ss.GenderErr())
+			}, etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, etime.Trial, stats.Mean)
+			}}})
+
+	// entropy of each output layer, as a measure of decision ambiguity
+	for _, lnm := range ss.Net.LayersByType(leabra.CompareLayer) {
+		ss.Logs.AddItem(&elog.Item{
+			Name:   lnm + "_Entropy",
+			Type:   reflect.Float64,
+			FixMin: true,
+			Write: elog.WriteMap{
+				etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+					ctx.SetFloat32(ss.Entropy(lnm))
+				}, etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+					ctx.SetAgg(ctx.Mode, etime.Trial, stats.Mean)
+				}}})
+	}
+
+	// mutual information between the inputs and each category layer
+	for _, lnm := range ss.Net.LayersByType(leabra.CompareLayer) {
+		ss.Logs.AddItem(&elog.Item{
+			Name:   lnm + "_MI",
+			Type:   reflect.Float64,
+			FixMin: true,
+			Write: elog.WriteMap{
+				etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+					ctx.SetFloat64(ss.InputMI(lnm))
+				}}})
+	}
+
+	// signal detection sensitivity for the DPrimeUnit target category
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "DPrime",
+		Type:   reflect.Float64,
+		FixMin: true,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetFloat64(ss.dprime.DPrime())
+			}}})
+
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
 	ss.Logs.NoPlot(etime.Test, etime.Cycle)
-	ss.Logs.PlotItems("Emotion_Act", "Gender_Act", "Identity_Act")
+	ss.Logs.PlotItems("Emotion_Act", "Gender_Act", "Identity_Act", "Harmony", "Identity_Entropy")
+	if ss.Config.Train {
+		ss.Logs.NoPlot(etime.Train, etime.Cycle)
+		ss.Logs.PlotItems("PctCor")
+	}
+
+	ss.ConfigMorph(ss.Logs.MiscTable("Morph"))
+	ss.ConfigCompletion(ss.Logs.MiscTable("Completion"))
+	ss.ConfigDPrime(ss.Logs.MiscTable("DPrime"))
+	ss.ConfigMI(ss.Logs.MiscTable("MI"))
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -435,6 +1030,9 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 	case time == etime.Trial:
 		ss.TrialStats()
 		ss.StatCounters()
+		if mode == etime.Test {
+			ss.DPrimeTrial() // only here, as TrialStats is also called for the NetView
+		}
 	}
 
 	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
@@ -560,12 +1158,17 @@ func (ss *Sim) ConfigNetView(nv *netview.NetView) {
 // ConfigGUI configures the Cogent Core GUI interface for this simulation.
 func (ss *Sim) ConfigGUI() {
 	title := "Faces"
+	plottheme.Init(ss.Config.Dark)
 	ss.GUI.MakeBody(ss, "faces", title, `This project explores how sensory inputs (in this case simple cartoon faces) can be categorized in multiple different ways, to extract the relevant information and collapse across the irrelevant. It allows you to explore both bottom-up processing from face image to categories, and top-down processing from category values to face images (imagery), including the ability to dynamically iterate both bottom-up and top-down to cleanup partial inputs (partially occluded face images). See <a href="https://github.com/compcogneuro/sims/blob/main/ch3/faces/README.md">README.md on GitHub</a>.</p>`, readme)
 	ss.GUI.CycleUpdateInterval = 10
+	ss.GUI.SimForm.OnChange(func(e events.Event) {
+		ss.ResetInputCache() // patterns may have been edited
+	})
 
 	nv := ss.GUI.AddNetView("Network")
 	nv.Options.MaxRecs = 300
 	nv.Options.Raster.Max = 100
+	errors.Log(netcolor.Set(nv, ss.Config.ColorMap))
 	nv.SetNet(ss.Net)
 	ss.ViewUpdate.Config(nv, etime.Cycle, etime.Cycle)
 	ss.GUI.ViewUpdate = &ss.ViewUpdate
@@ -581,6 +1184,14 @@ func (ss *Sim) ConfigGUI() {
 	ss.GUI.AddMiscPlotTab("ProjectionRandom")
 	ss.GUI.AddMiscPlotTab("ProjectionEmoteGend")
 
+	wgv := ss.GUI.AddGridTab("IdentityWeights")
+	wgv.SetTensor(ss.IdentityWeights())
+
+	ss.ConfigMIPlot()
+	ss.ConfigDPrimePlot()
+	ss.ConfigCompletionPlot()
+	ss.ConfigMorphPlot()
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -597,6 +1208,14 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			core.CallFunc(ss.GUI.Body, ss.SetInput)
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Set Cycles",
+		Icon:    icons.Update,
+		Tooltip: "set the number of cycles in the minus (settling) and plus phases of each trial",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.SetCycles)
+		},
+	})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Set Patterns",
 		Icon:    icons.Image,
 		Tooltip: "set which set of patterns to present: full or partial faces",
@@ -605,6 +1224,71 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			core.CallFunc(ss.GUI.Body, ss.SetPatterns)
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reconstruct Identity",
+		Icon:    icons.Image,
+		Tooltip: "clamps a single Identity unit top-down and settles, showing the network's mental image of that person in the Input layer",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ReconstructIdentity)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Next Identity",
+		Icon:    icons.Image,
+		Tooltip: "reconstructs the next Identity after the last one shown",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.NextIdentity()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Ambiguous Faces",
+		Icon:    icons.Image,
+		Tooltip: "present gender-ambiguous faces, which blend a male and a female face -- use Set Patterns to go back",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.SetAmbigPatterns()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "DPrime",
+		Icon:    icons.Image,
+		Tooltip: "tests the full and partial faces, and plots the signal detection sensitivity d' for the target category (DPrimeUnit of DPrimeLayer) in each in the DPrime Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.DPrimeConditions()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Occlusion Series",
+		Icon:    icons.Image,
+		Tooltip: "makes progressively occluded versions of a face (row of Patterns), with the given proportions of its pixels turned off, and presents them -- use Set Patterns to go back",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.GenerateOcclusionSeries)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Test Occlusion",
+		Icon:    icons.Image,
+		Tooltip: "tests the occlusion series, and plots how well the network completes the occluded pixels and the identity at each level in the Completion Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.TestOcclusionSeries()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Morph",
+		Icon:    icons.Image,
+		Tooltip: "present a blend of two faces (rows of Patterns), from t = 0 (first face) to 1 (second face) -- use Set Patterns to go back",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.Morph)
+		},
+	})
+	ss.MakeMorphSlider(p)
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Morph Sweep",
+		Icon:    icons.Image,
+		Tooltip: "present blends of two faces over the given number of steps from t = 0 to 1, and plot the entropy of each category layer in the Morph Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.MorphSweep)
+		},
+	})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Cluster Plot",
 		Icon:    icons.Image,
 		Tooltip: "tests all the patterns and generates cluster plots and projections onto different dimensions",
@@ -613,7 +1297,40 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.ClusterPlots()
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Identity Weights",
+		Icon:    icons.Image,
+		Tooltip: "shows the weights from the Input into each Identity unit as an image, in the IdentityWeights tab",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.ShowIdentityWeights()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Inspect Layer",
+		Icon:    icons.Search,
+		Tooltip: "shows the exact current values of the units in the layer of the unit last clicked on in the Network view, in a popup table",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.InspectLayer()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Save Identity Weights",
+		Icon:    icons.Save,
+		Tooltip: "saves the weights from the Input into each Identity unit as a grid of images in a PNG file",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.SaveIdentityWeightsPNG)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Record GIF",
+		Icon:    icons.Movie,
+		Tooltip: "runs the next test trial and saves the activity on each cycle as an animated GIF, with options in Config.GIF",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.RecordGIF)
+		},
+	})
 	tree.Add(p, func(w *core.Separator) {})
+	plottheme.AddSwitch(p, &ss.Config.Dark)
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "README",
 		Icon:    icons.FileMarkdown,
 		Tooltip: "Opens your browser on the README file that contains instructions for how to run this model.",
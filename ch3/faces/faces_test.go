@@ -0,0 +1,148 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/compcogneuro/sims/v2/simtest"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// newTestSim returns a configured Sim, without the GUI.
+func newTestSim(t testing.TB) *Sim {
+	ss := &Sim{}
+	simtest.Config(t, ss)
+	return ss
+}
+
+// TestHarmony checks the Harmony stat at each level of the Test logs:
+// computed from the network state at the Trial level, averaged over
+// trials at the Epoch level, and not logged at the Cycle level.
+func TestHarmony(t *testing.T) {
+	ss := newTestSim(t)
+	ss.Loops.ResetAndRun(etime.Test)
+
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	if trl.Rows == 0 {
+		t.Fatal("no Test Trial rows")
+	}
+	sum := 0.0
+	for r := range trl.Rows {
+		h := trl.Float("Harmony", r)
+		if !(h > 0) {
+			t.Errorf("Trial row %d: Harmony = %g, want > 0", r, h)
+		}
+		sum += h
+	}
+	// the network is unchanged since the last trial was logged
+	if h, want := trl.Float("Harmony", trl.Rows-1), float64(ss.Harmony(ss.Net)); math.Abs(h-want) > 1.0e-6 {
+		t.Errorf("last Trial Harmony = %g, want %g from the network", h, want)
+	}
+
+	epc := ss.Logs.Table(etime.Test, etime.Epoch)
+	if epc.Rows != 1 {
+		t.Fatalf("Test Epoch rows = %d, want 1", epc.Rows)
+	}
+	if h, want := epc.Float("Harmony", 0), sum/float64(trl.Rows); math.Abs(h-want) > 1.0e-6 {
+		t.Errorf("Epoch Harmony = %g, want the Trial mean %g", h, want)
+	}
+
+	// Log returns early at the Cycle level, so there are no rows to aggregate
+	cyc := ss.Logs.Table(etime.Test, etime.Cycle)
+	if cyc.Rows != 0 {
+		t.Errorf("Test Cycle rows = %d, want 0", cyc.Rows)
+	}
+	ss.Log(etime.Test, etime.Cycle)
+	if cyc.Rows != 0 {
+		t.Errorf("after Log at Cycle, Test Cycle rows = %d, want 0", cyc.Rows)
+	}
+	// the Cycle item reads the network directly, if a row is logged
+	ss.Logs.LogRow(etime.Test, etime.Cycle, 0)
+	if h, want := cyc.Float("Harmony", 0), float64(ss.Harmony(ss.Net)); math.Abs(h-want) > 1.0e-6 {
+		t.Errorf("Cycle Harmony = %g, want %g from the network", h, want)
+	}
+}
+
+// TestSetPatterns checks that toggling the partial patterns changes
+// the table of the Test env, and the number of Test trials with it.
+func TestSetPatterns(t *testing.T) {
+	ss := newTestSim(t)
+	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	ntrl := &ss.Loops.Stacks[etime.Test].Loops[etime.Trial].Counter
+	for _, partial := range []bool{true, false, true} {
+		want := ss.Patterns
+		if partial {
+			want = ss.PartialPatterns
+		}
+		ss.SetPatterns(partial)
+		if ev.Table.Table != want {
+			t.Errorf("SetPatterns(%v): Test env table is %q, want %q", partial, ev.Table.Table.MetaData["name"], want.MetaData["name"])
+		}
+		if ntrl.Max != want.Rows {
+			t.Errorf("SetPatterns(%v): Test trials = %d, want %d", partial, ntrl.Max, want.Rows)
+		}
+	}
+}
+
+// TestOpenPatterns checks that OpenPatterns loads the full faces into
+// Patterns and the partial faces into PartialPatterns.  Both sets have
+// one row per face, so they are told apart by the partial faces having
+// fewer active Input pixels.
+func TestOpenPatterns(t *testing.T) {
+	ss := newTestSim(t)
+	full, partial := ss.Patterns, ss.PartialPatterns
+	if full == partial {
+		t.Fatal("Patterns and PartialPatterns are the same table")
+	}
+	if full.Rows != 12 || partial.Rows != 12 {
+		t.Fatalf("rows: Patterns %d, PartialPatterns %d, want 12 each", full.Rows, partial.Rows)
+	}
+	sumInput := func(dt *table.Table) float64 {
+		in, err := dt.ColumnByName("Input")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := 0.0
+		for i := range in.Len() {
+			sum += in.Float1D(i)
+		}
+		return sum
+	}
+	if fs, ps := sumInput(full), sumInput(partial); fs != 749 || ps != 509 {
+		t.Errorf("Input sums: Patterns %g, PartialPatterns %g, want 749 and 509", fs, ps)
+	}
+}
+
+// BenchmarkInputState measures InputState for the Input pattern, as done
+// in ApplyInputs, which returns the cached copy of the pattern after the
+// first presentation of each row, without allocating.
+func BenchmarkInputState(b *testing.B) {
+	ss := newTestSim(b)
+	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	ev.Step()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		ss.InputState(ev, "Input")
+	}
+}
+
+// BenchmarkEnvState measures env.State for the Input pattern, which
+// ApplyInputs used before InputState, for comparison: it allocates
+// a new sub-tensor of the table on every call.
+func BenchmarkEnvState(b *testing.B) {
+	ss := newTestSim(b)
+	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	ev.Step()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		ev.State("Input")
+	}
+}
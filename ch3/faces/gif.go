@@ -0,0 +1,164 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/math32"
+	"github.com/compcogneuro/sims/v2/gifmovie"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// GIFConfig has the options for the activation movies made by RecordGIF.
+type GIFConfig struct {
+
+	// frames (cycles) per second in the movie
+	FPS int `default:"10" min:"1" max:"50"`
+
+	// first cycle of the trial to record
+	StartCycle int `default:"0" min:"0"`
+
+	// last cycle of the trial to record; -1 = the last cycle
+	EndCycle int `default:"-1"`
+
+	// size of each unit in pixels
+	UnitSize int `default:"8" min:"1"`
+}
+
+// RecordGIF runs the next Test trial, recording the network state on each
+// cycle as a frame of an animated GIF saved to the given file, to share
+// the settling dynamics.  Each layer is drawn as a grid of units, from the
+// first layer at the bottom up, colored blue (0) to red (1) by the NetView
+// variable (e.g., Act).  The cycles and frame rate are set in Config.GIF.
+func (ss *Sim) RecordGIF(filename core.Filename) { //types:add
+	if ss.GUI.Body == nil {
+		errors.Log(ss.recordGIF(string(filename)))
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		errors.Log(ss.recordGIF(string(filename)))
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) recordGIF(filename string) error {
+	ss.gifMovie = gifmovie.New(ss.Config.GIF.FPS)
+	ss.GUI.StopNow = false
+	ss.Loops.Step(etime.Test, 1, etime.Trial)
+	movie := ss.gifMovie
+	ss.gifMovie = nil
+	if movie.Frames() == 0 {
+		return fmt.Errorf("RecordGIF: no cycles recorded in range %d to %d", ss.Config.GIF.StartCycle, ss.Config.GIF.EndCycle)
+	}
+	return movie.Save(filename)
+}
+
+// GIFFrame adds the current network state as a frame of the movie
+// being recorded by RecordGIF, if the cycle is in the recorded range.
+// Called at the end of every Test cycle.
+func (ss *Sim) GIFFrame() {
+	if ss.gifMovie == nil {
+		return
+	}
+	cfg := &ss.Config.GIF
+	cyc := ss.Loops.Loop(etime.Test, etime.Cycle).Counter
+	end := cfg.EndCycle
+	if end < 0 {
+		end = cyc.Max - 1
+	}
+	if cyc.Cur < cfg.StartCycle || cyc.Cur > end {
+		return
+	}
+	ss.gifMovie.AddFrame(ss.NetImage())
+}
+
+// gifPalette has the background color at index 0,
+// followed by a blue (0) to red (1) value scale.
+var gifPalette = func() color.Palette {
+	pal := color.Palette{color.Gray{0xe8}}
+	for i := range 255 {
+		v := float32(i) / 254
+		pal = append(pal, color.RGBA{uint8(255 * v), uint8(50 * (1 - v)), uint8(255 * (1 - v)), 255})
+	}
+	return pal
+}()
+
+// NetImage returns an image of the current network state on the
+// NetView variable, with each layer as a row of units, for RecordGIF.
+func (ss *Sim) NetImage() *image.Paletted {
+	varNm := "Act"
+	if nv := ss.ViewUpdate.View; nv != nil && nv.Var != "" {
+		varNm = nv.Var
+	}
+	pix := max(ss.Config.GIF.UnitSize, 1)
+	wd, ht := 1, 1 // in units, including a one unit border
+	for _, ly := range ss.Net.Layers {
+		lw, lh := layerXY(ly)
+		wd = max(wd, lw+2)
+		ht += lh + 1
+	}
+	img := image.NewPaletted(image.Rect(0, 0, wd*pix, ht*pix), gifPalette)
+	y0 := ht - 1 // bottom of the current layer, going up
+	for _, ly := range ss.Net.Layers {
+		_, lh := layerXY(ly)
+		vi, _ := ly.UnitVarIndex(varNm)
+		for ui := range ly.Neurons {
+			v := ly.UnitValue1D(vi, ui, 0)
+			if math32.IsNaN(v) {
+				v = 0
+			}
+			ci := 1 + uint8(254*math32.Clamp(v, 0, 1))
+			x, y := unitXY(ly, ui)
+			px := (1 + x) * pix
+			py := (y0 - 1 - y) * pix
+			for yi := py; yi < py+pix-1; yi++ { // leave a gap between units
+				for xi := px; xi < px+pix-1; xi++ {
+					img.SetColorIndex(xi, yi, ci)
+				}
+			}
+		}
+		y0 -= lh + 1
+	}
+	return img
+}
+
+// layerXY returns the size of given layer in units as drawn by NetImage,
+// with a gap of one unit between the pools of 4D layers.
+func layerXY(ly *leabra.Layer) (x, y int) {
+	sz := ly.Shape.Sizes
+	if len(sz) == 4 {
+		return sz[1]*(sz[3]+1) - 1, sz[0]*(sz[2]+1) - 1
+	}
+	if len(sz) == 1 {
+		return sz[0], 1
+	}
+	return sz[len(sz)-1], sz[len(sz)-2]
+}
+
+// unitXY returns the position of given unit within its layer,
+// with a gap of one unit between the pools of 4D layers.
+func unitXY(ly *leabra.Layer, ui int) (x, y int) {
+	sz := ly.Shape.Sizes
+	if len(sz) == 4 {
+		nuy, nux := sz[2], sz[3]
+		pi := ui / (nuy * nux)
+		pu := ui % (nuy * nux)
+		py, px := pi/sz[1], pi%sz[1]
+		return px*(nux+1) + pu%nux, py*(nuy+1) + pu/nux
+	}
+	nux := sz[len(sz)-1]
+	return ui % nux, ui / nux
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"github.com/compcogneuro/sims/v2/mutinf"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/table"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// InputMI returns the mutual information, in bits, between the input
+// patterns (by TrialName) and the responses of the given category layer,
+// binned into its most active unit, over the trials of the current Test
+// epoch.  This is the plug-in estimate, which is biased upward for small
+// numbers of trials: with each face presented once, it is just the entropy
+// of the responses, and it is at most log2 of the number of units
+// (e.g., 1 bit for Gender).  See the mutinf package for details.
+func (ss *Sim) InputMI(lnm string) float64 {
+	x, y := ss.inputResponses(lnm)
+	return mutinf.MI(x, y)
+}
+
+// MIStats records the InputMI of each category layer for the current
+// Test epoch in the MI table and plot, along with the estimated bias of
+// the plug-in estimate, and the maximum possible MI for the layer.
+// Called at the end of each Test epoch.
+func (ss *Sim) MIStats() {
+	dt := ss.Logs.MiscTable("MI")
+	lays := ss.Net.LayersByType(leabra.CompareLayer)
+	dt.SetNumRows(len(lays))
+	for li, lnm := range lays {
+		x, y := ss.inputResponses(lnm)
+		dt.SetFloat("Lay", li, float64(li))
+		dt.SetString("Layer", li, lnm)
+		dt.SetFloat("MI", li, mutinf.MI(x, y))
+		dt.SetFloat("Bias", li, mutinf.Bias(x, y))
+		dt.SetFloat("MaxMI", li, math.Log2(float64(len(ss.Net.LayerByName(lnm).Neurons))))
+	}
+	if plt := ss.GUI.PlotByName("MI"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+}
+
+// inputResponses returns the input pattern index (by TrialName) and the
+// most active unit of the given layer for each trial of the current
+// Test epoch.
+func (ss *Sim) inputResponses(lnm string) (x, y []int) {
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	x = make([]int, trl.Rows)
+	y = make([]int, trl.Rows)
+	names := make(map[string]int)
+	for r := range trl.Rows {
+		nm := trl.StringValue("TrialName", r)
+		if _, ok := names[nm]; !ok {
+			names[nm] = len(names)
+		}
+		x[r] = names[nm]
+		y[r] = maxIndex(trl.Tensor(lnm+"_Act", r))
+	}
+	return x, y
+}
+
+// ConfigMI configures the table of results from MIStats.
+func (ss *Sim) ConfigMI(dt *table.Table) {
+	dt.SetMetaData("name", "MI")
+	dt.SetMetaData("desc", "mutual information between the inputs and each category layer over the last Test epoch")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Lay")
+	dt.AddStringColumn("Layer")
+	dt.AddFloat64Column("MI")
+	dt.AddFloat64Column("Bias")
+	dt.AddFloat64Column("MaxMI")
+}
+
+// ConfigMIPlot configures the plot of the MI table.
+func (ss *Sim) ConfigMIPlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("MI"), "MI Plot")
+	plt.Options.Title = "Mutual Information between Inputs and Category Layers (bits)"
+	plt.Options.XAxis = "Lay"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable("MI"))
+	// order of params: on, fixMin, min, fixMax, max
+	plt.SetColumnOptions("Layer", plotcore.On, plotcore.FloatMin, 0, plotcore.FloatMax, 0)
+	plt.SetColumnOptions("MI", plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 1)
+	plt.SetColumnOptions("Bias", plotcore.Off, plotcore.FixMin, 0, plotcore.FloatMax, 1)
+	plt.SetColumnOptions("MaxMI", plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 1)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/tree"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// Morph presents a blend of the two given face patterns (rows of Patterns),
+// linearly interpolated by t, which is clamped to [0,1]: t = 0 is face a,
+// and t = 1 is face b.  The blend is tested as a single Test trial, and
+// the Test trial log shows the resulting categorization and the entropy of
+// each category layer, which peaks where the categorization flips from
+// one face to the other.  Use the Morph slider in the toolbar to vary t
+// for the last faces, and MorphSweep to plot entropy as a function of t.
+// The input must be bottom-up (see SetInput) for the entropy stats.
+// Use SetPatterns to go back to the standard faces.
+func (ss *Sim) Morph(a, b int, t float64) { //types:add
+	if ss.GUI.Body == nil {
+		errors.Log(ss.morph(a, b, t))
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		errors.Log(ss.morph(a, b, t))
+		ss.GUI.Stopped()
+	}()
+}
+
+// MorphSweep presents the blends of the two given faces for steps+1 values
+// of t from 0 to 1, recording the entropy of each category layer for each
+// blend in the Morph table and plot, which shows the peak ambiguity where
+// the categorization flips, near t = 0.5 for faces that differ in a single
+// category (e.g., the happy and sad faces of the same person).
+func (ss *Sim) MorphSweep(a, b, steps int) { //types:add
+	if ss.GUI.Body == nil {
+		errors.Log(ss.morphSweep(a, b, steps))
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		errors.Log(ss.morphSweep(a, b, steps))
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) morphSweep(a, b, steps int) error {
+	steps = max(steps, 1)
+	dt := ss.Logs.MiscTable("Morph")
+	dt.SetNumRows(0)
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	for i := range steps + 1 {
+		t := float64(i) / float64(steps)
+		if err := ss.morph(a, b, t); err != nil {
+			return err
+		}
+		dt.SetNumRows(i + 1)
+		dt.SetFloat("T", i, t)
+		for _, cnm := range dt.ColumnNames[1:] {
+			dt.SetFloat(cnm, i, trl.Float(cnm, trl.Rows-1))
+		}
+	}
+	if plt := ss.GUI.PlotByName("Morph"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+	return nil
+}
+
+// morph sets the Test env to present the blend of faces a and b
+// from MorphPattern, and tests it.
+func (ss *Sim) morph(a, b int, t float64) error {
+	dt, err := ss.MorphPattern(a, b, t)
+	if err != nil {
+		return err
+	}
+	ss.morphA, ss.morphB = a, b
+	ss.MorphPatterns = dt
+	ss.SetPatternsTable(dt)
+	mode := ss.Loops.Mode
+	ss.GUI.StopNow = false
+	ss.Loops.ResetAndRun(etime.Test)
+	ss.Loops.Mode = mode
+	return nil
+}
+
+// MorphPattern returns a table with a single row having the values of
+// each of the patterns (Input, and the category layers) for face a and b
+// blended as (1-t) * a + t * b, with t clamped to [0,1].
+func (ss *Sim) MorphPattern(a, b int, t float64) (*table.Table, error) {
+	pats := ss.Patterns
+	if a < 0 || a >= pats.Rows || b < 0 || b >= pats.Rows {
+		return nil, fmt.Errorf("Morph: faces %d and %d must be in range 0-%d", a, b, pats.Rows-1)
+	}
+	t = min(max(t, 0), 1)
+	dt := pats.Clone()
+	dt.SetNumRows(1)
+	dt.SetMetaData("name", "FacesMorph")
+	dt.SetMetaData("desc", "Blend of two face patterns")
+	for ci, cl := range pats.Columns {
+		if cl.IsString() {
+			continue
+		}
+		dcl := dt.Columns[ci]
+		ncell := cl.Len() / pats.Rows
+		for ce := range ncell {
+			av := cl.Float1D(a*ncell + ce)
+			bv := cl.Float1D(b*ncell + ce)
+			dcl.SetFloat1D(ce, (1-t)*av+t*bv)
+		}
+	}
+	anm := pats.StringValue("Name", a)
+	bnm := pats.StringValue("Name", b)
+	dt.SetString("Name", 0, fmt.Sprintf("%s-%s_%.2f", anm, bnm, t))
+	return dt, nil
+}
+
+// ConfigMorph configures the table of results from MorphSweep,
+// with the entropy of each category layer for each blend.
+func (ss *Sim) ConfigMorph(dt *table.Table) {
+	dt.SetMetaData("name", "Morph")
+	dt.SetMetaData("desc", "entropy of each category layer as a function of the blend between two faces")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("T")
+	for _, lnm := range []string{"Emotion", "Gender", "Identity"} {
+		dt.AddFloat64Column(lnm + "_Entropy")
+	}
+}
+
+// ConfigMorphPlot configures the plot of the Morph table.
+func (ss *Sim) ConfigMorphPlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("Morph"), "Morph Plot")
+	plt.Options.Title = "Category Entropy by Face Blend"
+	plt.Options.XAxis = "T"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable("Morph"))
+	for _, cnm := range ss.Logs.MiscTable("Morph").ColumnNames[1:] {
+		plt.SetColumnOptions(cnm, plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 1)
+	}
+}
+
+// MakeMorphSlider adds a slider to the toolbar for the t of the Morph
+// between the last two faces given to Morph (initially the first two),
+// which presents the blend each time it is changed.
+func (ss *Sim) MakeMorphSlider(p *tree.Plan) {
+	tree.Add(p, func(w *core.Text) {
+		w.SetText("Morph:")
+	})
+	tree.Add(p, func(w *core.Slider) {
+		w.SetMin(0).SetMax(1).SetStep(0.05)
+		w.SetTooltip("blend between the last two faces given to Morph (initially the first two): 0 = first face, 1 = second face")
+		w.OnChange(func(e events.Event) {
+			ss.Morph(ss.morphA, ss.morphB, float64(w.Value))
+		})
+	})
+}
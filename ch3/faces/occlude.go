@@ -0,0 +1,191 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"cogentcore.org/core/base/errors"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// GenerateOcclusionSeries makes a series of progressively occluded versions
+// of the face in the given row of Patterns, one for each of the given levels,
+// which are the proportions (0-1) of the active Input pixels that are turned
+// off.  The pixels are occluded in a random order that is fixed for each face,
+// so each level occludes the same pixels as the lower levels plus some more,
+// and the series is the same every time.  The series is stored in the
+// OcclusionPatterns, which are set as the Test patterns: use
+// TestOcclusionSeries to test how well the network completes each of them.
+// Use SetPatterns to go back to the standard faces.
+func (ss *Sim) GenerateOcclusionSeries(row int, levels []float64) { //types:add
+	dt, err := ss.OcclusionSeries(row, levels)
+	if errors.Log(err) != nil {
+		return
+	}
+	ss.OcclusionPatterns = dt
+	ss.SetPatternsTable(dt)
+	if ss.GUI.SimForm != nil {
+		ss.GUI.SimForm.Update()
+	}
+}
+
+// OcclusionSeries returns the table of occluded faces for
+// GenerateOcclusionSeries, with the Level of each one, and the
+// Full (unoccluded) Input used to measure completion.
+func (ss *Sim) OcclusionSeries(row int, levels []float64) (*table.Table, error) {
+	pats := ss.Patterns
+	if row < 0 || row >= pats.Rows {
+		return nil, fmt.Errorf("GenerateOcclusionSeries: row %d out of range 0-%d", row, pats.Rows-1)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("GenerateOcclusionSeries: no levels given")
+	}
+	full := pats.Tensor("Input", row)
+	var on []int
+	for i := range full.Len() {
+		if full.Float1D(i) > 0 {
+			on = append(on, i)
+		}
+	}
+	rnd := rand.New(rand.NewSource(int64(row) + 1))
+	rnd.Shuffle(len(on), func(i, j int) { on[i], on[j] = on[j], on[i] })
+
+	dt := pats.Clone()
+	dt.SetMetaData("name", "FacesOccluded")
+	dt.SetMetaData("desc", "Progressively occluded versions of a face")
+	dt.SetNumRows(len(levels))
+	dt.AddFloat64Column("Level")
+	dt.AddFloat32TensorColumn("Full", full.Shape().Sizes)
+	nm := pats.StringValue("Name", row)
+	for r, lev := range levels {
+		lev = min(max(lev, 0), 1)
+		for ci, cl := range pats.Columns {
+			dcl := dt.Columns[ci]
+			ncell := cl.Len() / pats.Rows
+			for ce := range ncell {
+				if cl.IsString() {
+					dcl.SetString1D(r*ncell+ce, cl.String1D(row*ncell+ce))
+				} else {
+					dcl.SetFloat1D(r*ncell+ce, cl.Float1D(row*ncell+ce))
+				}
+			}
+		}
+		dt.SetString("Name", r, fmt.Sprintf("%s_occ%.2f", nm, lev))
+		dt.SetFloat("Level", r, lev)
+		noff := int(math.Round(lev * float64(len(on))))
+		for i := range full.Len() {
+			dt.SetTensorFloat1D("Full", r, i, full.Float1D(i))
+		}
+		for _, i := range on[:noff] {
+			dt.SetTensorFloat1D("Input", r, i, 0)
+		}
+	}
+	return dt, nil
+}
+
+// TestOcclusionSeries tests all of the faces in the series made by
+// GenerateOcclusionSeries, and records how well the network completes each
+// one in the Completion table and plot: the proportion of occluded pixels
+// that are filled in (activity > .5) in the Input layer, and whether the
+// Identity is correct.  The input must be bottom-up (see SetInput).
+func (ss *Sim) TestOcclusionSeries() { //types:add
+	if ss.GUI.Body == nil {
+		errors.Log(ss.testOcclusionSeries())
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		errors.Log(ss.testOcclusionSeries())
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) testOcclusionSeries() error {
+	ots := ss.OcclusionPatterns
+	if ots.Rows == 0 {
+		return fmt.Errorf("TestOcclusionSeries: use GenerateOcclusionSeries first")
+	}
+	ss.SetPatternsTable(ots)
+	mode := ss.Loops.Mode
+	ss.GUI.StopNow = false
+	ss.Loops.ResetAndRun(etime.Test)
+	ss.Loops.Mode = mode
+
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	dt := ss.Logs.MiscTable("Completion")
+	nr := min(trl.Rows, ots.Rows)
+	dt.SetNumRows(nr)
+	for r := range nr {
+		full := ots.Tensor("Full", r)
+		inp := ots.Tensor("Input", r)
+		act := trl.Tensor("Input_Act", r)
+		nocc, nfill := 0, 0
+		for i := range full.Len() {
+			if full.Float1D(i) > 0 && inp.Float1D(i) == 0 {
+				nocc++
+				if act.Float1D(i) > .5 {
+					nfill++
+				}
+			}
+		}
+		comp := 1.0
+		if nocc > 0 {
+			comp = float64(nfill) / float64(nocc)
+		}
+		idcor := 0.0
+		if maxIndex(trl.Tensor("Identity_Act", r)) == maxIndex(ots.Tensor("Identity", r)) {
+			idcor = 1
+		}
+		dt.SetFloat("Level", r, ots.Float("Level", r))
+		dt.SetFloat("Completion", r, comp)
+		dt.SetFloat("IdentCor", r, idcor)
+	}
+	if plt := ss.GUI.PlotByName("Completion"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+	return nil
+}
+
+// maxIndex returns the index of the largest value in the tensor.
+func maxIndex(tsr tensor.Tensor) int {
+	mi, mx := 0, math.Inf(-1)
+	for i := range tsr.Len() {
+		if v := tsr.Float1D(i); v > mx {
+			mi, mx = i, v
+		}
+	}
+	return mi
+}
+
+// ConfigCompletion configures the table of results from TestOcclusionSeries.
+func (ss *Sim) ConfigCompletion(dt *table.Table) {
+	dt.SetMetaData("name", "Completion")
+	dt.SetMetaData("desc", "completion of the occluded pixels and identity as a function of occlusion level")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Level")
+	dt.AddFloat64Column("Completion")
+	dt.AddFloat64Column("IdentCor")
+}
+
+// ConfigCompletionPlot configures the plot of the Completion table.
+func (ss *Sim) ConfigCompletionPlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("Completion"), "Completion Plot")
+	plt.Options.Title = "Pattern Completion by Occlusion Level"
+	plt.Options.XAxis = "Level"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable("Completion"))
+	plt.SetColumnOptions("Completion", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("IdentCor", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+}
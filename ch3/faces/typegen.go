@@ -6,4 +6,8 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "SetInput", Doc: "SetInput sets whether the input to the network comes in bottom-up\n(Input layer) or top-down (Higher-level category layers)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"topDown"}}, {Name: "SetPatterns", Doc: "SetPatterns selects which patterns to present: full or partial faces", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"partial"}}}, Fields: []types.Field{{Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Patterns", Doc: "the patterns to use"}, {Name: "PartialPatterns", Doc: "the partial patterns to use"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "MinusCycles", Doc: "number of cycles in the minus phase of each trial, where the network\nsettles on the input face.  The plus phase starts after this."}, {Name: "PlusCycles", Doc: "number of cycles in the plus phase, after the minus phase.\nThe total cycles per trial are MinusCycles + PlusCycles."}, {Name: "Train", Doc: "add a Train stack, which learns the face categories from random initial\nweights with error-driven learning, instead of loading the hand-set\nweights, to compare learned vs. hand-set weights.  Takes effect on restart."}, {Name: "NEpochs", Doc: "number of training epochs, when Train is on"}, {Name: "GIF", Doc: "options for the activation movies made by RecordGIF"}, {Name: "ColorMap", Doc: "name of the color map for the unit values in the Network view, e.g.,\nViridis for color blindness, or DarkLight (grayscale) for printing"}, {Name: "Dark", Doc: "use the dark theme for the plots (and the rest of the window),\ne.g., for presenting in a dark room"}}})
+
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "DPrimeConditions", Doc: "DPrimeConditions tests the full and partial faces, and records the DPrime\nsignal detection sensitivity for the target category (DPrimeUnit of\nDPrimeLayer) in each condition in the DPrime table and plot, along with\nthe hit and false alarm rates it is computed from.\nThe full faces are presented again at the end.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SetCycles", Doc: "SetCycles sets the number of cycles in the minus and plus phases of each\ntrial, and re-initializes the sim so that the new timing takes effect\nfrom the start of the next trial.  Shorter settling in the minus phase\nshows how much time the network needs to converge on each face.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"minusCycles", "plusCycles"}}, {Name: "SetInput", Doc: "SetInput sets whether the input to the network comes in bottom-up\n(Input layer) or top-down (Higher-level category layers)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"topDown"}}, {Name: "SetPatterns", Doc: "SetPatterns selects which patterns to present: full or partial faces", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"partial"}}, {Name: "SetAmbigPatterns", Doc: "SetAmbigPatterns selects the gender-ambiguous faces, which average the\ninputs of a male and a female face, to show graded gender categorization.\nUse SetPatterns to go back to the full or partial faces.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ReconstructIdentity", Doc: "ReconstructIdentity clamps the given Identity unit (0-9) on top-down,\nwith no other input, and settles the network for the standard number of\ntest cycles, so that the Input layer shows the network's \"mental image\"\nof that person, in the Network view. See NextIdentity to step through them.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"id"}}, {Name: "NextIdentity", Doc: "NextIdentity reconstructs the next Identity after the last one shown by\nReconstructIdentity, wrapping around after the last unit.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ShowIdentityWeights", Doc: "ShowIdentityWeights shows the weights from the Input layer into each\nIdentity unit as an image in the IdentityWeights tab, which is the\nface \"template\" that each identity unit detects.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "InspectLayer", Doc: "InspectLayer shows the current values of the units in the layer of the\nunit that was last clicked on in the Network view, in a popup table.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SaveIdentityWeightsPNG", Doc: "SaveIdentityWeightsPNG saves the weights from the Input layer into each\nIdentity unit as a row of grayscale images in a PNG file, with white\nfor a weight of 1 and the bottom of the Input layer at the bottom.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}, {Name: "RecordGIF", Doc: "RecordGIF runs the next Test trial, recording the network state on each\ncycle as a frame of an animated GIF saved to the given file, to share\nthe settling dynamics.  Each layer is drawn as a grid of units, from the\nfirst layer at the bottom up, colored blue (0) to red (1) by the NetView\nvariable (e.g., Act).  The cycles and frame rate are set in Config.GIF.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}}, {Name: "Morph", Doc: "Morph presents a blend of the two given face patterns (rows of Patterns),\nlinearly interpolated by t, which is clamped to [0,1]: t = 0 is face a,\nand t = 1 is face b.  The blend is tested as a single Test trial, and\nthe Test trial log shows the resulting categorization and the entropy of\neach category layer, which peaks where the categorization flips from\none face to the other.  Use the Morph slider in the toolbar to vary t\nfor the last faces, and MorphSweep to plot entropy as a function of t.\nThe input must be bottom-up (see SetInput) for the entropy stats.\nUse SetPatterns to go back to the standard faces.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"a", "b", "t"}}, {Name: "MorphSweep", Doc: "MorphSweep presents the blends of the two given faces for steps+1 values\nof t from 0 to 1, recording the entropy of each category layer for each\nblend in the Morph table and plot, which shows the peak ambiguity where\nthe categorization flips, near t = 0.5 for faces that differ in a single\ncategory (e.g., the happy and sad faces of the same person).", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"a", "b", "steps"}}, {Name: "GenerateOcclusionSeries", Doc: "GenerateOcclusionSeries makes a series of progressively occluded versions\nof the face in the given row of Patterns, one for each of the given levels,\nwhich are the proportions (0-1) of the active Input pixels that are turned\noff.  The pixels are occluded in a random order that is fixed for each face,\nso each level occludes the same pixels as the lower levels plus some more,\nand the series is the same every time.  The series is stored in the\nOcclusionPatterns, which are set as the Test patterns: use\nTestOcclusionSeries to test how well the network completes each of them.\nUse SetPatterns to go back to the standard faces.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"row", "levels"}}, {Name: "TestOcclusionSeries", Doc: "TestOcclusionSeries tests all of the faces in the series made by\nGenerateOcclusionSeries, and records how well the network completes each\none in the Completion table and plot: the proportion of occluded pixels\nthat are filled in (activity > .5) in the Input layer, and whether the\nIdentity is correct.  The input must be bottom-up (see SetInput).", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Fields: []types.Field{{Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Patterns", Doc: "the patterns to use"}, {Name: "PartialPatterns", Doc: "the partial patterns to use"}, {Name: "AmbigPatterns", Doc: "gender-ambiguous patterns, which blend a male and a female face"}, {Name: "OcclusionPatterns", Doc: "progressively occluded versions of a face, from GenerateOcclusionSeries"}, {Name: "DPrimeLayer", Doc: "category layer (Emotion, Gender, or Identity) of the target category\nfor the DPrime signal detection stat"}, {Name: "DPrimeUnit", Doc: "unit in the DPrimeLayer for the target category of the DPrime stat,\ne.g., 0 = male for Gender, 0 = happy for Emotion"}, {Name: "MorphPatterns", Doc: "blend of two faces presented by Morph"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+
+var _ = types.AddType(&types.Type{Name: "main.GIFConfig", IDName: "gif-config", Doc: "GIFConfig has the options for the activation movies made by RecordGIF.", Fields: []types.Field{{Name: "FPS", Doc: "frames (cycles) per second in the movie"}, {Name: "StartCycle", Doc: "first cycle of the trial to record"}, {Name: "EndCycle", Doc: "last cycle of the trial to record; -1 = the last cycle"}, {Name: "UnitSize", Doc: "size of each unit in pixels"}}})
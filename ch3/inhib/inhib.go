@@ -143,6 +143,20 @@ type Sim struct {
 	// connection-based inhibition when using the FFFBInhib computed inbhition.
 	FmInhibWtScaleAbs float32 `default:"1"`
 
+	// split the inhibitory interneurons into two pools with different time constants:
+	// the fast Inhib pool, using InhibGTau, and the slow InhibSlow pool, using
+	// SlowInhibGTau, which share the inhibition of the Hidden layer.
+	// The networks are rebuilt with or without the slow pools on Init.
+	TwoInhibPools bool `default:"false"`
+
+	// time constant (tau) for updating G conductances into the slow InhibSlow neurons,
+	// when using TwoInhibPools.
+	SlowInhibGTau float32 `default:"100" min:"1" step:"1"`
+
+	// strength of the inhibition from the slow pool into the Hidden layer,
+	// relative to the fast pool, when using TwoInhibPools.
+	SlowInhibRel float32 `default:"1" min:"0" step:"0.1"`
+
 	// the feedforward network -- click to view / edit parameters for layers, paths, etc
 	NetFF *leabra.Network `new-window:"+" display:"no-inline"`
 
@@ -208,6 +222,9 @@ func (ss *Sim) Defaults() {
 	ss.HiddenGTau = 40
 	ss.InhibGTau = 20
 	ss.FmInhibWtScaleAbs = 1
+	ss.TwoInhibPools = false
+	ss.SlowInhibGTau = 100
+	ss.SlowInhibRel = 1
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -259,6 +276,12 @@ func (ss *Sim) ConfigNetFF(net *leabra.Network) {
 
 	inh.PlaceRightOf(hid, 2)
 
+	if ss.TwoInhibPools {
+		slow := ss.AddSlowInhib(net, "InhibSlow", hid, inh)
+		net.ConnectLayers(hid, slow, full, leabra.BackPath)
+		net.ConnectLayers(inp, slow, full, leabra.ForwardPath)
+	}
+
 	net.Build()
 	net.Defaults()
 	ss.ApplyParams(net)
@@ -295,12 +318,56 @@ func (ss *Sim) ConfigNetBidir(net *leabra.Network) {
 	inh2.PlaceRightOf(hid2, 2)
 	hid2.PlaceAbove(hid)
 
+	if ss.TwoInhibPools {
+		slow := ss.AddSlowInhib(net, "InhibSlow", hid, inh)
+		net.ConnectLayers(hid, slow, full, leabra.BackPath)
+		net.ConnectLayers(inp, slow, full, leabra.ForwardPath)
+		net.ConnectLayers(hid2, slow, full, leabra.ForwardPath)
+		slow2 := ss.AddSlowInhib(net, "InhibSlow2", hid2, inh2)
+		net.ConnectLayers(hid, slow2, full, leabra.ForwardPath)
+		net.ConnectLayers(hid2, slow2, full, leabra.BackPath)
+	}
+
 	net.Build()
 	net.Defaults()
 	ss.ApplyParams(net)
 	ss.InitWeights(net)
 }
 
+// AddSlowInhib adds a slow pool of inhibitory interneurons with given name,
+// for TwoInhibPools, which inhibits the given hidden layer and itself,
+// placed to the right of the fast inhib pool.  The excitatory inputs into
+// the pool, which should match those of the fast pool, must be connected
+// separately.
+func (ss *Sim) AddSlowInhib(net *leabra.Network, name string, hid, inh *leabra.Layer) *leabra.Layer {
+	slow := net.AddLayer2D(name, 10, 2, leabra.SuperLayer)
+	slow.AddClass("InhibLay")
+	full := paths.NewFull()
+	net.ConnectLayers(slow, hid, full, leabra.InhibPath)
+	net.ConnectLayers(slow, slow, full, leabra.InhibPath)
+	slow.PlaceRightOf(inh, 2)
+	return slow
+}
+
+// UpdateNets rebuilds the networks if TwoInhibPools has changed since they
+// were built.  The slow inhib pools are only in the networks when they are
+// used, as their weights would otherwise change the random initial weights
+// of the other paths.
+func (ss *Sim) UpdateNets() {
+	_, err := ss.NetFF.EmerLayerByName("InhibSlow")
+	if (err == nil) == ss.TwoInhibPools {
+		return
+	}
+	ss.NetFF.Layers = nil
+	ss.NetBidir.Layers = nil
+	ss.ConfigNetFF(ss.NetFF)
+	ss.ConfigNetBidir(ss.NetBidir)
+	if ss.NetviewFF != nil {
+		ss.NetviewFF.SetNet(ss.NetFF)
+		ss.NetviewBidir.SetNet(ss.NetBidir)
+	}
+}
+
 // InitWeights initializes weights to digit 8
 func (ss *Sim) InitWeights(net *leabra.Network) {
 	net.InitWeights()
@@ -358,6 +425,41 @@ func (ss *Sim) ApplyParams(net *leabra.Network) {
 		inh = net.LayerByName("Inhib")
 		ff = errors.Log1(inh.RecvPathBySendName("Hidden2")).(*leabra.Path)
 		ff.WtScale.Rel = ffinhsc
+		ss.ApplySlowInhib(net, "Hidden2", "Inhib2", "InhibSlow2")
+	}
+	ss.ApplySlowInhib(net, "Hidden", "Inhib", "InhibSlow")
+}
+
+// ApplySlowInhib applies the params for the slow inhib pool of given name,
+// if the network has it, which is turned off unless TwoInhibPools is on,
+// in case it is turned off before the next Init.  The pool gets the same
+// params as the fast inhib pool, except for the SlowInhibGTau time constant,
+// and the SlowInhibRel strength of its inhibition into the hidden layer.
+func (ss *Sim) ApplySlowInhib(net *leabra.Network, hidNm, inhNm, slowNm string) {
+	if _, err := net.EmerLayerByName(slowNm); err != nil {
+		return
+	}
+	hid := net.LayerByName(hidNm)
+	inh := net.LayerByName(inhNm)
+	slow := net.LayerByName(slowNm)
+	slow.Off = !ss.TwoInhibPools
+	slow.Act.Gbar.I = inh.Act.Gbar.I
+	slow.Act.Dt.GTau = ss.SlowInhibGTau
+	slow.Act.Update()
+	slow.Inhib.Layer.On = ss.FFFBInhib
+	for _, pt := range slow.RecvPaths {
+		if pt.Send == slow {
+			pt.WtScale.Abs = ss.FmInhibWtScaleAbs
+			continue
+		}
+		fp := errors.Log1(inh.RecvPathBySendName(pt.Send.Name)).(*leabra.Path)
+		pt.WtScale.Rel = fp.WtScale.Rel
+	}
+	fi := errors.Log1(hid.RecvPathBySendName(slowNm)).(*leabra.Path)
+	fi.WtScale.Abs = ss.FmInhibWtScaleAbs
+	fi.WtScale.Rel = 0
+	if ss.TwoInhibPools {
+		fi.WtScale.Rel = ss.SlowInhibRel
 	}
 }
 
@@ -405,6 +507,7 @@ func (ss *Sim) Init() {
 	ss.LoopsBidir.ResetCounters()
 	// ss.InitRandSeed(0)
 	ss.GUI.StopNow = false
+	ss.UpdateNets()
 	ss.ApplyParams(ss.Net())
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
@@ -552,6 +655,10 @@ func (ss *Sim) ConfigLogs() {
 	li.SetFixMin(true).SetFixMax(true)
 	li = ss.Logs.AddStatAggItem("InhibActAvg", etime.Trial, etime.Cycle)
 	li.SetFixMin(true).SetFixMax(true)
+	li = ss.Logs.AddStatAggItem("InhibSlowActAvg", etime.Trial, etime.Cycle)
+	li.SetFixMin(true).SetFixMax(true)
+	ss.Logs.AddStatAggItem("GiFast", etime.Trial, etime.Cycle)
+	ss.Logs.AddStatAggItem("GiSlow", etime.Trial, etime.Cycle)
 
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net())
@@ -559,11 +666,42 @@ func (ss *Sim) ConfigLogs() {
 }
 
 func (ss *Sim) CycleStats() {
-	layers := []string{"Hidden", "Inhib"}
+	net := ss.Net()
+	layers := []string{"Hidden", "Inhib", "InhibSlow"}
 	for _, lnm := range layers {
-		ly := ss.Net().LayerByName(lnm)
-		ss.Stats.SetFloat32(lnm+"ActAvg", ly.Pools[0].Inhib.Act.Avg)
+		act := float32(0)
+		if ly, err := net.EmerLayerByName(lnm); err == nil {
+			act = ly.(*leabra.Layer).Pools[0].Inhib.Act.Avg
+		}
+		ss.Stats.SetFloat32(lnm+"ActAvg", act)
+	}
+	hid := net.LayerByName("Hidden")
+	ss.Stats.SetFloat32("GiFast", ss.InhibDrive(hid, "Inhib"))
+	ss.Stats.SetFloat32("GiSlow", 0)
+	if _, err := net.EmerLayerByName("InhibSlow"); err == nil {
+		ss.Stats.SetFloat32("GiSlow", ss.InhibDrive(hid, "InhibSlow"))
+	}
+}
+
+// InhibDrive returns the average inhibitory input (before integration
+// with the GTau time constant) into the given layer from the given inhib
+// pool, which is the contribution of that pool to the inhibition:
+// the scaled sum of sending activity times the mean weight.
+func (ss *Sim) InhibDrive(ly *leabra.Layer, inhNm string) float32 {
+	pt := errors.Log1(ly.RecvPathBySendName(inhNm)).(*leabra.Path)
+	if pt.Send.Off || len(pt.Syns) == 0 {
+		return 0
+	}
+	sum := float32(0)
+	for i := range pt.Send.Neurons {
+		sum += pt.Send.Neurons[i].Act
+	}
+	wt := float32(0)
+	for i := range pt.Syns {
+		wt += pt.Syns[i].Wt
 	}
+	wt /= float32(len(pt.Syns))
+	return pt.GScale * wt * sum
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -6,4 +6,4 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "BidirNet", Doc: "if true, use the bidirectionally connected network,\notherwise use the simpler feedforward network."}, {Name: "TrainedWts", Doc: "simulate trained weights by having higher variance and Gaussian\ndistributed weight values -- otherwise lower variance, uniform."}, {Name: "InputPct", Doc: "percent of active units in input layer (literally number of active units,\nbecause input has 100 units total)."}, {Name: "FFFBInhib", Doc: "use feedforward, feedback (FFFB) computed inhibition instead\nof unit-level inhibition."}, {Name: "HiddenGbarI", Doc: "inhibitory conductance strength for inhibition into Hidden layer."}, {Name: "InhibGbarI", Doc: "inhibitory conductance strength for inhibition into Inhib layer\n(self-inhibition -- tricky!)."}, {Name: "FFinhibWtScale", Doc: "feedforward (FF) inhibition relative strength: for FF projections into Inhib neurons."}, {Name: "FBinhibWtScale", Doc: "feedback (FB) inhibition relative strength: for projections into Inhib neurons."}, {Name: "HiddenGTau", Doc: "time constant (tau) for updating G conductances into Hidden neurons\nMuch slower than std default of 1.4."}, {Name: "InhibGTau", Doc: "time constant (tau) for updating G conductances into Inhib neurons.\nMuch slower than std default of 1.4, but 2x faster than Hidden."}, {Name: "FmInhibWtScaleAbs", Doc: "absolute weight scaling of projections from inhibition onto\nhidden and inhib layers.  This must be set to 0 to turn off the\nconnection-based inhibition when using the FFFBInhib computed inbhition."}, {Name: "NetFF", Doc: "the feedforward network -- click to view / edit parameters for layers, paths, etc"}, {Name: "NetBidir", Doc: "the bidirectional network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "LoopsFF", Doc: "contains looper control loops for running sim"}, {Name: "LoopsBidir"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Patterns", Doc: "the patterns to use"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "NetviewFF"}, {Name: "NetviewBidir"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "BidirNet", Doc: "if true, use the bidirectionally connected network,\notherwise use the simpler feedforward network."}, {Name: "TrainedWts", Doc: "simulate trained weights by having higher variance and Gaussian\ndistributed weight values -- otherwise lower variance, uniform."}, {Name: "InputPct", Doc: "percent of active units in input layer (literally number of active units,\nbecause input has 100 units total)."}, {Name: "FFFBInhib", Doc: "use feedforward, feedback (FFFB) computed inhibition instead\nof unit-level inhibition."}, {Name: "HiddenGbarI", Doc: "inhibitory conductance strength for inhibition into Hidden layer."}, {Name: "InhibGbarI", Doc: "inhibitory conductance strength for inhibition into Inhib layer\n(self-inhibition -- tricky!)."}, {Name: "FFinhibWtScale", Doc: "feedforward (FF) inhibition relative strength: for FF projections into Inhib neurons."}, {Name: "FBinhibWtScale", Doc: "feedback (FB) inhibition relative strength: for projections into Inhib neurons."}, {Name: "HiddenGTau", Doc: "time constant (tau) for updating G conductances into Hidden neurons\nMuch slower than std default of 1.4."}, {Name: "InhibGTau", Doc: "time constant (tau) for updating G conductances into Inhib neurons.\nMuch slower than std default of 1.4, but 2x faster than Hidden."}, {Name: "FmInhibWtScaleAbs", Doc: "absolute weight scaling of projections from inhibition onto\nhidden and inhib layers.  This must be set to 0 to turn off the\nconnection-based inhibition when using the FFFBInhib computed inbhition."}, {Name: "TwoInhibPools", Doc: "split the inhibitory interneurons into two pools with different time constants:\nthe fast Inhib pool, using InhibGTau, and the slow InhibSlow pool, using\nSlowInhibGTau, which share the inhibition of the Hidden layer.\nThe networks are rebuilt with or without the slow pools on Init."}, {Name: "SlowInhibGTau", Doc: "time constant (tau) for updating G conductances into the slow InhibSlow neurons,\nwhen using TwoInhibPools."}, {Name: "SlowInhibRel", Doc: "strength of the inhibition from the slow pool into the Hidden layer,\nrelative to the fast pool, when using TwoInhibPools."}, {Name: "NetFF", Doc: "the feedforward network -- click to view / edit parameters for layers, paths, etc"}, {Name: "NetBidir", Doc: "the bidirectional network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "LoopsFF", Doc: "contains looper control loops for running sim"}, {Name: "LoopsBidir"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Patterns", Doc: "the patterns to use"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "NetviewFF"}, {Name: "NetviewBidir"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
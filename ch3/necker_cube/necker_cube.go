@@ -11,12 +11,18 @@ package main
 
 import (
 	"embed"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
 
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/downsample"
+	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
 	"github.com/emer/emergent/v2/emer"
@@ -72,6 +78,36 @@ var ParamSets = params.Sets{
 	},
 }
 
+// Config has config parameters related to running the sim
+type Config struct {
+	// maximum number of network states recorded in the NetView, which can be
+	// replayed from its counter controls. Each record holds the full network
+	// state, so memory grows in proportion: lower it to bound memory in long
+	// sessions, or raise it to keep more of the cycle-by-cycle history.
+	MaxRecs int `default:"300" min:"1"`
+
+	// maximum number of records shown in the NetView raster plot display
+	RasterMax int `default:"100" min:"1"`
+
+	// log stats on every cycle, which is needed to see the settling
+	// dynamics in the Cycle plot.  If off, only the final cycle of each
+	// trial is logged, so the Trial stats reflect the settled state,
+	// and the Cycle log stays small.
+	LogCycle bool `default:"false"`
+
+	// unit indexes in the NeckerCube layer for each of the competing percepts
+	// (interpretations), which can be extended to more than two groups.
+	// The percept with the highest average activity (above 0.5) is dominant,
+	// and a switch is counted whenever a different percept becomes dominant.
+	// The number of percepts determines the log items, so changes to it
+	// take effect on restart.
+	Percepts [][]int
+}
+
+func (cfg *Config) Defaults() {
+	cfg.Percepts = [][]int{{0, 1, 2, 3, 4, 5, 6, 7}, {8, 9, 10, 11, 12, 13, 14, 15}}
+}
+
 // Sim encapsulates the entire simulation model, and we define all the
 // functionality as methods on this struct.  This structure keeps all relevant
 // state information organized and available without having to pass everything around
@@ -81,12 +117,33 @@ type Sim struct {
 	// the variance parameter for Gaussian noise added to unit activations on every cycle
 	Noise float32 `min:"0" step:"0.01"`
 
+	// temperature scales the softness of the competition between the percepts,
+	// as a single knob trading off stability vs. switching rate: the effective
+	// noise variance is Noise * Temperature, and the activation function gain
+	// (Act.XX1.Gain, 100 by default) is divided by Temperature.  Higher values
+	// give noisier, softer competition with more switching, and lower values
+	// give more stable percepts.  1 = use Noise and the gain as given.
+	Temperature float32 `default:"1" min:"0.1" max:"4" step:"0.1"`
+
 	// apply sodium-gated potassium adaptation mechanisms that cause the neuron to reduce spiking over time
 	KNaAdapt bool
 
 	// total number of cycles to run per trial; increase to 1,000 when testing adaptation
 	Cycles int `default:"100,1000"`
 
+	// minimum interval in msec between plot updates while running, which keeps
+	// the GUI responsive during long runs; 0 = update on every step
+	PlotInterval int `default:"50" min:"0" step:"10"`
+
+	// maximum number of points in the Test Cycle plot, which is downsampled
+	// by averaging the cycles within equal-sized bins for long runs, keeping
+	// the plot fast and readable; the log and stats are unaffected.
+	// 0 = plot every cycle
+	PlotPoints int `default:"0" min:"0" step:"100"`
+
+	// Config contains misc configuration parameters for running the sim
+	Config Config `new-window:"+" display:"no-inline"`
+
 	// the network -- click to view / edit parameters for layers, paths, etc
 	Net *leabra.Network `new-window:"+" display:"no-inline"`
 
@@ -113,10 +170,29 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// index of the currently dominant percept, -1 if none yet
+	percept int
+
+	// total number of cycles run, and the count at which the current percept became dominant
+	perceptCycles, perceptStart int
+
+	// number of switches and completed dwell times in the current trial
+	trialSwitches int
+	trialDwells   []int
+
+	// downsampling of the Test Cycle log shown in its plot
+	cycPlot downsample.Plot
+
+	// activation function gain (Act.XX1.Gain) of the NeckerCube layer from
+	// the params, which is divided by Temperature in ApplyParams
+	baseGain float32
 }
 
 // New creates new blank elements and initializes defaults
 func (ss *Sim) New() {
+	ss.Config.Defaults()
+	econfig.Config(&ss.Config, "config.toml")
 	ss.Defaults()
 	ss.Net = leabra.NewNetwork("NeckerCube")
 	ss.Params.Config(ParamSets, "", "", ss.Net)
@@ -128,8 +204,11 @@ func (ss *Sim) New() {
 
 func (ss *Sim) Defaults() {
 	ss.Noise = 0.01
+	ss.Temperature = 1
 	ss.KNaAdapt = false
 	ss.Cycles = 100
+	ss.PlotInterval = 50
+	ss.PlotPoints = 0
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -152,6 +231,7 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 
 	net.Build()
 	net.Defaults()
+	ss.baseGain = nc.Act.XX1.Gain
 	ss.ApplyParams()
 	ss.InitWeights(net)
 }
@@ -165,7 +245,9 @@ func (ss *Sim) InitWeights(net *leabra.Network) {
 func (ss *Sim) ApplyParams() {
 	ss.Params.SetAll()
 	ly := ss.Net.LayerByName("NeckerCube")
-	ly.Act.Noise.Var = float64(ss.Noise)
+	temp := max(ss.Temperature, 0.01)
+	ly.Act.Noise.Var = float64(ss.Noise * temp)
+	ly.Act.XX1.Gain = ss.baseGain / temp // from the base, so repeated calls do not compound
 	ly.Act.KNa.On = ss.KNaAdapt
 	ly.Act.Update()
 	if ss.Loops != nil {
@@ -217,7 +299,11 @@ func (ss *Sim) ConfigLoops() {
 		stack := ls.Stacks[m]
 		stack.Loops[etime.Trial].OnStart.Add("ApplyInputs", func() {
 			ss.ApplyInputs()
+			ss.trialSwitches = 0
+			ss.trialDwells = ss.trialDwells[:0]
 		})
+		// percept stats are needed for switches even when not logging cycles
+		stack.Loops[etime.Cycle].OnEnd.Add("PerceptStats", ss.PerceptStats)
 	}
 
 	/////////////////////////////////////////////
@@ -232,12 +318,58 @@ func (ss *Sim) ConfigLoops() {
 	// GUI
 
 	leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
-	leabra.LooperUpdatePlots(ls, &ss.GUI)
+	ss.ConfigPlotUpdates(ls)
 	ls.Stacks[etime.Test].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
 
 	ss.Loops = ls
 }
 
+// ConfigPlotUpdates adds plot updates at the end of each loop, as in
+// leabra.LooperUpdatePlots, except that Trial and Cycle level updates happen
+// at most once every PlotInterval msec.  The Cycle plot is also updated at
+// the end of each Trial, so its final cycle is always rendered, and all plots
+// are updated at the end of the Epoch, so the final state is always rendered
+// when the run ends.
+func (ss *Sim) ConfigPlotUpdates(ls *looper.Stacks) {
+	for m, stack := range ls.Stacks {
+		mode := m.(etime.Modes)
+		for t, loop := range stack.Loops {
+			tm := t.(etime.Times)
+			var last time.Time
+			loop.OnEnd.Add("GUI:UpdatePlot", func() {
+				if tm == etime.Epoch {
+					for pt := range stack.Loops {
+						if pt == etime.Cycle {
+							ss.UpdateCyclePlot(mode)
+							continue
+						}
+						ss.GUI.GoUpdatePlot(mode, pt.(etime.Times))
+					}
+					return
+				}
+				if tm == etime.Trial {
+					ss.UpdateCyclePlot(mode)
+				}
+				if ss.PlotInterval > 0 && time.Since(last) < time.Duration(ss.PlotInterval)*time.Millisecond {
+					return
+				}
+				last = time.Now()
+				if tm == etime.Cycle {
+					ss.UpdateCyclePlot(mode)
+				} else {
+					ss.GUI.GoUpdatePlot(mode, tm)
+				}
+			})
+		}
+	}
+}
+
+// UpdateCyclePlot updates the Cycle plot for given mode, showing the
+// Cycle log downsampled to PlotPoints points if it has more rows than that.
+func (ss *Sim) UpdateCyclePlot(mode etime.Modes) {
+	ss.cycPlot.Update(ss.GUI.Plots[etime.Scope(mode, etime.Cycle)], ss.Logs.Table(mode, etime.Cycle), ss.PlotPoints)
+}
+
 // ApplyInputs applies input patterns from given environment.
 // It is good practice to have this be a separate method with appropriate
 // args so that it can be used for various different contexts
@@ -275,6 +407,13 @@ func (ss *Sim) NewRun() {
 // called at start of new run
 func (ss *Sim) InitStats() {
 	ss.Stats.SetString("TrialName", "")
+	ss.percept = -1
+	ss.perceptCycles = 0
+	ss.perceptStart = 0
+	ss.trialSwitches = 0
+	ss.trialDwells = ss.trialDwells[:0]
+	ss.Stats.SetFloat("Switches", 0)
+	ss.Stats.SetFloat("DwellTime", math.NaN())
 }
 
 // StatCounters saves current counters to Stats, so they are available for logging etc
@@ -319,6 +458,54 @@ func (ss *Sim) Harmony(nt *leabra.Network) float32 {
 	return harm
 }
 
+// PerceptStats computes the average activity of each percept group,
+// as Percept<i>_Act stats, and tracks switches of the dominant percept
+// and the number of cycles each percept stayed dominant (dwell time).
+// Called at the end of every cycle.
+func (ss *Sim) PerceptStats() {
+	ly := ss.Net.LayerByName("NeckerCube")
+	dom, domAct := -1, float32(0.5)
+	for pi, units := range ss.Config.Percepts {
+		avg := float32(0)
+		for _, ui := range units {
+			avg += ly.Neurons[ui].Act
+		}
+		if len(units) > 0 {
+			avg /= float32(len(units))
+		}
+		ss.Stats.SetFloat32(fmt.Sprintf("Percept%d_Act", pi), avg)
+		if avg > domAct {
+			dom, domAct = pi, avg
+		}
+	}
+	ss.perceptCycles++
+	if dom < 0 || dom == ss.percept {
+		return
+	}
+	if ss.percept >= 0 {
+		ss.trialSwitches++
+		ss.trialDwells = append(ss.trialDwells, ss.perceptCycles-ss.perceptStart)
+	}
+	ss.percept = dom
+	ss.perceptStart = ss.perceptCycles
+}
+
+// TrialStats records the number of percept switches in the trial,
+// and the mean dwell time of the percepts that ended in the trial
+// (NaN if there were no switches).
+func (ss *Sim) TrialStats() {
+	ss.Stats.SetFloat("Switches", float64(ss.trialSwitches))
+	dwell := math.NaN()
+	if n := len(ss.trialDwells); n > 0 {
+		sum := 0
+		for _, d := range ss.trialDwells {
+			sum += d
+		}
+		dwell = float64(sum) / float64(n)
+	}
+	ss.Stats.SetFloat("DwellTime", dwell)
+}
+
 //////////////////////////////////////////////////////////////////////////////
 // 		Logging
 
@@ -330,11 +517,29 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddStatAggItem("GknaMed", etime.Trial, etime.Cycle)
 	ss.Logs.AddStatAggItem("GknaSlow", etime.Trial, etime.Cycle)
 
+	var pnms []string
+	for pi := range ss.Config.Percepts {
+		pnm := fmt.Sprintf("Percept%d_Act", pi)
+		ss.Logs.AddStatAggItem(pnm, etime.Trial, etime.Cycle)
+		pnms = append(pnms, pnm)
+	}
+	for _, st := range []string{"Switches", "DwellTime"} {
+		ss.Logs.AddItem(&elog.Item{
+			Name:   st,
+			Type:   reflect.Float64,
+			FixMin: true,
+			Write: elog.WriteMap{
+				etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+					ctx.SetStatFloat(st)
+				}}})
+	}
+
 	ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "InputLayer")
 
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
-	ss.Logs.PlotItems("Harmony")
+	ss.Logs.PlotItems("Harmony", "Switches")
+	ss.Logs.PlotItems(pnms...)
 }
 
 func (ss *Sim) CycleStats() {
@@ -359,10 +564,20 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 
 	switch {
 	case time == etime.Cycle:
+		if !ss.Config.LogCycle {
+			return
+		}
 		ss.StatCounters()
 		ss.CycleStats()
 	case time == etime.Trial:
+		if !ss.Config.LogCycle { // only log the final, settled cycle
+			ss.StatCounters()
+			ss.CycleStats()
+			cdt := ss.Logs.Table(mode, etime.Cycle)
+			ss.Logs.LogRow(mode, etime.Cycle, cdt.Rows)
+		}
 		ss.StatCounters()
+		ss.TrialStats()
 		ss.Logs.Log(mode, time) // also logs to file, etc
 		return
 	}
@@ -379,8 +594,8 @@ func (ss *Sim) ConfigGUI() {
 	ss.GUI.CycleUpdateInterval = 10
 
 	nv := ss.GUI.AddNetView("Network")
-	nv.Options.Raster.Max = 100
-	nv.Options.MaxRecs = 300
+	nv.Options.Raster.Max = ss.Config.RasterMax
+	nv.Options.MaxRecs = ss.Config.MaxRecs
 	nv.SetNet(ss.Net)
 	ss.ViewUpdate.Config(nv, etime.Cycle, etime.Cycle)
 	ss.GUI.ViewUpdate = &ss.ViewUpdate
@@ -6,4 +6,6 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Noise", Doc: "the variance parameter for Gaussian noise added to unit activations on every cycle"}, {Name: "KNaAdapt", Doc: "apply sodium-gated potassium adaptation mechanisms that cause the neuron to reduce spiking over time"}, {Name: "Cycles", Doc: "total number of cycles to run per trial; increase to 1,000 when testing adaptation"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "MaxRecs", Doc: "maximum number of network states recorded in the NetView, which can be\nreplayed from its counter controls. Each record holds the full network\nstate, so memory grows in proportion: lower it to bound memory in long\nsessions, or raise it to keep more of the cycle-by-cycle history."}, {Name: "RasterMax", Doc: "maximum number of records shown in the NetView raster plot display"}, {Name: "LogCycle", Doc: "log stats on every cycle, which is needed to see the settling\ndynamics in the Cycle plot.  If off, only the final cycle of each\ntrial is logged, so the Trial stats reflect the settled state,\nand the Cycle log stays small."}, {Name: "Percepts", Doc: "unit indexes in the NeckerCube layer for each of the competing percepts\n(interpretations), which can be extended to more than two groups.\nThe percept with the highest average activity (above 0.5) is dominant,\nand a switch is counted whenever a different percept becomes dominant.\nThe number of percepts determines the log items, so changes to it\ntake effect on restart."}}})
+
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Noise", Doc: "the variance parameter for Gaussian noise added to unit activations on every cycle"}, {Name: "Temperature", Doc: "temperature scales the softness of the competition between the percepts,\nas a single knob trading off stability vs. switching rate: the effective\nnoise variance is Noise * Temperature, and the activation function gain\n(Act.XX1.Gain, 100 by default) is divided by Temperature.  Higher values\ngive noisier, softer competition with more switching, and lower values\ngive more stable percepts.  1 = use Noise and the gain as given."}, {Name: "KNaAdapt", Doc: "apply sodium-gated potassium adaptation mechanisms that cause the neuron to reduce spiking over time"}, {Name: "Cycles", Doc: "total number of cycles to run per trial; increase to 1,000 when testing adaptation"}, {Name: "PlotInterval", Doc: "minimum interval in msec between plot updates while running, which keeps\nthe GUI responsive during long runs; 0 = update on every step"}, {Name: "PlotPoints", Doc: "maximum number of points in the Test Cycle plot, which is downsampled\nby averaging the cycles within equal-sized bins for long runs, keeping\nthe plot fast and readable; the log and stats are unaffected.\n0 = plot every cycle"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
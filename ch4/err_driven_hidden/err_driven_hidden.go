@@ -12,6 +12,7 @@ package main
 
 import (
 	"embed"
+	"fmt"
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
@@ -20,6 +21,7 @@ import (
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/envstate"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -31,6 +33,7 @@ import (
 	"github.com/emer/emergent/v2/netview"
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/etensor/plot/plotcore"
 	"github.com/emer/etensor/tensor/table"
 	"github.com/emer/leabra/v2/leabra"
 )
@@ -126,6 +129,15 @@ type Config struct {
 	// how often to run through all the test patterns, in terms of training epochs.
 	// can use 0 or -1 for no testing.
 	TestInterval int `default:"5"`
+
+	// activity threshold for counting an output or target unit as on,
+	// for the Hamming distance stat.
+	HammingThr float32 `default:"0.5" min:"0" max:"1"`
+
+	// number of training trials in each mini-batch: weight changes are
+	// accumulated over the trials in a batch, and applied once at the end
+	// of the batch (or the end of the epoch).  1 = update after every trial.
+	BatchSize int `default:"1" min:"1"`
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -180,6 +192,12 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// row in the BatchCurves table for each batch size and epoch
+	batchRows map[string]int
+
+	// reused input tensors for ApplyInputs
+	inputs envstate.Buffers
 }
 
 // New creates new blank elements and initializes defaults
@@ -300,6 +318,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -330,6 +349,20 @@ func (ss *Sim) ConfigLoops() {
 
 	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, 75, 99)                // plus phase timing
 	leabra.LooperSimCycleAndLearn(ls, ss.Net, &ss.Context, &ss.ViewUpdate) // std algo code
+
+	// accumulate weight changes over mini-batches of BatchSize trials
+	trainTrial := ls.Loop(etime.Train, etime.Trial)
+	trainTrial.OnEnd.Replace("UpdateWeights", func() bool {
+		ss.Net.DWt()
+		if ss.ViewUpdate.IsViewingSynapse() {
+			ss.ViewUpdate.RecordSyns()
+		}
+		trl := trainTrial.Counter
+		if (trl.Cur+1)%max(ss.Config.BatchSize, 1) == 0 || trl.Cur+1 >= trl.Max {
+			ss.Net.WtFromDWt()
+		}
+		return true
+	})
 	ls.Stacks[etime.Train].OnInit.Add("Init", func() { ss.Init() })
 
 	for m, _ := range ls.Stacks {
@@ -409,7 +442,7 @@ func (ss *Sim) ApplyInputs() {
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
+		pats := ss.inputs.State(ev, ly.Name)
 		if pats != nil {
 			ly.ApplyExt(pats)
 		}
@@ -449,11 +482,18 @@ func (ss *Sim) NewRun() {
 	ss.Logs.ResetLog(etime.Test, etime.Epoch)
 }
 
-// TestAll runs through the full set of testing items
-func (ss *Sim) TestAll() {
+// TestAll runs through the full set of testing items, and returns
+// the accuracy, as the proportion of testing items that are correct
+// (PctCor in the Test Epoch log, from 0 to 1).
+func (ss *Sim) TestAll() float64 {
 	ss.Envs.ByMode(etime.Test).Init(0)
 	ss.Loops.ResetAndRun(etime.Test)
 	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
+	dt := ss.Logs.Table(etime.Test, etime.Epoch)
+	if dt.Rows == 0 {
+		return 0
+	}
+	return dt.Float("PctCor", dt.Rows-1)
 }
 
 /////////////////////////////////////////////////////////////////////
@@ -463,6 +503,7 @@ func (ss *Sim) TestAll() {
 // called at start of new run
 func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("SSE", 0.0)
+	ss.Stats.SetFloat("Hamming", 0.0)
 	ss.Stats.SetString("TrialName", "")
 	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
@@ -505,6 +546,21 @@ func (ss *Sim) TrialStats() {
 	} else {
 		ss.Stats.SetFloat("TrlErr", 0)
 	}
+	ss.Stats.SetFloat("Hamming", float64(ss.Hamming(out)))
+}
+
+// Hamming returns the number of units in given layer where the thresholded
+// minus phase output differs from the thresholded target, using HammingThr.
+func (ss *Sim) Hamming(ly *leabra.Layer) int {
+	thr := ss.Config.HammingThr
+	nd := 0
+	for i := range ly.Neurons {
+		nrn := &ly.Neurons[i]
+		if (nrn.ActM > thr) != (nrn.Targ > thr) {
+			nd++
+		}
+	}
+	return nd
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -520,6 +576,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddStatAggItem("SSE", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("AvgSSE", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddErrStatAggItems("TrlErr", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("Hamming", etime.Run, etime.Epoch, etime.Trial)
 
 	ss.Logs.AddCopyFromFloatItems(etime.Train, []etime.Times{etime.Epoch, etime.Run}, etime.Test, etime.Epoch, "Tst", "SSE", "AvgSSE")
 
@@ -528,7 +585,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddLayerTensorItems(ss.Net, "ActM", etime.Test, etime.Trial, "InputLayer", "SuperLayer", "TargetLayer")
 	ss.Logs.AddLayerTensorItems(ss.Net, "Targ", etime.Test, etime.Trial, "TargetLayer")
 
-	ss.Logs.PlotItems("SSE", "FirstZero", "LastZero")
+	ss.Logs.PlotItems("SSE", "Hamming", "FirstZero", "LastZero")
 
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
@@ -538,6 +595,48 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.NoPlot(etime.Test, etime.Trial)
 	ss.Logs.NoPlot(etime.Test, etime.Run)
 	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+
+	ss.ConfigBatchCurves(ss.Logs.MiscTable("BatchCurves"))
+}
+
+// ConfigBatchCurves configures the table of learning curves for each
+// batch size, which has the training SSE for each epoch, averaged
+// over all runs with that batch size.
+func (ss *Sim) ConfigBatchCurves(dt *table.Table) {
+	dt.SetMetaData("name", "BatchCurves")
+	dt.SetMetaData("desc", "learning curves for each mini-batch size, averaged over runs")
+	dt.SetMetaData("read-only", "true")
+	dt.SetMetaData("LegendCol", "Batch")
+	if dt.NumColumns() == 0 {
+		dt.AddStringColumn("Batch")
+		dt.AddIntColumn("Epoch")
+		dt.AddFloat64Column("SSE")
+		dt.AddIntColumn("NRuns")
+	}
+	dt.SetNumRows(0)
+	ss.batchRows = map[string]int{}
+}
+
+// LogBatchCurve adds the training SSE for the given epoch to the
+// average for the current batch size in the BatchCurves table.
+func (ss *Sim) LogBatchCurve(epoch int, sse float64) {
+	dt := ss.Logs.MiscTable("BatchCurves")
+	batch := fmt.Sprintf("Batch %d", ss.Config.BatchSize)
+	key := fmt.Sprintf("%s:%d", batch, epoch)
+	row, ok := ss.batchRows[key]
+	if !ok {
+		row = dt.Rows
+		dt.SetNumRows(row + 1)
+		dt.SetString("Batch", row, batch)
+		dt.SetFloat("Epoch", row, float64(epoch))
+		ss.batchRows[key] = row
+	}
+	n := dt.Float("NRuns", row)
+	dt.SetFloat("SSE", row, (dt.Float("SSE", row)*n+sse)/(n+1))
+	dt.SetFloat("NRuns", row, n+1)
+	if plt := ss.GUI.PlotByName("BatchCurves"); plt != nil {
+		plt.GoUpdatePlot()
+	}
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -561,6 +660,9 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 	}
 
 	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
+	if mode == etime.Train && time == etime.Epoch {
+		ss.LogBatchCurve(int(dt.Float("Epoch", row)), dt.Float("SSE", row))
+	}
 
 	if mode == etime.Test {
 		ss.GUI.UpdateTableView(etime.Test, etime.Trial)
@@ -592,6 +694,15 @@ func (ss *Sim) ConfigGUI() {
 
 	ss.GUI.AddTableView(&ss.Logs, etime.Test, etime.Trial)
 
+	stnm := "BatchCurves"
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey(stnm), stnm+" Plot")
+	plt.Options.Title = "Learning Curves by Batch Size"
+	plt.Options.XAxis = "Epoch"
+	plt.SetTable(ss.Logs.MiscTable(stnm))
+	// order of params: on, fixMin, min, fixMax, max
+	plt.SetColumnOptions("SSE", plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 0)
+	plt.SetColumnOptions("NRuns", plotcore.Off, plotcore.FixMin, 0, plotcore.FloatMax, 0)
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -608,6 +719,17 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.GUI.UpdatePlot(etime.Train, etime.Run)
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset Batch Curves",
+		Icon:    icons.Reset,
+		Tooltip: "Reset the learning curves accumulated for each mini-batch size (Config.BatchSize)",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.ConfigBatchCurves(ss.Logs.MiscTable("BatchCurves"))
+			if plt := ss.GUI.PlotByName("BatchCurves"); plt != nil {
+				plt.UpdatePlot()
+			}
+		},
+	})
 	////////////////////////////////////////////////
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "New Seed",
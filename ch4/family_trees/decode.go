@@ -0,0 +1,55 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"cogentcore.org/core/base/errors"
+	"github.com/compcogneuro/sims/v2/decode"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// DecodeAccuracy returns the cross-validated accuracy of a linear classifier
+// trained to decode the DecodeTarget category from the minus phase activity
+// (ActM) of the DecodeLayer, over the trials of the last test epoch, from
+// the Test Trial log, with DecodeFolds folds.  The category of each trial
+// is the active unit of the DecodeTarget layer: the target (Targ) for
+// the Patient, or the input for the Agent and Relation.
+// Returns NaN if the layer or target is not in the log.
+func (ss *Sim) DecodeAccuracy() float64 {
+	dt := ss.Logs.Table(etime.Test, etime.Trial)
+	acol, err := dt.ColumnByName(ss.DecodeLayer + "_ActM")
+	if errors.Log(err) != nil {
+		return math.NaN()
+	}
+	tcol, err := dt.ColumnByName(ss.DecodeTarget + "_Targ")
+	if err != nil {
+		tcol, err = dt.ColumnByName(ss.DecodeTarget + "_ActM")
+		if errors.Log(err) != nil {
+			return math.NaN()
+		}
+	}
+	nr := dt.Rows
+	if nr == 0 {
+		return math.NaN()
+	}
+	na := acol.Len() / nr
+	nc := tcol.Len() / nr
+	x := make([][]float64, nr)
+	y := make([]int, nr)
+	for r := range nr {
+		x[r] = make([]float64, na)
+		for i := range na {
+			x[r][i] = acol.Float1D(r*na + i)
+		}
+		for c := range nc {
+			if tcol.Float1D(r*nc+c) > tcol.Float1D(r*nc+y[r]) {
+				y[r] = c
+			}
+		}
+	}
+	return decode.CrossValidate(x, y, nc, ss.DecodeFolds)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"cogentcore.org/core/base/errors"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/stats/metric"
+)
+
+// Drift returns the representational drift of the DriftLayer since the
+// previous test epoch in the current run: 1 minus the mean correlation
+// between the minus phase activity (ActM) patterns for each item on the
+// two test epochs, from the Test Trial log, so 0 is a perfectly stable
+// representation of every item, and 1 is a completely new one.
+// The current activity is saved for the next test epoch, and
+// NaN is returned on the first test epoch of a run.
+func (ss *Sim) Drift() float64 {
+	dt := ss.Logs.Table(etime.Test, etime.Trial)
+	col, err := dt.ColumnByName(ss.DriftLayer + "_ActM")
+	if errors.Log(err) != nil {
+		return math.NaN()
+	}
+	nr := dt.Rows
+	if nr == 0 {
+		return math.NaN()
+	}
+	nu := col.Len() / nr
+	cur := make([]float32, nr*nu)
+	for i := range cur {
+		cur[i] = float32(col.Float1D(i))
+	}
+	prev := ss.driftPrev
+	ss.driftPrev = cur
+	if len(prev) != len(cur) {
+		return math.NaN()
+	}
+	sum := 0.0
+	n := 0
+	for r := range nr {
+		c := float64(metric.Correlation32(prev[r*nu:(r+1)*nu], cur[r*nu:(r+1)*nu]))
+		if math.IsNaN(c) {
+			continue
+		}
+		sum += c
+		n++
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return 1 - sum/float64(n)
+}
@@ -11,14 +11,18 @@ package main
 
 import (
 	"embed"
+	"math"
+	"reflect"
 	"strings"
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
+	"cogentcore.org/core/math32/minmax"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/envstate"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -139,6 +143,13 @@ type Config struct {
 	// how often to run through all the test patterns, in terms of training epochs.
 	// can use 0 or -1 for no testing.
 	TestInterval int `default:"5"`
+
+	// compute the PctErr_Smooth moving average of the PctErr learning curve,
+	// to make the overall trend easier to see in noisy learning curves.
+	Smooth bool `default:"true"`
+
+	// number of epochs in the PctErr_Smooth moving average window.
+	SmoothWindow int `default:"5" min:"1"`
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -151,6 +162,34 @@ type Sim struct {
 	// select which type of learning to use
 	Learn LearnType
 
+	// layer whose activity for each test trial is shown in the ActHeatmap tab,
+	// which must be one of the layers with ActM in the Test Trial log
+	HeatmapLayer string
+
+	// layer whose activity is decoded by a linear classifier for the Decode
+	// stat, which must be one of the layers with ActM in the Test Trial log
+	DecodeLayer string
+
+	// layer whose active unit is the category decoded for the Decode stat:
+	// Agent, Relation, or Patient
+	DecodeTarget string
+
+	// number of cross-validation folds for the Decode stat
+	DecodeFolds int `min:"2"`
+
+	// layer whose change in activity for each item between test epochs
+	// is the Drift stat, which must be one of the layers with ActM
+	// in the Test Trial log
+	DriftLayer string
+
+	// layer whose units are lesioned by Lesion and LesionSweep,
+	// to test the graceful degradation of its distributed representation
+	LesionLayer string
+
+	// random seed for choosing which units are lesioned,
+	// so the same units are lesioned for a given seed
+	LesionSeed int64
+
 	// Config contains misc configuration parameters for running the sim
 	Config Config `new-window:"+" display:"no-inline"`
 
@@ -186,12 +225,25 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// DriftLayer activity on the previous test epoch, for the Drift stat
+	driftPrev []float32
+
+	// reused input tensors for ApplyInputs
+	inputs envstate.Buffers
 }
 
 // New creates new blank elements and initializes defaults
 func (ss *Sim) New() {
 	econfig.Config(&ss.Config, "config.toml")
 	ss.Learn = HebbError
+	ss.HeatmapLayer = "Hidden"
+	ss.DecodeLayer = "Hidden"
+	ss.DecodeTarget = "Patient"
+	ss.DecodeFolds = 4
+	ss.DriftLayer = "Hidden"
+	ss.LesionLayer = "Hidden"
+	ss.LesionSeed = 1
 	ss.Net = leabra.NewNetwork("FamilyTrees")
 	ss.Params.Config(ParamSets, "", "", ss.Net)
 	ss.Stats.Init()
@@ -307,6 +359,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -379,6 +432,7 @@ func (ss *Sim) ConfigLoops() {
 	ls.AddOnEndToAll("Log", func(mode, time enums.Enum) {
 		ss.Log(mode.(etime.Modes), time.(etime.Times))
 	})
+	ls.Loop(etime.Test, etime.Epoch).OnEnd.Add("ActHeatmap", ss.ActHeatmap)
 	leabra.LooperResetLogBelow(ls, &ss.Logs)
 	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
 		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
@@ -417,7 +471,7 @@ func (ss *Sim) ApplyInputs() {
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
+		pats := ss.inputs.State(ev, ly.Name)
 		if pats != nil {
 			ly.ApplyExt(pats)
 		}
@@ -438,6 +492,7 @@ func (ss *Sim) NewRun() {
 	ss.StatCounters()
 	ss.Logs.ResetLog(etime.Train, etime.Epoch)
 	ss.Logs.ResetLog(etime.Test, etime.Epoch)
+	ss.driftPrev = nil
 }
 
 // TestAll runs through the full set of testing items
@@ -564,14 +619,51 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddStatAggItem("AvgSSE", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddErrStatAggItems("TrlErr", etime.Run, etime.Epoch, etime.Trial)
 
-	ss.Logs.AddCopyFromFloatItems(etime.Train, []etime.Times{etime.Epoch, etime.Run}, etime.Test, etime.Epoch, "Tst", "SSE", "AvgSSE")
+	// cross-validated accuracy of decoding the DecodeTarget from the DecodeLayer
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "Decode",
+		Type:   reflect.Float64,
+		FixMin: true,
+		FixMax: true,
+		Range:  minmax.F32{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetFloat64(ss.DecodeAccuracy())
+			}}})
+
+	// change in the DriftLayer representation of each item since the last test
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "Drift",
+		Type:   reflect.Float64,
+		FixMin: true,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetFloat64(ss.Drift())
+			}}})
+
+	ss.Logs.AddCopyFromFloatItems(etime.Train, []etime.Times{etime.Epoch, etime.Run}, etime.Test, etime.Epoch, "Tst", "SSE", "AvgSSE", "Decode", "Drift")
+
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "PctErr_Smooth",
+		Type:   reflect.Float64,
+		FixMin: true,
+		FixMax: true,
+		Range:  minmax.F32{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.Train, etime.Epoch): func(ctx *elog.Context) {
+				if !ss.Config.Smooth {
+					ctx.SetFloat64(math.NaN())
+					return
+				}
+				ctx.SetFloat64(MovingAverage(ctx.Table, "PctErr", ctx.Row, ss.Config.SmoothWindow))
+			}}})
 
 	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
 
 	ss.Logs.AddLayerTensorItems(ss.Net, "ActM", etime.Test, etime.Trial, "InputLayer", "SuperLayer", "TargetLayer")
 	ss.Logs.AddLayerTensorItems(ss.Net, "Targ", etime.Test, etime.Trial, "TargetLayer")
 
-	ss.Logs.PlotItems("PctErr", "FirstZero", "LastZero")
+	ss.Logs.PlotItems("PctErr", "PctErr_Smooth", "FirstZero", "LastZero", "TstDecode", "TstDrift")
 
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
@@ -581,6 +673,19 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.NoPlot(etime.Test, etime.Trial)
 	ss.Logs.NoPlot(etime.Test, etime.Run)
 	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+
+	ss.ConfigLesionSweep(ss.Logs.MiscTable("LesionSweep"))
+}
+
+// MovingAverage returns the mean of the given column over the window rows
+// ending at the given row, or fewer at the start of the table.
+func MovingAverage(dt *table.Table, colNm string, row, window int) float64 {
+	st := max(row-window+1, 0)
+	sum := 0.0
+	for r := st; r <= row; r++ {
+		sum += dt.Float(colNm, r)
+	}
+	return sum / float64(row-st+1)
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -642,6 +747,11 @@ func (ss *Sim) ConfigGUI() {
 	ss.GUI.AddMiscPlotTab("AgentCodePCA")
 	ss.GUI.AddMiscPlotTab("AgentCodeClust")
 
+	hg := ss.GUI.AddGridTab("ActHeatmap")
+	hg.SetTensor(ss.Stats.F32Tensor("ActHeatmap"))
+
+	ss.ConfigLesionSweepPlot()
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -656,6 +766,22 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.RepsAnalysis()
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Lesion",
+		Icon:    icons.Delete,
+		Tooltip: "Lesion the given fraction of the units in the LesionLayer, chosen at random from the LesionSeed, and test all the items -- use 0 to restore all the units",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.Lesion)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Lesion Sweep",
+		Icon:    icons.PlayArrow,
+		Tooltip: "Test the trained network with increasing fractions of the LesionLayer units lesioned, from 0 to 1 in the given number of steps, and plot the accuracy vs. the fraction lesioned in the LesionSweep Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.LesionSweep)
+		},
+	})
 
 	////////////////////////////////////////////////
 	tree.Add(p, func(w *core.Separator) {})
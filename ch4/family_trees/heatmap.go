@@ -0,0 +1,40 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cogentcore.org/core/base/errors"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// ActHeatmap fills the ActHeatmap tensor with the minus phase activity
+// (ActM) of each unit in the HeatmapLayer, for each trial of the last test
+// epoch, from the Test Trial log.  This trial x unit matrix is shown as a
+// heatmap in the ActHeatmap tab, so the population code across all of the
+// items can be seen at once, with the trials in the order of the Test Trial log.
+func (ss *Sim) ActHeatmap() {
+	dt := ss.Logs.Table(etime.Test, etime.Trial)
+	col, err := dt.ColumnByName(ss.HeatmapLayer + "_ActM")
+	if errors.Log(err) != nil {
+		return
+	}
+	nr := dt.Rows
+	nu := 0
+	if nr > 0 {
+		nu = col.Len() / nr
+	}
+	hm := ss.Stats.F32Tensor("ActHeatmap")
+	hm.SetShape([]int{nr, nu}, "Trial", "Unit")
+	for i := range hm.Values {
+		hm.Values[i] = float32(col.Float1D(i))
+	}
+	if ss.GUI.Grids == nil {
+		return
+	}
+	gv := ss.GUI.Grid("ActHeatmap")
+	gv.AsyncLock()
+	gv.Update()
+	gv.AsyncUnlock()
+}
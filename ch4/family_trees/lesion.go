@@ -0,0 +1,154 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/table"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// Lesion lesions the given fraction (0-1) of the units in the LesionLayer,
+// chosen at random using the LesionSeed, so the same units are lesioned
+// for a given seed, and tests all the items, reporting the proportion
+// correct (PctCor).  The lesion stays in place until Lesion is called
+// again, e.g., with 0 to restore all of the units.
+func (ss *Sim) Lesion(frac float32) error { //types:add
+	ly := ss.Net.LayerByName(ss.LesionLayer)
+	if ly == nil {
+		return fmt.Errorf("Lesion: layer %q not found", ss.LesionLayer)
+	}
+	if ss.GUI.Body == nil {
+		fmt.Println(ss.LesionTest(ly, frac))
+		return nil
+	}
+	if ss.GUI.IsRunning {
+		return nil
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		sum := ss.LesionTest(ly, frac)
+		ss.GUI.Stopped()
+		ss.GUI.Body.AsyncLock()
+		core.MessageDialog(ss.GUI.Body, sum, "Lesion")
+		ss.GUI.Body.AsyncUnlock()
+	}()
+	return nil
+}
+
+// LesionTest lesions the given fraction of units in the layer,
+// tests all the items, and returns a summary of the resulting PctCor.
+func (ss *Sim) LesionTest(ly *leabra.Layer, frac float32) string {
+	n := ss.lesionUnits(ly, frac)
+	pc := ss.testPctCor()
+	return fmt.Sprintf("%s: %d of %d units lesioned: PctCor: %.4g", ly.Name, n, len(ly.Neurons), pc)
+}
+
+// LesionSweep tests all the items with increasing fractions of the units in
+// the LesionLayer lesioned, from 0 to 1 in the given number of steps, and
+// plots the proportion correct (PctCor) vs. the fraction lesioned in the
+// LesionSweep plot, to show how performance degrades with damage.  The units
+// are lesioned in the same random order (from the LesionSeed) at each step,
+// so each lesion includes all the units of the smaller ones.  All of the
+// units are restored at the end.
+func (ss *Sim) LesionSweep(steps int) error { //types:add
+	ly := ss.Net.LayerByName(ss.LesionLayer)
+	if ly == nil {
+		return fmt.Errorf("LesionSweep: layer %q not found", ss.LesionLayer)
+	}
+	if ss.GUI.Body == nil {
+		ss.lesionSweep(ly, steps)
+		return nil
+	}
+	if ss.GUI.IsRunning {
+		return nil
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.lesionSweep(ly, steps)
+		ss.GUI.Stopped()
+	}()
+	return nil
+}
+
+func (ss *Sim) lesionSweep(ly *leabra.Layer, steps int) {
+	steps = max(steps, 1)
+	dt := ss.Logs.MiscTable("LesionSweep")
+	dt.SetNumRows(0)
+	for s := range steps + 1 {
+		frac := float32(s) / float32(steps)
+		n := ss.lesionUnits(ly, frac)
+		pc := ss.testPctCor()
+		dt.SetNumRows(s + 1)
+		dt.SetFloat("Frac", s, float64(frac))
+		dt.SetFloat("NLesioned", s, float64(n))
+		dt.SetFloat("PctCor", s, pc)
+		if plt := ss.GUI.PlotByName("LesionSweep"); plt != nil {
+			plt.GoUpdatePlot()
+		}
+		if ss.GUI.StopNow {
+			break
+		}
+	}
+	ss.lesionUnits(ly, 0)
+}
+
+// lesionUnits restores all of the units in the layer, and then lesions
+// the given fraction of them, in a random order determined by the
+// LesionSeed, returning the number lesioned.
+func (ss *Sim) lesionUnits(ly *leabra.Layer, frac float32) int {
+	ly.UnLesionNeurons()
+	nn := len(ly.Neurons)
+	n := min(int(frac*float32(nn)+0.5), nn)
+	perm := rand.New(rand.NewSource(ss.LesionSeed)).Perm(nn)
+	for _, ni := range perm[:n] {
+		ly.Neurons[ni].SetFlag(true, leabra.NeurOff)
+	}
+	ss.Net.InitActs()
+	return n
+}
+
+// testPctCor tests all the items and returns the resulting PctCor,
+// keeping the previous test activity for the Drift stat, so that
+// the lesion tests do not count as drift.
+func (ss *Sim) testPctCor() float64 {
+	prev := ss.driftPrev
+	ss.GUI.StopNow = false
+	ss.TestAll()
+	ss.driftPrev = prev
+	dt := ss.Logs.Table(etime.Test, etime.Epoch)
+	if dt.Rows == 0 {
+		return 0
+	}
+	return dt.Float("PctCor", dt.Rows-1)
+}
+
+// ConfigLesionSweep configures the table of results from LesionSweep.
+func (ss *Sim) ConfigLesionSweep(dt *table.Table) {
+	dt.SetMetaData("name", "LesionSweep")
+	dt.SetMetaData("desc", "proportion correct as a function of the fraction of LesionLayer units lesioned")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Frac")
+	dt.AddFloat64Column("NLesioned")
+	dt.AddFloat64Column("PctCor")
+}
+
+// ConfigLesionSweepPlot configures the plot of the LesionSweep table.
+func (ss *Sim) ConfigLesionSweepPlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("LesionSweep"), "LesionSweep Plot")
+	plt.Options.Title = "Accuracy vs. Fraction of Units Lesioned"
+	plt.Options.XAxis = "Frac"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable("LesionSweep"))
+	plt.SetColumnOptions("PctCor", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+	plt.SetColumnOptions("NLesioned", plotcore.Off, plotcore.FixMin, 0, plotcore.FloatMax, 0)
+}
@@ -8,6 +8,6 @@ import (
 
 var _ = types.AddType(&types.Type{Name: "main.LearnType", IDName: "learn-type", Doc: "LearnType is the type of learning to use"})
 
-var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}}})
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}, {Name: "Smooth", Doc: "compute the PctErr_Smooth moving average of the PctErr learning curve,\nto make the overall trend easier to see in noisy learning curves."}, {Name: "SmoothWindow", Doc: "number of epochs in the PctErr_Smooth moving average window."}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Learn", Doc: "select which type of learning to use"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Patterns", Doc: "family trees training patterns"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "Lesion", Doc: "Lesion lesions the given fraction (0-1) of the units in the LesionLayer,\nchosen at random using the LesionSeed, so the same units are lesioned\nfor a given seed, and tests all the items, reporting the proportion\ncorrect (PctCor).  The lesion stays in place until Lesion is called\nagain, e.g., with 0 to restore all of the units.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"frac"}, Returns: []string{"error"}}, {Name: "LesionSweep", Doc: "LesionSweep tests all the items with increasing fractions of the units in\nthe LesionLayer lesioned, from 0 to 1 in the given number of steps, and\nplots the proportion correct (PctCor) vs. the fraction lesioned in the\nLesionSweep plot, to show how performance degrades with damage.  The units\nare lesioned in the same random order (from the LesionSeed) at each step,\nso each lesion includes all the units of the smaller ones.  All of the\nunits are restored at the end.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"steps"}, Returns: []string{"error"}}}, Fields: []types.Field{{Name: "Learn", Doc: "select which type of learning to use"}, {Name: "HeatmapLayer", Doc: "layer whose activity for each test trial is shown in the ActHeatmap tab,\nwhich must be one of the layers with ActM in the Test Trial log"}, {Name: "DecodeLayer", Doc: "layer whose activity is decoded by a linear classifier for the Decode\nstat, which must be one of the layers with ActM in the Test Trial log"}, {Name: "DecodeTarget", Doc: "layer whose active unit is the category decoded for the Decode stat:\nAgent, Relation, or Patient"}, {Name: "DecodeFolds", Doc: "number of cross-validation folds for the Decode stat"}, {Name: "DriftLayer", Doc: "layer whose change in activity for each item between test epochs\nis the Drift stat, which must be one of the layers with ActM\nin the Test Trial log"}, {Name: "LesionLayer", Doc: "layer whose units are lesioned by Lesion and LesionSweep,\nto test the graceful degradation of its distributed representation"}, {Name: "LesionSeed", Doc: "random seed for choosing which units are lesioned,\nso the same units are lesioned for a given seed"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Patterns", Doc: "family trees training patterns"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
@@ -11,6 +11,7 @@ package main
 
 import (
 	"embed"
+	"math/rand"
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
@@ -19,6 +20,8 @@ import (
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/envstate"
+	"github.com/compcogneuro/sims/v2/gifmovie"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -34,7 +37,6 @@ import (
 	"github.com/emer/etensor/tensor/stats/metric"
 	"github.com/emer/etensor/tensor/stats/norm"
 	"github.com/emer/etensor/tensor/table"
-	"golang.org/x/exp/rand"
 
 	//	"github.com/emer/etable/split"
 	"github.com/emer/leabra/v2/leabra"
@@ -212,6 +214,14 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// weight movie being recorded, see StartWeightMovie,
+	// and the pathway being filmed
+	wtMovie     *gifmovie.Movie
+	wtMoviePath *leabra.Path
+
+	// reused input tensors for ApplyInputs
+	inputs envstate.Buffers
 }
 
 // New creates new blank elements and initializes defaults
@@ -261,6 +271,8 @@ func (ss *Sim) ConfigEnv() {
 		tst = ss.Envs.ByMode(etime.Test).(*env.FixedTable)
 	}
 
+	// note: must use math/rand, which is seeded by RandSeeds in InitRandSeed,
+	// so the split follows the seed, and New Seed gives a different one.
 	n := ss.Lines2.Rows
 	order := rand.Perm(n)
 	ntrn := int(0.85 * float64(n))
@@ -348,6 +360,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -399,6 +412,7 @@ func (ss *Sim) ConfigLoops() {
 			ss.TestAll()
 		}
 	})
+	trainEpoch.OnEnd.Add("WeightMovie", ss.WeightMovieFrame)
 
 	/////////////////////////////////////////////
 	// Logging
@@ -457,7 +471,7 @@ func (ss *Sim) ApplyInputs() {
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
+		pats := ss.inputs.State(ev, ly.Name)
 		if pats != nil {
 			ly.ApplyExt(pats)
 		}
@@ -481,11 +495,18 @@ func (ss *Sim) NewRun() {
 	ss.HiddenFromInput()
 }
 
-// TestAll runs through the full set of testing items
-func (ss *Sim) TestAll() {
+// TestAll runs through the full set of testing items, and returns
+// the accuracy, as the proportion of testing items that are correct
+// (PctCor in the Test Epoch log, from 0 to 1).
+func (ss *Sim) TestAll() float64 {
 	ss.Envs.ByMode(etime.Test).Init(0)
 	ss.Loops.ResetAndRun(etime.Test)
 	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
+	dt := ss.Logs.Table(etime.Test, etime.Epoch)
+	if dt.Rows == 0 {
+		return 0
+	}
+	return dt.Float("PctCor", dt.Rows-1)
 }
 
 /////////////////////////////////////////////////////////////////////
@@ -704,6 +725,22 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.GUI.UpdatePlot(etime.Test, etime.Run)
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Start Weight Movie",
+		Icon:    icons.Movie,
+		Tooltip: "Start recording the weights of the given pathway at the end of each training epoch, as a movie of the weights evolving over learning",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.StartWeightMovie)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Save Weight Movie",
+		Icon:    icons.Save,
+		Tooltip: "Save the weight movie recorded since Start Weight Movie as an animated GIF, and stop recording",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.SaveWeightMovie)
+		},
+	})
 	////////////////////////////////////////////////
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "New Seed",
@@ -0,0 +1,64 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/compcogneuro/sims/v2/simtest"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// newTestSim returns a configured Sim, without the GUI.
+func newTestSim(t *testing.T) *Sim {
+	ss := &Sim{}
+	simtest.Config(t, ss)
+	return ss
+}
+
+// runState returns the training items of the train/test split,
+// and the initial weights from Input to Hidden, after Init.
+func runState(ss *Sim) (split []int, wts []float32) {
+	ss.Init()
+	split = slices.Clone(ss.Envs.ByMode(etime.Train).(*env.FixedTable).Table.Indexes)
+	pt := ss.Net.LayerByName("Hidden").RecvPaths[0]
+	for si := range pt.Syns {
+		wts = append(wts, pt.Syns[si].Wt)
+	}
+	return
+}
+
+// TestSeeds checks that runs with the same seeds have the same train/test
+// split and initial weights, and that runs with different seeds diverge.
+func TestSeeds(t *testing.T) {
+	ss := newTestSim(t)
+	split, wts := runState(ss)
+
+	split2, wts2 := runState(ss)
+	if !slices.Equal(split, split2) {
+		t.Error("same seed: train/test split differs")
+	}
+	if !slices.Equal(wts, wts2) {
+		t.Error("same seed: initial weights differ")
+	}
+
+	ss.RandSeeds.NewSeeds()
+	split3, wts3 := runState(ss)
+	if slices.Equal(split, split3) {
+		t.Error("new seed: train/test split is the same")
+	}
+	if slices.Equal(wts, wts3) {
+		t.Error("new seed: initial weights are the same")
+	}
+}
+
+// TestInitClearsLogs checks that Init after training clears the Train
+// logs and the accumulated Stats, so the plots start out empty.
+func TestInitClearsLogs(t *testing.T) {
+	ss := newTestSim(t)
+	simtest.InitClearsLogs(t, ss.Init, ss.Loops, &ss.Logs, &ss.Stats)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// ExportWeightsNPY writes the weights of the pathway from send to recv
+// layer to given file in NumPy .npy format, for analysis in Python.
+// The array shape is the recv layer shape followed by the send layer shape,
+// so a 2D recv and 2D send layer give a 4D [ry, rx, sy, sx] array.
+// Missing connections are written as NaN.
+func (ss *Sim) ExportWeightsNPY(path, send, recv string) error { //types:add
+	rly := ss.Net.LayerByName(recv)
+	if rly == nil {
+		return fmt.Errorf("ExportWeightsNPY: recv layer %q not found", recv)
+	}
+	ept, err := rly.RecvPathBySendName(send)
+	if err != nil {
+		return err
+	}
+	return WritePathNPY(path, ept.(*leabra.Path))
+}
+
+// ExportAllWeightsNPY writes the weights of every pathway in the network
+// to the given directory, one <Send>To<Recv>.npy file per pathway.
+func (ss *Sim) ExportAllWeightsNPY(dir string) error { //types:add
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			fn := filepath.Join(dir, pt.Send.Name+"To"+pt.Recv.Name+".npy")
+			if err := WritePathNPY(fn, pt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WritePathNPY writes the Wt values of given pathway to given file,
+// as a full recv x send matrix shaped by the layer geometries.
+func WritePathNPY(fn string, pt *leabra.Path) error {
+	rsz := pt.Recv.Shape.Sizes
+	ssz := pt.Send.Shape.Sizes
+	rn := len(pt.Recv.Neurons)
+	sn := len(pt.Send.Neurons)
+	if pt.Recv.Shape.Len() != rn || pt.Send.Shape.Len() != sn {
+		return fmt.Errorf("WritePathNPY: layer shapes %v, %v do not match neuron counts %d, %d for path %sTo%s", rsz, ssz, rn, sn, pt.Send.Name, pt.Recv.Name)
+	}
+	shape := append(append([]int{}, rsz...), ssz...)
+	vals := make([]float32, rn*sn)
+	for ri := 0; ri < rn; ri++ {
+		for si := 0; si < sn; si++ {
+			vals[ri*sn+si] = pt.SynValue("Wt", si, ri)
+		}
+	}
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	err = WriteNPY(bw, shape, vals)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteNPY writes the given float32 values as a C-ordered array with
+// given shape, in NumPy .npy format version 1.0.
+func WriteNPY(w io.Writer, shape []int, vals []float32) error {
+	n := 1
+	dims := make([]string, len(shape))
+	for i, s := range shape {
+		n *= s
+		dims[i] = fmt.Sprintf("%d", s)
+	}
+	if n != len(vals) {
+		return fmt.Errorf("WriteNPY: shape %v does not match number of values %d", shape, len(vals))
+	}
+	shp := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shp += ","
+	}
+	hdr := fmt.Sprintf("{'descr': '<f4', 'fortran_order': False, 'shape': (%s), }", shp)
+	// magic (6) + version (2) + header len (2) + header + newline, padded to 64 bytes
+	pad := 64 - (10+len(hdr)+1)%64
+	if pad == 64 {
+		pad = 0
+	}
+	hdr += strings.Repeat(" ", pad) + "\n"
+	if _, err := w.Write([]byte("\x93NUMPY\x01\x00")); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(hdr))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, hdr); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, vals)
+}
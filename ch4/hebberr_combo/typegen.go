@@ -10,4 +10,4 @@ var _ = types.AddType(&types.Type{Name: "main.LearnType", IDName: "learn-type",
 
 var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Learn", Doc: "select which type of learning to use"}, {Name: "AvgLGain", Doc: "key BCM hebbian learning parameter, that determines how high the\nfloating threshold goes -- higher = more homeostatic pressure\nagainst rich-get-richer feedback loops."}, {Name: "InputNoise", Doc: "variance on gaussian noise to add to inputs."}, {Name: "TrainGi", Doc: "strength of inhibition during training with two lines present in input."}, {Name: "TestGi", Doc: "strength of inhibition during testing with one line present in input;\nhigher because fewer neurons should be active."}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Lines2", Doc: "2 active lines for training"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "ExportWeightsNPY", Doc: "ExportWeightsNPY writes the weights of the pathway from send to recv\nlayer to given file in NumPy .npy format, for analysis in Python.\nThe array shape is the recv layer shape followed by the send layer shape,\nso a 2D recv and 2D send layer give a 4D [ry, rx, sy, sx] array.\nMissing connections are written as NaN.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"path", "send", "recv"}, Returns: []string{"error"}}, {Name: "ExportAllWeightsNPY", Doc: "ExportAllWeightsNPY writes the weights of every pathway in the network\nto the given directory, one <Send>To<Recv>.npy file per pathway.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"dir"}, Returns: []string{"error"}}, {Name: "StartWeightMovie", Doc: "StartWeightMovie starts recording the weights of the pathway from\nsend to recv layer at the end of every training epoch, as frames of\na movie that is saved with SaveWeightMovie.  Each frame is a heatmap\nlike the Weights grid: the weights into each recv unit are shown as an\nimage of the send layer, arranged by recv unit position.\nfps is the number of frames (epochs) shown per second, up to 50.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"send", "recv", "fps"}, Returns: []string{"error"}}, {Name: "SaveWeightMovie", Doc: "SaveWeightMovie saves the weight movie recorded since StartWeightMovie\nto the given file as an animated GIF, and stops recording.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}}, Fields: []types.Field{{Name: "Learn", Doc: "select which type of learning to use"}, {Name: "AvgLGain", Doc: "key BCM hebbian learning parameter, that determines how high the\nfloating threshold goes -- higher = more homeostatic pressure\nagainst rich-get-richer feedback loops."}, {Name: "InputNoise", Doc: "variance on gaussian noise to add to inputs."}, {Name: "TrainGi", Doc: "strength of inhibition during training with two lines present in input."}, {Name: "TestGi", Doc: "strength of inhibition during testing with one line present in input;\nhigher because fewer neurons should be active."}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Lines2", Doc: "2 active lines for training"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
@@ -0,0 +1,106 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/math32"
+	"github.com/compcogneuro/sims/v2/gifmovie"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// StartWeightMovie starts recording the weights of the pathway from
+// send to recv layer at the end of every training epoch, as frames of
+// a movie that is saved with SaveWeightMovie.  Each frame is a heatmap
+// like the Weights grid: the weights into each recv unit are shown as an
+// image of the send layer, arranged by recv unit position.
+// fps is the number of frames (epochs) shown per second, up to 50.
+func (ss *Sim) StartWeightMovie(send, recv string, fps int) error { //types:add
+	rly := ss.Net.LayerByName(recv)
+	if rly == nil {
+		return fmt.Errorf("StartWeightMovie: recv layer %q not found", recv)
+	}
+	pt, err := rly.RecvPathBySendName(send)
+	if err != nil {
+		return err
+	}
+	ss.wtMoviePath = pt.(*leabra.Path)
+	ss.wtMovie = gifmovie.New(fps)
+	ss.WeightMovieFrame() // initial weights
+	return nil
+}
+
+// SaveWeightMovie saves the weight movie recorded since StartWeightMovie
+// to the given file as an animated GIF, and stops recording.
+func (ss *Sim) SaveWeightMovie(filename core.Filename) error { //types:add
+	movie := ss.wtMovie
+	if movie == nil || movie.Frames() == 0 {
+		return fmt.Errorf("SaveWeightMovie: no weight movie has been recorded: use StartWeightMovie first")
+	}
+	ss.wtMovie = nil
+	return movie.Save(string(filename))
+}
+
+// WeightMovieFrame adds the current weights as a frame of the movie
+// being recorded, if any.  Called at the end of every training epoch.
+func (ss *Sim) WeightMovieFrame() {
+	if ss.wtMovie == nil {
+		return
+	}
+	ss.wtMovie.AddFrame(WeightImage(ss.wtMoviePath, 4))
+}
+
+// wtPalette has the background color at index 0,
+// followed by a black (0) to white (1) weight scale.
+var wtPalette = func() color.Palette {
+	pal := color.Palette{color.RGBA{0x40, 0x60, 0x80, 0xff}}
+	for i := range 255 {
+		v := uint8(255 * i / 254)
+		pal = append(pal, color.Gray{v})
+	}
+	return pal
+}()
+
+// WeightImage returns a heatmap image of the weights of given pathway,
+// with pix pixels per weight.  The weights into each recv unit are drawn
+// as an image of the send layer, and these are arranged according to the
+// recv layer geometry, with y = 0 at the bottom, as in the NetView.
+func WeightImage(pt *leabra.Path, pix int) *image.Paletted {
+	rx, ry := layer2D(pt.Recv)
+	sx, sy := layer2D(pt.Send)
+	wd := (rx*(sx+1) + 1) * pix
+	ht := (ry*(sy+1) + 1) * pix
+	img := image.NewPaletted(image.Rect(0, 0, wd, ht), wtPalette)
+	for ri := range pt.Recv.Neurons {
+		rux, ruy := ri%rx, ri/rx
+		for si := range pt.Send.Neurons {
+			v := pt.SynValue("Wt", si, ri)
+			if math32.IsNaN(v) {
+				continue // not connected
+			}
+			ci := 1 + uint8(254*math32.Clamp(v, 0, 1))
+			x := (1 + rux*(sx+1) + si%sx) * pix
+			y := ht - (1+ruy*(sy+1)+si/sx+1)*pix
+			for yi := y; yi < y+pix; yi++ {
+				for xi := x; xi < x+pix; xi++ {
+					img.SetColorIndex(xi, yi, ci)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// layer2D returns the size of given layer as a 2D grid of units,
+// using the last dimension as X.
+func layer2D(ly *leabra.Layer) (x, y int) {
+	sz := ly.Shape.Sizes
+	x = sz[len(sz)-1]
+	return x, len(ly.Neurons) / x
+}
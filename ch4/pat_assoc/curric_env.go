@@ -0,0 +1,127 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// CurricStage is one stage of a CurricEnv curriculum.
+type CurricStage struct {
+
+	// name of the stage, e.g., Easy, reported in the CurricStage stat
+	Name string
+
+	// number of epochs to present this stage
+	Epochs int `min:"1"`
+
+	// names of the patterns (trial names) presented in this stage;
+	// all of the patterns are presented if empty
+	Patterns []string
+}
+
+// CurricEnv is a curriculum environment, which wraps a FixedTable to present
+// its patterns in staged subsets, e.g., easy patterns first and then harder
+// ones, according to the Schedule.  The active subset is advanced at the
+// start of each epoch, based on the Epoch count, which is incremented
+// each time through all of the patterns.  With an empty Schedule,
+// all of the patterns are presented, as in the FixedTable.
+type CurricEnv struct {
+	env.FixedTable
+
+	// curriculum schedule of stages, in order.  The last stage remains
+	// active after all of the epochs of the earlier stages.
+	Schedule []CurricStage
+
+	// index of the active stage in the Schedule, -1 if none
+	Stage int `edit:"-"`
+
+	// number of epochs (times through the patterns) since Init
+	Epoch env.Counter `display:"inline"`
+
+	// all of the patterns, from which the subsets are selected
+	Source *table.Table `display:"-"`
+}
+
+// Config configures the env to use the given table as the source of all patterns.
+func (ce *CurricEnv) Config(dt *table.Table) {
+	ce.Source = dt
+	ce.FixedTable.Config(table.NewIndexView(dt))
+}
+
+func (ce *CurricEnv) Init(run int) {
+	ce.Epoch.Scale = etime.Epoch
+	ce.Epoch.Init()
+	ce.SetStage(ce.StageForEpoch(0))
+	ce.FixedTable.Init(run)
+}
+
+func (ce *CurricEnv) Step() bool {
+	if ce.Trial.Cur >= 0 && ce.Trial.Cur+1 >= ce.Trial.Max { // last trial of the epoch
+		ce.Epoch.Incr()
+	}
+	ce.FixedTable.Step()
+	if ce.Trial.Cur == 0 { // new epoch
+		if st := ce.StageForEpoch(ce.Epoch.Cur); st != ce.Stage {
+			ce.SetStage(st)
+			ce.UpdateTrialName()
+		}
+	}
+	return true
+}
+
+// StageForEpoch returns the index of the stage in the Schedule
+// for the given epoch, or -1 if there is no Schedule.
+func (ce *CurricEnv) StageForEpoch(epc int) int {
+	for si, st := range ce.Schedule {
+		if epc < st.Epochs {
+			return si
+		}
+		epc -= st.Epochs
+	}
+	return len(ce.Schedule) - 1
+}
+
+// StageName returns the name of the given stage, "All" if none.
+func (ce *CurricEnv) StageName(st int) string {
+	if st < 0 {
+		return "All"
+	}
+	return ce.Schedule[st].Name
+}
+
+// StagePatterns returns an IndexView of the Source patterns for the given stage.
+func (ce *CurricEnv) StagePatterns(st int) *table.IndexView {
+	ix := table.NewIndexView(ce.Source)
+	if st < 0 || len(ce.Schedule[st].Patterns) == 0 {
+		return ix
+	}
+	pats := ce.Schedule[st].Patterns
+	ix.Filter(func(et *table.Table, row int) bool {
+		return slices.Contains(pats, et.StringValue(ce.NameCol, row))
+	})
+	return ix
+}
+
+// SetStage makes the given stage active, presenting its subset of patterns.
+func (ce *CurricEnv) SetStage(st int) {
+	ce.Stage = st
+	ce.Table = ce.StagePatterns(st)
+	ce.Trial.Max = ce.Table.Len()
+}
+
+// UpdateTrialName updates the current trial name after the patterns
+// or their order have been changed within the current step.
+// There is no current trial before the first Step.
+func (ce *CurricEnv) UpdateTrialName() {
+	if ce.Trial.Cur < 0 {
+		return
+	}
+	ce.TrialName.Cur = ce.Table.Table.StringValue(ce.NameCol, ce.Row())
+}
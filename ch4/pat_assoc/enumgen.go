@@ -6,20 +6,20 @@ import (
 	"cogentcore.org/core/enums"
 )
 
-var _PatsTypeValues = []PatsType{0, 1, 2}
+var _PatsTypeValues = []PatsType{0, 1, 2, 3}
 
 // PatsTypeN is the highest valid value for type PatsType, plus one.
 //
 //gosl:start
-const PatsTypeN PatsType = 3
+const PatsTypeN PatsType = 4
 
 //gosl:end
 
-var _PatsTypeValueMap = map[string]PatsType{`Easy`: 0, `Hard`: 1, `Impossible`: 2}
+var _PatsTypeValueMap = map[string]PatsType{`Easy`: 0, `Hard`: 1, `Impossible`: 2, `Custom`: 3}
 
-var _PatsTypeDescMap = map[PatsType]string{0: `Easy patterns can be learned by Hebbian learning`, 1: `Hard patterns can only be learned with error-driven learning`, 2: `Impossible patterns require error-driven + a hidden layer`}
+var _PatsTypeDescMap = map[PatsType]string{0: `Easy patterns can be learned by Hebbian learning`, 1: `Hard patterns can only be learned with error-driven learning`, 2: `Impossible patterns require error-driven + a hidden layer`, 3: `Custom patterns are imported from a CSV file, see ImportCSV`}
 
-var _PatsTypeMap = map[PatsType]string{0: `Easy`, 1: `Hard`, 2: `Impossible`}
+var _PatsTypeMap = map[PatsType]string{0: `Easy`, 1: `Hard`, 2: `Impossible`, 3: `Custom`}
 
 // String returns the string representation of this PatsType value.
 func (i PatsType) String() string { return enums.String(i, _PatsTypeMap) }
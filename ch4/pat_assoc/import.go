@@ -0,0 +1,136 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// ImportCSV reads patterns from a generic CSV file with a header row,
+// using the mapping spec to assign CSV columns to network layers,
+// and selects the resulting Custom patterns for training and testing.
+// The mapping has the form: "Input: a, b, c, d; Output: x, y; Name: label"
+// where each layer lists the CSV columns that fill its units in order,
+// and the optional Name entry gives the column used for the trial name.
+// The number of columns mapped to a layer must match its number of units.
+func (ss *Sim) ImportCSV(filename core.Filename, mapping string) error { //types:add
+	dt, err := ss.ReadCSVPatterns(string(filename), mapping)
+	if err != nil {
+		return err
+	}
+	ss.Custom = dt
+	ss.Patterns = Custom
+	ss.UpdateEnv()
+	ss.Envs.ByMode(etime.Train).Init(0)
+	ss.Envs.ByMode(etime.Test).Init(0)
+	return nil
+}
+
+// ReadCSVPatterns reads the given CSV file into a patterns table in the
+// standard format, with a Name column and one tensor column per mapped layer,
+// shaped according to the layer geometry. See ImportCSV for the mapping format.
+func (ss *Sim) ReadCSVPatterns(filename, mapping string) (*table.Table, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	recs, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) < 2 {
+		return nil, fmt.Errorf("ImportCSV: %s must have a header row and at least one data row", filename)
+	}
+	hdr := recs[0]
+	colIndex := func(nm string) (int, error) {
+		ci := slices.Index(hdr, nm)
+		if ci < 0 {
+			return ci, fmt.Errorf("ImportCSV: column %q not found in %s", nm, filename)
+		}
+		return ci, nil
+	}
+
+	nameCol := -1
+	var lays []string
+	cols := map[string][]int{}
+	for _, ent := range strings.Split(mapping, ";") {
+		ent = strings.TrimSpace(ent)
+		if ent == "" {
+			continue
+		}
+		lnm, cnms, ok := strings.Cut(ent, ":")
+		if !ok {
+			return nil, fmt.Errorf("ImportCSV: mapping entry %q must have the form Layer: col1, col2, ...", ent)
+		}
+		lnm = strings.TrimSpace(lnm)
+		var cis []int
+		for _, cnm := range strings.Split(cnms, ",") {
+			ci, err := colIndex(strings.TrimSpace(cnm))
+			if err != nil {
+				return nil, err
+			}
+			cis = append(cis, ci)
+		}
+		if lnm == "Name" {
+			if len(cis) != 1 {
+				return nil, fmt.Errorf("ImportCSV: Name must map to a single column")
+			}
+			nameCol = cis[0]
+			continue
+		}
+		ly := ss.Net.LayerByName(lnm)
+		if ly == nil {
+			return nil, fmt.Errorf("ImportCSV: layer %q not found in network", lnm)
+		}
+		if nu := ly.Shape.Len(); len(cis) != nu {
+			return nil, fmt.Errorf("ImportCSV: layer %s has %d units but %d columns are mapped to it", lnm, nu, len(cis))
+		}
+		lays = append(lays, lnm)
+		cols[lnm] = cis
+	}
+	if len(lays) == 0 {
+		return nil, fmt.Errorf("ImportCSV: mapping does not assign columns to any layer")
+	}
+
+	rows := recs[1:]
+	dt := table.NewTable()
+	dt.SetMetaData("name", "Custom")
+	dt.SetMetaData("desc", "Custom patterns imported from "+filename)
+	dt.AddStringColumn("Name")
+	tsrs := make([]*tensor.Float32, len(lays))
+	for i, lnm := range lays {
+		tsrs[i] = dt.AddFloat32TensorColumn(lnm, ss.Net.LayerByName(lnm).Shape.Sizes, "Y", "X")
+	}
+	dt.SetNumRows(len(rows))
+	for ri, rec := range rows {
+		nm := fmt.Sprintf("Event_%d", ri)
+		if nameCol >= 0 {
+			nm = rec[nameCol]
+		}
+		dt.SetString("Name", ri, nm)
+		for i, lnm := range lays {
+			cis := cols[lnm]
+			for ui, ci := range cis {
+				v, err := strconv.ParseFloat(strings.TrimSpace(rec[ci]), 32)
+				if err != nil {
+					return nil, fmt.Errorf("ImportCSV: row %d, column %q: %w", ri+1, hdr[ci], err)
+				}
+				tsrs[i].SetFloat1D(ri*len(cis)+ui, v)
+			}
+		}
+	}
+	return dt, nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// CompareLearningRates trains one run with a high learning rate (e.g., 1,
+// for nearly one-shot learning), and another with a low learning rate
+// (e.g., 0.01, for gradual learning), from the same initial weights, and
+// overlays their learning curves (SSE per epoch) in the CompareLrates plot.
+// The SSE per epoch of each run is kept in the LrateHigh and LrateLow
+// Stats tensors (Stats has no tables), and the CompareLrates log table
+// that is plotted has both.  The learning rates of the network are
+// restored afterwards.  The summary reports the final training SSE, and the SSE on a
+// final test, which differs from training when TestNoise is set, as a
+// test of generalization: a larger increase in SSE from training to test
+// for the high rate would indicate overfitting to the exact patterns.
+func (ss *Sim) CompareLearningRates(high, low float32) { //types:add
+	if ss.GUI.Body == nil {
+		fmt.Println(ss.RunCompareLrates(high, low))
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		sum := ss.RunCompareLrates(high, low)
+		ss.GUI.Stopped()
+		ss.GUI.Body.AsyncLock()
+		core.MessageDialog(ss.GUI.Body, sum, "Compare Learning Rates")
+		ss.GUI.Body.AsyncUnlock()
+	}()
+}
+
+// RunCompareLrates runs the training for CompareLearningRates, and returns
+// the summary, with the final SSEs also set in the High/Low SSE and TstSSE
+// stats (e.g., HighSSE, LowTstSSE).
+func (ss *Sim) RunCompareLrates(high, low float32) string {
+	// the param sheets do not set Lrate, so save the current rates to restore
+	var orig []float32
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			orig = append(orig, pt.Learn.Lrate)
+		}
+	}
+	sum := ""
+	var curves []*tensor.Float64
+	for _, lr := range []struct {
+		name string
+		rate float32
+	}{{"High", high}, {"Low", low}} {
+		sse, tsse, curve := ss.trainLrate(lr.rate)
+		curves = append(curves, curve)
+		ss.Stats.SetFloat(lr.name+"SSE", sse)
+		ss.Stats.SetFloat(lr.name+"TstSSE", tsse)
+		sum += fmt.Sprintf("%s learning rate %g: final SSE: %.4g, test SSE: %.4g (difference: %.4g)\n", lr.name, lr.rate, sse, tsse, tsse-sse)
+	}
+	i := 0
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			pt.Learn.Lrate = orig[i]
+			pt.Learn.LrateInit = orig[i]
+			i++
+		}
+	}
+
+	// set after both runs, as the Init of each run resets them
+	hi, lo := curves[0], curves[1]
+	ss.Stats.SetF64Tensor("LrateHigh", hi)
+	ss.Stats.SetF64Tensor("LrateLow", lo)
+	dt := ss.Logs.MiscTable("CompareLrates")
+	nr := max(hi.Len(), lo.Len())
+	dt.SetNumRows(nr)
+	for r := range nr {
+		dt.SetFloat("Epoch", r, float64(r))
+		for _, lr := range []struct {
+			name string
+			sse  *tensor.Float64
+		}{{"High", hi}, {"Low", lo}} {
+			sse := math.NaN() // run stopped early
+			if r < lr.sse.Len() {
+				sse = lr.sse.Float1D(r)
+			}
+			dt.SetFloat(lr.name+"SSE", r, sse)
+		}
+	}
+	if plt := ss.GUI.PlotByName("CompareLrates"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+	return sum
+}
+
+// trainLrate trains one run from the start with the given learning rate on
+// all paths, and returns the final training SSE, the SSE on a final test,
+// and the SSE per epoch.
+func (ss *Sim) trainLrate(lrate float32) (sse, tstSSE float64, curve *tensor.Float64) {
+	ss.Init() // same seed and initial weights for each rate
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			pt.Learn.Lrate = lrate
+		}
+	}
+	ss.GUI.StopNow = false
+	ss.Loops.Loop(etime.Train, etime.Run).Counter.Max = 1
+	ss.Loops.Run(etime.Train)
+	ss.Loops.Loop(etime.Train, etime.Run).Counter.Max = ss.Config.NRuns
+
+	epc := ss.Logs.Table(etime.Train, etime.Epoch)
+	curve = tensor.NewFloat64([]int{epc.Rows})
+	for r := range epc.Rows {
+		curve.SetFloat1D(r, epc.Float("SSE", r))
+	}
+	if epc.Rows > 0 {
+		sse = epc.Float("SSE", epc.Rows-1)
+	}
+	ss.TestAll()
+	tst := ss.Logs.Table(etime.Test, etime.Epoch)
+	if tst.Rows > 0 {
+		tstSSE = tst.Float("SSE", tst.Rows-1)
+	}
+	return
+}
+
+// ConfigCompareLrates configures the table of learning curves
+// from CompareLearningRates.
+func (ss *Sim) ConfigCompareLrates(dt *table.Table) {
+	dt.SetMetaData("name", "CompareLrates")
+	dt.SetMetaData("desc", "training SSE per epoch for a high and a low learning rate")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Epoch")
+	dt.AddFloat64Column("HighSSE")
+	dt.AddFloat64Column("LowSSE")
+}
+
+// ConfigCompareLratesPlot configures the plot of the CompareLrates table.
+func (ss *Sim) ConfigCompareLratesPlot() {
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey("CompareLrates"), "CompareLrates Plot")
+	plt.Options.Title = "Learning Curves for High vs. Low Learning Rates"
+	plt.Options.XAxis = "Epoch"
+	plt.SetTable(ss.Logs.MiscTable("CompareLrates"))
+	plt.SetColumnOptions("HighSSE", plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 1)
+	plt.SetColumnOptions("LowSSE", plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 1)
+}
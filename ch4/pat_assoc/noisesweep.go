@@ -0,0 +1,46 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/compcogneuro/sims/v2/noise"
+)
+
+// NoiseSweep tests the current network with the TestNoise level set to
+// each of steps+1 levels from 0 to maxLevel, recording the test accuracy
+// for each level in the NoiseSweep table and plot.  Train the network
+// first, so the sweep shows how robust its learning is to noise.
+// In the GUI, the tests happen in the background; without the GUI the
+// results are printed.
+func (ss *Sim) NoiseSweep(maxLevel float32, steps int) { //types:add
+	if ss.GUI.Body == nil {
+		ss.RunNoiseSweep(maxLevel, steps)
+		dt := ss.Logs.MiscTable("NoiseSweep")
+		for ri := range dt.Rows {
+			fmt.Printf("Level: %g\tPctCor: %g\n", dt.Float("Level", ri), dt.Float("PctCor", ri))
+		}
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.RunNoiseSweep(maxLevel, steps)
+		ss.GUI.Stopped()
+	}()
+}
+
+// RunNoiseSweep runs the tests for NoiseSweep, and updates the plot.
+func (ss *Sim) RunNoiseSweep(maxLevel float32, steps int) {
+	dt := ss.Logs.MiscTable("NoiseSweep")
+	ss.TestNoise.Sweep(dt, noise.Levels(maxLevel, steps), ss.TestAll)
+	if plt := ss.GUI.PlotByName("NoiseSweep"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+}
@@ -10,15 +10,20 @@ package main
 //go:generate core generate -add-types
 
 import (
+	"bytes"
 	"embed"
+	"math/rand"
 
 	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/fileinfo/mimedata"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/envstate"
+	"github.com/compcogneuro/sims/v2/noise"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -30,6 +35,8 @@ import (
 	"github.com/emer/emergent/v2/netview"
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor"
 	"github.com/emer/etensor/tensor/table"
 	"github.com/emer/leabra/v2/leabra"
 )
@@ -52,6 +59,9 @@ const (
 
 	// Impossible patterns require error-driven + a hidden layer
 	Impossible
+
+	// Custom patterns are imported from a CSV file, see ImportCSV
+	Custom
 )
 
 // LearnType is the type of learning to use
@@ -121,6 +131,16 @@ type Config struct {
 	// how often to run through all the test patterns, in terms of training epochs.
 	// can use 0 or -1 for no testing.
 	TestInterval int `default:"5"`
+
+	// present the training trials in a new permuted order on each epoch,
+	// derived from the random seed for the run, so that a given seed always
+	// reproduces the exact same sequence of trials.  If off, training trials
+	// are presented sequentially, as in testing.
+	PermuteTrain bool `default:"true"`
+
+	// name of the final run stat in the Train Run log that is averaged
+	// over runs by RunAndAverage, e.g., FirstZero, LastZero, PctCor
+	AvgStat string `default:"FirstZero"`
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -136,6 +156,14 @@ type Sim struct {
 	// select which type of patterns to use
 	Patterns PatsType
 
+	// curriculum schedule for training, presenting subsets of the patterns
+	// in stages, e.g., easy ones first.  Empty = all patterns on every epoch.
+	Curriculum []CurricStage
+
+	// noise added to the input patterns during testing, to test the
+	// robustness of the learned associations; see NoiseSweep.
+	TestNoise noise.Params `display:"add-fields"`
+
 	// Config contains misc configuration parameters for running the sim
 	Config Config `new-window:"+" display:"no-inline"`
 
@@ -151,6 +179,8 @@ type Sim struct {
 	Hard *table.Table `new-window:"+" display:"no-inline"`
 	// impossible training patterns
 	Impossible *table.Table `new-window:"+" display:"no-inline"`
+	// custom training patterns, imported from a CSV file
+	Custom *table.Table `new-window:"+" display:"no-inline"`
 
 	// contains looper control loops for running sim
 	Loops *looper.Stacks `new-window:"+" display:"no-inline"`
@@ -175,6 +205,12 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// random generator for the training trial order, seeded for each run
+	orderRand *rand.Rand
+
+	// reused input tensors for ApplyInputs
+	inputs envstate.Buffers
 }
 
 // New creates new blank elements and initializes defaults
@@ -188,9 +224,11 @@ func (ss *Sim) New() {
 	ss.Easy = &table.Table{}
 	ss.Hard = &table.Table{}
 	ss.Impossible = &table.Table{}
+	ss.Custom = &table.Table{}
 	ss.RandSeeds.Init(100) // max 100 runs
 	ss.InitRandSeed(0)
 	ss.Context.Defaults()
+	ss.TestNoise.Defaults()
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -219,18 +257,20 @@ func (ss *Sim) OpenPatterns() {
 
 func (ss *Sim) ConfigEnv() {
 	// Can be called multiple times -- don't re-create
-	var trn, tst *env.FixedTable
+	var trn *CurricEnv
+	var tst *env.FixedTable
 	if len(ss.Envs) == 0 {
-		trn = &env.FixedTable{}
+		trn = &CurricEnv{}
 		tst = &env.FixedTable{}
 	} else {
-		trn = ss.Envs.ByMode(etime.Train).(*env.FixedTable)
+		trn = ss.Envs.ByMode(etime.Train).(*CurricEnv)
 		tst = ss.Envs.ByMode(etime.Test).(*env.FixedTable)
 	}
 
 	// note: names must be standard here!
 	trn.Name = etime.Train.String()
-	trn.Config(table.NewIndexView(ss.Easy))
+	trn.Config(ss.Easy)
+	trn.Sequential = true // permuted order is set by PermuteTrain
 	trn.Validate()
 
 	tst.Name = etime.Test.String()
@@ -288,6 +328,11 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
+	for _, tsr := range ss.Stats.F64Tensors {
+		tsr.SetShape([]int{0}) // e.g., the CompareLearningRates curves
+	}
+	ss.Logs.MiscTable("CompareLrates").SetNumRows(0)
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -344,6 +389,13 @@ func (ss *Sim) ConfigLoops() {
 
 	// Add Testing
 	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
+	trainEpoch.OnStart.Add("Curriculum", func() {
+		// number of trials must match the subset of patterns in the curriculum stage
+		ev := ss.Envs.ByMode(etime.Train).(*CurricEnv)
+		st := ev.StageForEpoch(trainEpoch.Counter.Cur)
+		ls.Loop(etime.Train, etime.Trial).Counter.Max = ev.StagePatterns(st).Len()
+		ss.Stats.SetString("CurricStage", ev.StageName(st))
+	})
 	trainEpoch.OnStart.Add("TestAtInterval", func() {
 		if (ss.Config.TestInterval > 0) && ((trainEpoch.Counter.Cur+1)%ss.Config.TestInterval == 0) {
 			// Note the +1 so that it doesn't occur at the 0th timestep.
@@ -383,8 +435,11 @@ func (ss *Sim) ConfigLoops() {
 func (ss *Sim) ApplyInputs() {
 	ctx := &ss.Context
 	net := ss.Net
-	ev := ss.Envs.ByMode(ctx.Mode).(*env.FixedTable)
-	ev.Step()
+	ss.Envs.ByMode(ctx.Mode).Step()
+	ev := ss.FixedEnv(ctx.Mode)
+	if ctx.Mode == etime.Train && ss.Config.PermuteTrain && ev.Trial.Cur == 0 {
+		ss.PermuteTrain() // new epoch
+	}
 
 	out := ss.Net.LayerByName("Output")
 	if ctx.Mode == etime.Test {
@@ -398,26 +453,65 @@ func (ss *Sim) ApplyInputs() {
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
-		if pats != nil {
-			ly.ApplyExt(pats)
+		st := ss.inputs.State(ev, ly.Name)
+		if st == nil {
+			continue
+		}
+		var pats tensor.Tensor = st
+		if ctx.Mode == etime.Test && ly.Type == leabra.InputLayer {
+			pats = ss.TestNoise.Add(pats, ev.Trial.Cur)
 		}
+		ly.ApplyExt(pats)
 	}
 }
 
+// PermuteTrain permutes the order of the training trials, using the
+// order random generator, which is seeded from the run seed in NewRun,
+// so that the sequence of trials is reproducible for a given seed.
+func (ss *Sim) PermuteTrain() {
+	ev := ss.Envs.ByMode(etime.Train).(*CurricEnv)
+	ix := ev.Table
+	ss.orderRand.Shuffle(len(ix.Indexes), func(i, j int) {
+		ix.Indexes[i], ix.Indexes[j] = ix.Indexes[j], ix.Indexes[i]
+	})
+	ev.UpdateTrialName()
+}
+
+// FixedEnv returns the FixedTable env for given mode,
+// which for Train is wrapped in the CurricEnv.
+func (ss *Sim) FixedEnv(mode etime.Modes) *env.FixedTable {
+	if ce, ok := ss.Envs.ByMode(mode).(*CurricEnv); ok {
+		return &ce.FixedTable
+	}
+	return ss.Envs.ByMode(mode).(*env.FixedTable)
+}
+
 func (ss *Sim) UpdateEnv() {
-	trn := ss.Envs.ByMode(etime.Train).(*env.FixedTable)
+	trn := ss.Envs.ByMode(etime.Train).(*CurricEnv)
 	tst := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
 	switch ss.Patterns {
 	case Easy:
-		trn.Table = table.NewIndexView(ss.Easy)
+		trn.Config(ss.Easy)
 		tst.Table = table.NewIndexView(ss.Easy)
 	case Hard:
-		trn.Table = table.NewIndexView(ss.Hard)
+		trn.Config(ss.Hard)
 		tst.Table = table.NewIndexView(ss.Hard)
 	case Impossible:
-		trn.Table = table.NewIndexView(ss.Impossible)
+		trn.Config(ss.Impossible)
 		tst.Table = table.NewIndexView(ss.Impossible)
+	case Custom:
+		if ss.Custom.Rows == 0 { // nothing imported yet, see ImportCSV
+			break
+		}
+		trn.Config(ss.Custom)
+		tst.Table = table.NewIndexView(ss.Custom)
+	}
+	trn.Schedule = ss.Curriculum
+	// custom patterns can have any number of trials
+	if ss.Loops != nil {
+		ntrls := tst.Table.Len()
+		ss.Loops.Stacks[etime.Train].Loops[etime.Trial].Counter.Max = ntrls
+		ss.Loops.Stacks[etime.Test].Loops[etime.Trial].Counter.Max = ntrls
 	}
 }
 
@@ -425,7 +519,9 @@ func (ss *Sim) UpdateEnv() {
 // for the new run value
 func (ss *Sim) NewRun() {
 	ctx := &ss.Context
-	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
+	run := ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur
+	ss.InitRandSeed(run)
+	ss.orderRand = rand.New(rand.NewSource(ss.RandSeeds[run]))
 	ss.UpdateEnv()
 	ss.Envs.ByMode(etime.Train).Init(0)
 	ss.Envs.ByMode(etime.Test).Init(0)
@@ -438,11 +534,18 @@ func (ss *Sim) NewRun() {
 	ss.Logs.ResetLog(etime.Test, etime.Epoch)
 }
 
-// TestAll runs through the full set of testing items
-func (ss *Sim) TestAll() {
+// TestAll runs through the full set of testing items, and returns
+// the accuracy, as the proportion of testing items that are correct
+// (PctCor in the Test Epoch log, from 0 to 1).
+func (ss *Sim) TestAll() float64 {
 	ss.Envs.ByMode(etime.Test).Init(0)
 	ss.Loops.ResetAndRun(etime.Test)
 	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
+	dt := ss.Logs.Table(etime.Test, etime.Epoch)
+	if dt.Rows == 0 {
+		return 0
+	}
+	return dt.Float("PctCor", dt.Rows-1)
 }
 
 ///////////////////////////////////////////////////////////////////////
@@ -453,6 +556,7 @@ func (ss *Sim) TestAll() {
 func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("SSE", 0.0)
 	ss.Stats.SetString("TrialName", "")
+	ss.Stats.SetString("CurricStage", "")
 	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
 
@@ -505,6 +609,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
 	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
 	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
+	ss.Logs.AddStatStringItem(etime.Train, etime.Epoch, "CurricStage")
 
 	ss.Logs.AddStatAggItem("SSE", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("AvgSSE", etime.Run, etime.Epoch, etime.Trial)
@@ -527,6 +632,9 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.NoPlot(etime.Test, etime.Trial)
 	ss.Logs.NoPlot(etime.Test, etime.Run)
 	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+
+	noise.ConfigSweep(ss.Logs.MiscTable("NoiseSweep"))
+	ss.ConfigCompareLrates(ss.Logs.MiscTable("CompareLrates"))
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -581,9 +689,62 @@ func (ss *Sim) ConfigGUI() {
 
 	ss.GUI.AddTableView(&ss.Logs, etime.Test, etime.Trial)
 
+	nsnm := "NoiseSweep"
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey(nsnm), nsnm+" Plot")
+	plt.Options.Title = "Test Accuracy by Input Noise Level"
+	plt.Options.XAxis = "Level"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable(nsnm))
+	plt.SetColumnOptions("PctCor", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+
+	ss.ConfigCompareLratesPlot()
+
 	ss.GUI.FinalizeGUI(false)
 }
 
+// CopyPlotTable copies the table backing the currently selected plot tab
+// to the clipboard as tab-separated values, for pasting into a spreadsheet.
+func (ss *Sim) CopyPlotTable() {
+	tab, _ := ss.GUI.Tabs.CurrentTab()
+	var plt *plotcore.PlotEditor
+	if tab != nil {
+		tab.AsTree().WalkDown(func(n tree.Node) bool {
+			if pe, ok := n.(*plotcore.PlotEditor); ok {
+				plt = pe
+				return tree.Break
+			}
+			return tree.Continue
+		})
+	}
+	dt := ss.PlotTable(plt)
+	if dt == nil {
+		core.MessageSnackbar(ss.GUI.Body, "Select a plot tab to copy its data")
+		return
+	}
+	var b bytes.Buffer
+	errors.Log(dt.WriteCSV(&b, table.Tab, table.Headers))
+	ss.GUI.Body.Clipboard().Write(mimedata.NewText(b.String()))
+}
+
+// PlotTable returns the table plotted by the given plot, which is
+// either a log table or a misc table, stored under the same key
+// as the plot, or nil if not found.
+func (ss *Sim) PlotTable(plt *plotcore.PlotEditor) *table.Table {
+	if plt == nil {
+		return nil
+	}
+	for key, pe := range ss.GUI.Plots {
+		if pe != plt {
+			continue
+		}
+		if lt, ok := ss.Logs.Tables[key]; ok {
+			return lt.Table
+		}
+		return ss.Logs.MiscTables[string(key)]
+	}
+	return nil
+}
+
 func (ss *Sim) MakeToolbar(p *tree.Plan) {
 	ss.GUI.AddLooperCtrl(p, ss.Loops)
 
@@ -597,6 +758,46 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.GUI.UpdatePlot(etime.Train, etime.Run)
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Import CSV",
+		Icon:    icons.Open,
+		Tooltip: "Import Custom patterns from a CSV file, mapping columns to layers, e.g.: Input: a, b, c, d; Output: x, y; Name: label",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ImportCSV)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Copy Plot Data",
+		Icon:    icons.ContentCopy,
+		Tooltip: "Copy the data of the selected plot tab to the clipboard as tab-separated values, for pasting into a spreadsheet",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.CopyPlotTable()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Run and Average",
+		Icon:    icons.PlayArrow,
+		Tooltip: "Train the given number of runs with different random seeds, and report the mean and 95% confidence interval of the Config.AvgStat final run stat",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.RunAndAverage)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Noise Sweep",
+		Icon:    icons.PlayArrow,
+		Tooltip: "Test the trained network with increasing levels of noise added to the input patterns, from 0 to the given max level in the given number of steps, and plot the accuracy vs. noise level in the NoiseSweep Plot -- set TestNoise.Flip for salt-and-pepper noise, where the level is the flip probability",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.NoiseSweep)
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Compare Lrates",
+		Icon:    icons.PlayArrow,
+		Tooltip: "Train one run with a high learning rate (e.g., 1, nearly one-shot) and one with a low rate (e.g., 0.01, gradual), from the same initial weights, and overlay their learning curves in the CompareLrates Plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.CompareLearningRates)
+		},
+	})
 	////////////////////////////////////////////////
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "New Seed",
@@ -0,0 +1,81 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/compcogneuro/sims/v2/simtest"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// newTestSim returns a configured Sim, without the GUI.
+func newTestSim(t testing.TB) *Sim {
+	ss := &Sim{}
+	simtest.Config(t, ss)
+	return ss
+}
+
+// TestInitClearsLogs checks that Init after training clears the Train
+// logs and the accumulated Stats, so the plots start out empty.
+func TestInitClearsLogs(t *testing.T) {
+	ss := newTestSim(t)
+	simtest.InitClearsLogs(t, ss.Init, ss.Loops, &ss.Logs, &ss.Stats)
+}
+
+// trainOrder returns the training trial orders for the given number
+// of epochs of a new run, from PermuteTrain.
+func trainOrder(ss *Sim, epochs int) [][]int {
+	ss.NewRun()
+	ix := ss.FixedEnv(etime.Train).Table
+	var order [][]int
+	for range epochs {
+		ss.PermuteTrain()
+		order = append(order, slices.Clone(ix.Indexes))
+	}
+	return order
+}
+
+// TestPermuteTrain checks that the same seed gives the same permuted
+// training order, and that different seeds give different orders.
+func TestPermuteTrain(t *testing.T) {
+	ss := newTestSim(t)
+	order := trainOrder(ss, 5)
+	if order2 := trainOrder(ss, 5); !reflect.DeepEqual(order, order2) {
+		t.Errorf("same seed: training order %v, then %v", order, order2)
+	}
+	ss.RandSeeds.NewSeeds()
+	if order3 := trainOrder(ss, 5); reflect.DeepEqual(order, order3) {
+		t.Errorf("new seed: training order is the same: %v", order)
+	}
+}
+
+// BenchmarkInputs measures the copy of the Input pattern into the
+// reused input tensor, as done in ApplyInputs.
+func BenchmarkInputs(b *testing.B) {
+	ss := newTestSim(b)
+	ev := ss.FixedEnv(etime.Train)
+	ev.Step()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		ss.inputs.State(ev, "Input")
+	}
+}
+
+// BenchmarkEnvState measures env.State for the Input pattern, which
+// ApplyInputs used before the reused input tensors, for comparison.
+func BenchmarkEnvState(b *testing.B) {
+	ss := newTestSim(b)
+	ev := ss.FixedEnv(etime.Train)
+	ev.Step()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		ev.State("Input")
+	}
+}
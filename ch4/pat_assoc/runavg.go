@@ -0,0 +1,104 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// RunAndAverage trains the given number of runs, each with its own random
+// seed, and reports the mean and 95% confidence interval of the
+// Config.AvgStat final run stat over the runs.  In the GUI, the runs
+// happen in the background and the summary is shown in a dialog when
+// done; without the GUI it runs directly and prints the summary.
+func (ss *Sim) RunAndAverage(n int) { //types:add
+	if ss.GUI.Body == nil {
+		fmt.Println(ss.RunAverage(n))
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		sum := ss.RunAverage(n)
+		ss.GUI.Stopped()
+		ss.GUI.Body.AsyncLock()
+		core.MessageDialog(ss.GUI.Body, sum, "Run and Average")
+		ss.GUI.Body.AsyncUnlock()
+	}()
+}
+
+// RunAverage trains the given number of runs from the start, and returns
+// a summary of the mean and 95% confidence interval of the Config.AvgStat
+// column in the Train Run log, which is also set in the RunAvg stats.
+// Negative values, e.g., a FirstZero of -1 for a run that never reached
+// zero errors, are excluded and reported separately.
+func (ss *Sim) RunAverage(n int) string {
+	n = max(n, 1)
+	ss.Init() // resets the Train Run log and seeds
+	ss.GUI.StopNow = false
+	ss.Loops.Loop(etime.Train, etime.Run).Counter.Max = n
+	ss.Loops.Run(etime.Train)
+	ss.Loops.Loop(etime.Train, etime.Run).Counter.Max = ss.Config.NRuns
+
+	stnm := ss.Config.AvgStat
+	dt := ss.Logs.Table(etime.Train, etime.Run)
+	var vals []float64
+	for ri := range dt.Rows {
+		v := dt.Float(stnm, ri)
+		if v >= 0 && !math.IsNaN(v) {
+			vals = append(vals, v)
+		}
+	}
+	mean, ci := MeanCI95(vals)
+	ss.Stats.SetFloat("RunAvg", mean)
+	ss.Stats.SetFloat("RunAvgCI", ci)
+	sum := fmt.Sprintf("%s over %d runs: %.4g ± %.4g (95%% CI)", stnm, len(vals), mean, ci)
+	if nx := dt.Rows - len(vals); nx > 0 {
+		sum += fmt.Sprintf(", excluding %d runs without a value", nx)
+	}
+	return sum
+}
+
+// MeanCI95 returns the mean of the given values and the half-width of its
+// 95% confidence interval, based on the t distribution.  The interval is
+// NaN for fewer than 2 values.
+func MeanCI95(vals []float64) (mean, ci float64) {
+	n := len(vals)
+	if n == 0 {
+		return math.NaN(), math.NaN()
+	}
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(n)
+	if n < 2 {
+		return mean, math.NaN()
+	}
+	sumsq := 0.0
+	for _, v := range vals {
+		sumsq += (v - mean) * (v - mean)
+	}
+	sem := math.Sqrt(sumsq/float64(n-1)) / math.Sqrt(float64(n))
+	return mean, tCrit95(n-1) * sem
+}
+
+// tCrit95 returns the two-tailed 95% critical value of the
+// t distribution for the given degrees of freedom.
+func tCrit95(df int) float64 {
+	tbl := []float64{12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+		2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+		2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042}
+	if df >= 1 && df <= len(tbl) {
+		return tbl[df-1]
+	}
+	return 1.96
+}
@@ -6,10 +6,14 @@ import (
 	"cogentcore.org/core/types"
 )
 
+var _ = types.AddType(&types.Type{Name: "main.CurricStage", IDName: "curric-stage", Doc: "CurricStage is one stage of a CurricEnv curriculum.", Fields: []types.Field{{Name: "Name", Doc: "name of the stage, e.g., Easy, reported in the CurricStage stat"}, {Name: "Epochs", Doc: "number of epochs to present this stage"}, {Name: "Patterns", Doc: "names of the patterns (trial names) presented in this stage;\nall of the patterns are presented if empty"}}})
+
+var _ = types.AddType(&types.Type{Name: "main.CurricEnv", IDName: "curric-env", Doc: "CurricEnv is a curriculum environment, which wraps a FixedTable to present\nits patterns in staged subsets, e.g., easy patterns first and then harder\nones, according to the Schedule.  The active subset is advanced at the\nstart of each epoch, based on the Epoch count, which is incremented\neach time through all of the patterns.  With an empty Schedule,\nall of the patterns are presented, as in the FixedTable.", Embeds: []types.Field{{Name: "FixedTable"}}, Fields: []types.Field{{Name: "Schedule", Doc: "curriculum schedule of stages, in order.  The last stage remains\nactive after all of the epochs of the earlier stages."}, {Name: "Stage", Doc: "index of the active stage in the Schedule, -1 if none"}, {Name: "Epoch", Doc: "number of epochs (times through the patterns) since Init"}, {Name: "Source", Doc: "all of the patterns, from which the subsets are selected"}}})
+
 var _ = types.AddType(&types.Type{Name: "main.PatsType", IDName: "pats-type", Doc: "PatsType is the type of training patterns"})
 
 var _ = types.AddType(&types.Type{Name: "main.LearnType", IDName: "learn-type", Doc: "LearnType is the type of learning to use"})
 
-var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}}})
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}, {Name: "PermuteTrain", Doc: "present the training trials in a new permuted order on each epoch,\nderived from the random seed for the run, so that a given seed always\nreproduces the exact same sequence of trials.  If off, training trials\nare presented sequentially, as in testing."}, {Name: "AvgStat", Doc: "name of the final run stat in the Train Run log that is averaged\nover runs by RunAndAverage, e.g., FirstZero, LastZero, PctCor"}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Learn", Doc: "select which type of learning to use"}, {Name: "Patterns", Doc: "select which type of patterns to use"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Easy", Doc: "easy training patterns"}, {Name: "Hard", Doc: "hard training patterns"}, {Name: "Impossible", Doc: "impossible training patterns"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "ImportCSV", Doc: "ImportCSV reads patterns from a generic CSV file with a header row,\nusing the mapping spec to assign CSV columns to network layers,\nand selects the resulting Custom patterns for training and testing.\nThe mapping has the form: \"Input: a, b, c, d; Output: x, y; Name: label\"\nwhere each layer lists the CSV columns that fill its units in order,\nand the optional Name entry gives the column used for the trial name.\nThe number of columns mapped to a layer must match its number of units.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename", "mapping"}, Returns: []string{"error"}}, {Name: "CompareLearningRates", Doc: "CompareLearningRates trains one run with a high learning rate (e.g., 1,\nfor nearly one-shot learning), and another with a low learning rate\n(e.g., 0.01, for gradual learning), from the same initial weights, and\noverlays their learning curves (SSE per epoch) in the CompareLrates plot.\nThe SSE per epoch of each run is kept in the LrateHigh and LrateLow\nStats tensors (Stats has no tables), and the CompareLrates log table\nthat is plotted has both.  The learning rates of the network are\nrestored afterwards.  The summary reports the final training SSE, and the SSE on a\nfinal test, which differs from training when TestNoise is set, as a\ntest of generalization: a larger increase in SSE from training to test\nfor the high rate would indicate overfitting to the exact patterns.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"high", "low"}}, {Name: "NoiseSweep", Doc: "NoiseSweep tests the current network with the TestNoise level set to\neach of steps+1 levels from 0 to maxLevel, recording the test accuracy\nfor each level in the NoiseSweep table and plot.  Train the network\nfirst, so the sweep shows how robust its learning is to noise.\nIn the GUI, the tests happen in the background; without the GUI the\nresults are printed.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"maxLevel", "steps"}}, {Name: "RunAndAverage", Doc: "RunAndAverage trains the given number of runs, each with its own random\nseed, and reports the mean and 95% confidence interval of the\nConfig.AvgStat final run stat over the runs.  In the GUI, the runs\nhappen in the background and the summary is shown in a dialog when\ndone; without the GUI it runs directly and prints the summary.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"n"}}}, Fields: []types.Field{{Name: "Learn", Doc: "select which type of learning to use"}, {Name: "Patterns", Doc: "select which type of patterns to use"}, {Name: "Curriculum", Doc: "curriculum schedule for training, presenting subsets of the patterns\nin stages, e.g., easy ones first.  Empty = all patterns on every epoch."}, {Name: "TestNoise", Doc: "noise added to the input patterns during testing, to test the\nrobustness of the learned associations; see NoiseSweep."}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Easy", Doc: "easy training patterns"}, {Name: "Hard", Doc: "hard training patterns"}, {Name: "Impossible", Doc: "impossible training patterns"}, {Name: "Custom", Doc: "custom training patterns, imported from a CSV file"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
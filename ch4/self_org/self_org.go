@@ -18,6 +18,7 @@ import (
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/envstate"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -161,6 +162,9 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// reused input tensors for ApplyInputs
+	inputs envstate.Buffers
 }
 
 // New creates new blank elements and initializes defaults
@@ -279,6 +283,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -368,7 +373,7 @@ func (ss *Sim) ApplyInputs() {
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
 	for _, lnm := range lays {
 		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
+		pats := ss.inputs.State(ev, ly.Name)
 		if pats != nil {
 			ly.ApplyExt(pats)
 		}
@@ -496,6 +501,48 @@ func (ss *Sim) HiddenFromInput() {
 	gv.Update()
 }
 
+// WinnerMap computes the average of the test input lines for which each
+// Hidden unit is a winner (activity > .5), into the WinnerMap grid, which is
+// arranged like the Hidden layer, with the same layout as the Weights grid.
+// This is a self-organizing map (SOM) view of the lines: it shows which
+// units win for each line, and whether nearby units win for similar lines.
+func (ss *Sim) WinnerMap() {
+	if ss.GUI.Grids == nil {
+		return
+	}
+	dt := ss.Logs.Table(etime.Test, etime.Trial)
+	wm := ss.Stats.F32Tensor("WinnerMap")
+	wm.SetZeros()
+	isz := ss.Net.LayerByName("Input").Shape.Len()
+	nwin := make([]int, ss.Net.LayerByName("Hidden").Shape.Len())
+	for row := range dt.Rows {
+		hact := dt.Tensor("Hidden_Act", row)
+		inp := dt.Tensor("Input_Act", row)
+		for ui := range nwin {
+			if hact.Float1D(ui) <= .5 {
+				continue
+			}
+			nwin[ui]++
+			for i := range isz {
+				wm.Values[ui*isz+i] += float32(inp.Float1D(i))
+			}
+		}
+	}
+	for ui, n := range nwin {
+		if n <= 1 {
+			continue
+		}
+		vls := wm.Values[ui*isz : (ui+1)*isz]
+		for i := range vls {
+			vls[i] /= float32(n)
+		}
+	}
+	gv := ss.GUI.Grid("WinnerMap")
+	gv.AsyncLock()
+	gv.Update()
+	gv.AsyncUnlock()
+}
+
 //////////////////////////////////////////////////////////////////////////////
 // 		Logging
 
@@ -550,6 +597,7 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 		ss.GUI.UpdateTableView(etime.Test, etime.Trial)
 		if time == etime.Epoch {
 			ss.HiddenFromInput()
+			ss.WinnerMap()
 		}
 	}
 }
@@ -581,6 +629,11 @@ func (ss *Sim) ConfigGUI() {
 	wg.SetShape([]int{4, 5, 5, 5})
 	wgv.SetTensor(wg)
 
+	mgv := ss.GUI.AddGridTab("WinnerMap")
+	wm := ss.Stats.F32Tensor("WinnerMap")
+	wm.SetShape([]int{4, 5, 5, 5})
+	mgv.SetTensor(wm)
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -0,0 +1,25 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/compcogneuro/sims/v2/simtest"
+)
+
+// newTestSim returns a configured Sim, without the GUI.
+func newTestSim(t *testing.T) *Sim {
+	ss := &Sim{}
+	simtest.Config(t, ss)
+	return ss
+}
+
+// TestInitClearsLogs checks that Init after training clears the Train
+// logs and the accumulated Stats, so the plots start out empty.
+func TestInitClearsLogs(t *testing.T) {
+	ss := newTestSim(t)
+	simtest.InitClearsLogs(t, ss.Init, ss.Loops, &ss.Logs, &ss.Stats)
+}
@@ -206,6 +206,9 @@ type Sim struct {
 	// number of cycles to present a target; 220 by default, 50 to 300 for KNa adapt testing
 	TargetCycles int `default:"220"`
 
+	// number of random search displays at each set size for the Search Test
+	SearchTrials int `default:"5" min:"1"`
+
 	// click to see these testing input patterns
 	MultiObjs *table.Table `new-window:"+" display:"no-inline"`
 
@@ -250,6 +253,10 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// true while running the Conjunction search task, when the RT requires
+	// spatial selection of the target location
+	searchConj bool
 }
 
 // New creates new blank elements and initializes defaults
@@ -274,6 +281,7 @@ func (ss *Sim) Defaults() {
 	ss.KNaAdapt = false
 	ss.CueCycles = 100
 	ss.TargetCycles = 220
+	ss.SearchTrials = 5
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -555,7 +563,7 @@ func (ss *Sim) CycleThresholdStop() {
 	cyc := ss.Loops.Stacks[etime.Test].Loops[etime.Cycle]
 	out := ss.Net.LayerByName("Output")
 	act := out.Neurons[1].Act
-	if act > 0.5 {
+	if act > 0.5 && (!ss.searchConj || ss.SearchTargetSelected()) {
 		ss.Stats.SetFloat("RT", float64(cyc.Counter.Cur))
 		cyc.SkipToMax()
 	}
@@ -716,6 +724,8 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.SetMeta(etime.Test, etime.Trial, "RT:FixMax", "true")
 	ss.Logs.SetMeta(etime.Test, etime.Trial, "RT:Min", "0")
 	ss.Logs.SetMeta(etime.Test, etime.Trial, "RT:Max", "250")
+
+	ss.ConfigSearchRT(ss.Logs.MiscTable("SearchRT"))
 }
 
 func (ss *Sim) TrialStats() {
@@ -808,6 +818,13 @@ func (ss *Sim) ConfigGUI() {
 	plt.Options.XAxis = "Trial"
 	plt.SetTable(dt)
 
+	srnm := "SearchRT"
+	plt = ss.GUI.NewPlotTab(etime.ScopeKey(srnm), srnm+" Plot")
+	plt.Options.Title = "Search RT vs Set Size"
+	plt.Options.XAxis = "SetSize"
+	plt.SetTable(ss.Logs.MiscTable(srnm))
+	plt.SetColumnOptions("RT", plotcore.On, plotcore.FixMin, 0, plotcore.FloatMax, 0)
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -825,6 +842,15 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 		},
 	})
 
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Search Test",
+		Icon:    icons.Search,
+		Tooltip: "Runs the Feature and Conjunction visual search tasks at each set size, and plots RT vs. set size for each in the SearchRT plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			ss.SearchTest()
+		},
+	})
+
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset Log",
 		Icon:    icons.Reset,
 		Tooltip: "Reset the accumulated trial log",
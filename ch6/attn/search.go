@@ -0,0 +1,182 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// NSearchLocs is the number of locations in the Input layer, which is
+// the largest set size for the visual search tasks.
+const NSearchLocs = 7
+
+// SearchTasks are the names of the visual search tasks, which are
+// used as the Group names of the search displays.
+var SearchTasks = []string{"Feature", "Conjunction"}
+
+// SearchDisplays generates the search displays for given task,
+// with SearchTrials displays at each set size from 1 to NSearchLocs,
+// each with the items at random locations.  In the Feature task,
+// the target is feature 1 (the Output target object) and the distractors
+// are all feature 0, so the target differs from the distractors in a
+// single feature.  In the Conjunction task, the target has both features
+// at the same location, and the distractors alternate between
+// feature 0 alone and feature 1 alone, so the target is only defined by
+// the conjunction of features that each of the distractors also have.
+// The target is always the first item in each display.
+func (ss *Sim) SearchDisplays(task string) *table.Table {
+	conj := task == "Conjunction"
+	dt := table.NewTable()
+	dt.SetMetaData("name", task+"Search")
+	dt.SetMetaData("desc", task+" search displays")
+	dt.AddStringColumn("Group")
+	dt.AddStringColumn("Name")
+	inp := dt.AddFloat32TensorColumn("Input", []int{2, NSearchLocs}, "Y", "X")
+	out := dt.AddFloat32TensorColumn("Output", []int{2, 1}, "Y", "X")
+	ntrl := max(ss.SearchTrials, 1)
+	dt.SetNumRows(NSearchLocs * ntrl)
+	row := 0
+	for ssz := 1; ssz <= NSearchLocs; ssz++ {
+		for ti := range ntrl {
+			dt.SetString("Group", row, task)
+			dt.SetString("Name", row, fmt.Sprintf("%s_%d_%d", task, ssz, ti))
+			locs := ss.Net.Rand.Perm(NSearchLocs)
+			for ii, loc := range locs[:ssz] {
+				switch {
+				case ii == 0:
+					inp.SetFloatRowCell(row, NSearchLocs+loc, 1)
+					if conj {
+						inp.SetFloatRowCell(row, loc, 1)
+					}
+				case conj && ii%2 == 0:
+					inp.SetFloatRowCell(row, NSearchLocs+loc, 1)
+				default:
+					inp.SetFloatRowCell(row, loc, 1)
+				}
+			}
+			out.SetFloatRowCell(row, 1, 1)
+			row++
+		}
+	}
+	return dt
+}
+
+// SearchTest runs the Feature and Conjunction visual search tasks
+// (see SearchDisplays) at each set size, and plots the mean reaction
+// time (RT, settling cycles) as a function of set size for each task in
+// the SearchRT plot.  In the Feature task, RT is the usual time for the
+// Output target unit to exceed .5.  In the Conjunction task, detecting the
+// target object is not enough, because the distractors contain the same
+// features, so the trial also requires that spatial attention has selected
+// the target location, i.e., that the Spat1 pool centered on the target
+// is the most active.  Classic results show a flat RT function for feature
+// search and an increasing one for conjunction search.
+func (ss *Sim) SearchTest() { //types:add
+	if ss.GUI.Body == nil {
+		ss.searchTest()
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.searchTest()
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) searchTest() {
+	ss.GUI.StopNow = false
+	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	tstTrial := ss.Loops.Stacks[etime.Test].Loops[etime.Trial]
+	dt := ss.Logs.MiscTable("SearchRT")
+	dt.SetNumRows(0)
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	ntrl := max(ss.SearchTrials, 1)
+	for _, task := range SearchTasks {
+		if ss.GUI.StopNow {
+			break
+		}
+		ss.searchConj = task == "Conjunction"
+		disp := ss.SearchDisplays(task)
+		ev.Table = table.NewIndexView(disp)
+		ev.Init(0)
+		tstTrial.Counter.Max = disp.Rows
+		st := trl.Rows
+		ss.Loops.ResetCountersByMode(etime.Test)
+		ss.Loops.Step(etime.Test, 1, etime.Epoch)
+		for ssz := 1; ssz <= NSearchLocs; ssz++ {
+			srow := st + (ssz-1)*ntrl
+			if srow+ntrl > trl.Rows {
+				break
+			}
+			rt := 0.0
+			for ri := srow; ri < srow+ntrl; ri++ {
+				rt += trl.Float("RT", ri)
+			}
+			rt /= float64(ntrl)
+			row := dt.Rows
+			dt.SetNumRows(row + 1)
+			dt.SetString("Task", row, task)
+			dt.SetFloat("SetSize", row, float64(ssz))
+			dt.SetFloat("RT", row, rt)
+			if ss.GUI.Body == nil {
+				fmt.Printf("%s search\tSetSize: %d\tRT: %.4g\n", task, ssz, rt)
+			}
+		}
+		if plt := ss.GUI.PlotByName("SearchRT"); plt != nil {
+			plt.GoUpdatePlot()
+		}
+	}
+	ss.searchConj = false
+	ss.UpdateEnv()
+}
+
+// SearchTargetSelected returns true if spatial attention has selected
+// the location of the target in the current Conjunction search display,
+// which is the location where both features are present in the Input:
+// the Spat1 pool centered on that location must be the most active pool.
+func (ss *Sim) SearchTargetSelected() bool {
+	inp := ss.Net.LayerByName("Input")
+	tloc := -1
+	for loc := range NSearchLocs {
+		// 2D Input pattern is [feature, location] = unit y, pool x
+		if inp.Neurons[loc*2].Ext > 0 && inp.Neurons[loc*2+1].Ext > 0 {
+			tloc = loc
+			break
+		}
+	}
+	if tloc < 0 {
+		return false
+	}
+	sp1 := ss.Net.LayerByName("Spat1")
+	npl := len(sp1.Pools) - 1
+	tpl := min(max(tloc-1, 0), npl-1) // Spat1 pool x receives from locations x..x+2
+	tact := sp1.Pools[1+tpl].Inhib.Act.Avg
+	for pi := range npl {
+		if pi != tpl && sp1.Pools[1+pi].Inhib.Act.Avg >= tact {
+			return false
+		}
+	}
+	return true
+}
+
+// ConfigSearchRT configures the table of results from SearchTest,
+// with the mean RT at each set size for each task.
+func (ss *Sim) ConfigSearchRT(dt *table.Table) {
+	dt.SetMetaData("name", "SearchRT")
+	dt.SetMetaData("desc", "visual search reaction time as a function of set size")
+	dt.SetMetaData("read-only", "true")
+	dt.SetMetaData("LegendCol", "Task")
+	dt.AddStringColumn("Task")
+	dt.AddFloat64Column("SetSize")
+	dt.AddFloat64Column("RT")
+}
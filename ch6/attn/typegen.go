@@ -12,4 +12,4 @@ var _ = types.AddType(&types.Type{Name: "main.LesionType", IDName: "lesion-type"
 
 var _ = types.AddType(&types.Type{Name: "main.LesionSize", IDName: "lesion-size", Doc: "LesionSize is the size of lesion"})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "Lesion", Doc: "Lesion lesions given set of layers (or unlesions for NoLesion) and\nlocations and number of units (Half = partial = 1/2 units, Full = both units)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"lay", "locations", "units"}}}, Fields: []types.Field{{Name: "Test", Doc: "select which type of test (input patterns) to use"}, {Name: "SpatToObj", Doc: "spatial to object projection WtScale.Rel strength -- reduce to 1.5, 1 to test"}, {Name: "V1ToSpat1", Doc: "V1 to Spat1 projection WtScale.Rel strength -- reduce to .55, .5 to test"}, {Name: "KNaAdapt", Doc: "sodium (Na) gated potassium (K) channels that cause neurons to fatigue over time"}, {Name: "CueCycles", Doc: "number of cycles to present the cue; 100 by default, 50 to 300 for KNa adapt testing"}, {Name: "TargetCycles", Doc: "number of cycles to present a target; 220 by default, 50 to 300 for KNa adapt testing"}, {Name: "MultiObjs", Doc: "click to see these testing input patterns"}, {Name: "StdPosner", Doc: "click to see these testing input patterns"}, {Name: "ClosePosner", Doc: "click to see these testing input patterns"}, {Name: "ReversePosner", Doc: "click to see these testing input patterns"}, {Name: "ObjAttn", Doc: "click to see these testing input patterns"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "Lesion", Doc: "Lesion lesions given set of layers (or unlesions for NoLesion) and\nlocations and number of units (Half = partial = 1/2 units, Full = both units)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"lay", "locations", "units"}}, {Name: "SearchTest", Doc: "SearchTest runs the Feature and Conjunction visual search tasks\n(see SearchDisplays) at each set size, and plots the mean reaction\ntime (RT, settling cycles) as a function of set size for each task in\nthe SearchRT plot.  In the Feature task, RT is the usual time for the\nOutput target unit to exceed .5.  In the Conjunction task, detecting the\ntarget object is not enough, because the distractors contain the same\nfeatures, so the trial also requires that spatial attention has selected\nthe target location, i.e., that the Spat1 pool centered on the target\nis the most active.  Classic results show a flat RT function for feature\nsearch and an increasing one for conjunction search.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Fields: []types.Field{{Name: "Test", Doc: "select which type of test (input patterns) to use"}, {Name: "SpatToObj", Doc: "spatial to object projection WtScale.Rel strength -- reduce to 1.5, 1 to test"}, {Name: "V1ToSpat1", Doc: "V1 to Spat1 projection WtScale.Rel strength -- reduce to .55, .5 to test"}, {Name: "KNaAdapt", Doc: "sodium (Na) gated potassium (K) channels that cause neurons to fatigue over time"}, {Name: "CueCycles", Doc: "number of cycles to present the cue; 100 by default, 50 to 300 for KNa adapt testing"}, {Name: "TargetCycles", Doc: "number of cycles to present a target; 220 by default, 50 to 300 for KNa adapt testing"}, {Name: "SearchTrials", Doc: "number of random search displays at each set size for the Search Test"}, {Name: "MultiObjs", Doc: "click to see these testing input patterns"}, {Name: "StdPosner", Doc: "click to see these testing input patterns"}, {Name: "ClosePosner", Doc: "click to see these testing input patterns"}, {Name: "ReversePosner", Doc: "click to see these testing input patterns"}, {Name: "ObjAttn", Doc: "click to see these testing input patterns"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
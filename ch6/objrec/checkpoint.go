@@ -0,0 +1,314 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/lab/base/mpi"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+	"github.com/emer/leabra/v2/leabra"
+	"github.com/emer/vision/v2/fffb"
+)
+
+// Checkpoint records the state of a training run at the end of an epoch,
+// saved every Log.Checkpoint epochs, from which the run can be resumed
+// with the Run.Resume option, e.g., after a long run has been interrupted.
+type Checkpoint struct {
+
+	// run number
+	Run int
+
+	// last epoch that was completed in the run
+	Epoch int
+
+	// file with the weights at the end of Epoch
+	Weights string
+
+	// file with the rest of the state of the network and envs at the end
+	// of Epoch, including the running averages of the neurons, saved by SaveState
+	State string
+
+	// error stats at the end of Epoch, from the elog ErrStat items,
+	// which depend on the previous epochs
+	NZero, FirstZero, LastZero int
+
+	// files with the Train Epoch log of the run through Epoch,
+	// and the Train Run log of the previous runs
+	EpochLog, RunLog string
+}
+
+// CheckpointFile returns the file name for the checkpoint,
+// in the Log.OutputDir directory.
+func (ss *Sim) CheckpointFile() string {
+	return ss.checkpointFile(".json")
+}
+
+// checkpointFile returns the file name for a checkpoint file
+// with the given suffix, in the Log.OutputDir directory.
+func (ss *Sim) checkpointFile(suffix string) string {
+	return ss.OutputFile(ss.Net.Name + "_" + ss.Stats.String("RunName") + "_ckpt" + suffix)
+}
+
+// SaveCheckpoint saves the weights and a Checkpoint for the current
+// training epoch, if it is at the Log.Checkpoint interval.
+// The weights and logs are saved first, so an interrupted save leaves
+// the previous Checkpoint valid.
+func (ss *Sim) SaveCheckpoint() {
+	if ss.Config.Log.Checkpoint <= 0 {
+		return
+	}
+	epc := ss.Loops.Loop(etime.Train, etime.Epoch).Counter.Cur
+	if (epc+1)%ss.Config.Log.Checkpoint != 0 {
+		return
+	}
+	ck := &Checkpoint{Run: ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur, Epoch: epc}
+	ck.NZero = ss.Stats.Int("NZero")
+	ck.FirstZero = ss.Stats.Int("FirstZero")
+	ck.LastZero = ss.Stats.Int("LastZero")
+	ck.Weights = ss.checkpointFile(".wts.gz")
+	if errors.Log(ss.Net.SaveWeightsJSON(core.Filename(ck.Weights))) != nil {
+		return
+	}
+	ck.State = ss.checkpointFile("_state.gob.gz")
+	if errors.Log(ss.SaveState(ck.State)) != nil {
+		return
+	}
+	ck.EpochLog = ss.checkpointFile("_epc.tsv")
+	if errors.Log(saveCheckpointLog(ss.Logs.Table(etime.Train, etime.Epoch), ck.EpochLog)) != nil {
+		return
+	}
+	ck.RunLog = ss.checkpointFile("_run.tsv")
+	if errors.Log(saveCheckpointLog(ss.Logs.Table(etime.Train, etime.Run), ck.RunLog)) != nil {
+		return
+	}
+	b, err := json.MarshalIndent(ck, "", "\t")
+	if errors.Log(err) != nil {
+		return
+	}
+	errors.Log(os.WriteFile(ss.CheckpointFile(), b, 0666))
+}
+
+// RemoveCheckpoint removes the checkpoint and its files, if any,
+// which RunNoGUI does when all of the runs are done.
+func (ss *Sim) RemoveCheckpoint() {
+	ck := ss.OpenCheckpoint()
+	if ck == nil {
+		return
+	}
+	for _, fnm := range []string{ck.Weights, ck.State, ck.EpochLog, ck.RunLog} {
+		if fnm != "" {
+			os.Remove(fnm)
+		}
+	}
+	errors.Log(os.Remove(ss.CheckpointFile()))
+}
+
+// OpenCheckpoint returns the saved Checkpoint, or nil if there is none.
+func (ss *Sim) OpenCheckpoint() *Checkpoint {
+	b, err := os.ReadFile(ss.CheckpointFile())
+	if err != nil {
+		return nil
+	}
+	ck := &Checkpoint{}
+	if errors.Log(json.Unmarshal(b, ck)) != nil {
+		return nil
+	}
+	return ck
+}
+
+// ResumeCheckpoint is called at the start of each run, after NewRun
+// and the reset of the Epoch log, to load the weights, set the epoch
+// counter, and restore the error stats and the Train Epoch and Run logs
+// from the Checkpoint that RunNoGUI is resuming from, if any.
+// The logs are saved without headers, and read back into the existing
+// columns.  The log files are not rewritten, so Log.Append should be
+// used to keep the earlier rows in them.
+func (ss *Sim) ResumeCheckpoint() {
+	ck := ss.resume
+	if ck == nil {
+		return
+	}
+	ss.resume = nil
+	if errors.Log(ss.Net.OpenWeightsJSON(core.Filename(ck.Weights))) != nil {
+		return
+	}
+	if ck.State != "" && errors.Log(ss.OpenState(ck.State)) != nil {
+		return
+	}
+	ss.Loops.Loop(etime.Train, etime.Epoch).Counter.Cur = ck.Epoch + 1
+	ss.Stats.SetInt("NZero", ck.NZero)
+	ss.Stats.SetInt("FirstZero", ck.FirstZero)
+	ss.Stats.SetInt("LastZero", ck.LastZero)
+	ss.openCheckpointLog(etime.Train, etime.Epoch, ck.EpochLog)
+	ss.openCheckpointLog(etime.Train, etime.Run, ck.RunLog)
+	mpi.Printf("Resuming run %d at epoch %d from: %s\n", ck.Run, ck.Epoch+1, ss.CheckpointFile())
+}
+
+// CheckpointSeed is called at the start of each training epoch when saving
+// checkpoints, to set the random seeds from the run seed and the epoch, so
+// that a resumed run has the same random sequences as an uninterrupted one.
+func (ss *Sim) CheckpointSeed() {
+	if ss.Config.Log.Checkpoint <= 0 {
+		return
+	}
+	run := ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur
+	epc := ss.Loops.Loop(etime.Train, etime.Epoch).Counter.Cur
+	seed := ss.RandSeeds[run] + int64(epc)
+	rand.Seed(seed)
+	ss.Net.Rand.Seed(seed)
+}
+
+// saveCheckpointLog saves the given log to the given checkpoint file,
+// without headers, and with the full precision of the values, instead of
+// the precision of the log files, so that they are restored exactly.
+func saveCheckpointLog(dt *table.Table, fnm string) error {
+	prec, hasPrec := dt.MetaData["precision"]
+	delete(dt.MetaData, "precision")
+	err := dt.SaveCSV(core.Filename(fnm), table.Tab, table.NoHeaders)
+	if hasPrec {
+		dt.MetaData["precision"] = prec
+	}
+	return err
+}
+
+// openCheckpointLog reads the rows of the given log from the checkpoint file,
+// saved without headers, so they go into the existing log columns.
+func (ss *Sim) openCheckpointLog(mode etime.Modes, time etime.Times, fnm string) {
+	if fnm == "" {
+		return
+	}
+	errors.Log(ss.Logs.Table(mode, time).OpenCSV(core.Filename(fnm), table.Tab))
+	ss.Logs.TableDetails(mode, time).ResetIndexViews()
+}
+
+// simState is the state of the sim saved in a Checkpoint, in addition
+// to the weights: all of the neuron, pool and synapse variables of the
+// network, including the running averages (e.g., AvgL, ActPAvg, and the
+// CosDiff stats) and the weight balance state, and the state of the V1
+// filtering of the envs, which all carry over from one epoch to the next,
+// so that a resumed run is the same as an uninterrupted one.
+type simState struct {
+	WtBalCtr int
+	Layers   []layerState
+
+	// state of each of the LEDEnv envs, by name
+	Envs map[string]visState
+}
+
+// layerState is the state of one layer in a simState.
+type layerState struct {
+	Neurons []leabra.Neuron
+	Pools   []leabra.Pool
+	CosDiff leabra.CosDiffStats
+
+	// state of each of the RecvPaths
+	Paths []pathState
+}
+
+// pathState is the state of one pathway in a layerState.
+type pathState struct {
+	Syns   []leabra.Synapse
+	WbRecv []leabra.WtBalRecvPath
+}
+
+// visState is the state of the V1 filtering of an LEDEnv: the KWTA
+// activations and inhibition, which are the starting point for the next image.
+type visState struct {
+	V1sKwta   []float32
+	V1sInhibs fffb.Inhibs
+}
+
+// SaveState saves the state of the sim to the given file,
+// as a gzipped gob-encoded simState.
+func (ss *Sim) SaveState(fnm string) error {
+	st := &simState{WtBalCtr: ss.Net.WtBalCtr, Envs: map[string]visState{}}
+	for _, ly := range ss.Net.Layers {
+		ls := layerState{Neurons: ly.Neurons, Pools: ly.Pools, CosDiff: ly.CosDiff}
+		for _, pt := range ly.RecvPaths {
+			ls.Paths = append(ls.Paths, pathState{Syns: pt.Syns, WbRecv: pt.WbRecv})
+		}
+		st.Layers = append(st.Layers, ls)
+	}
+	for nm, ev := range ss.Envs {
+		if lev, ok := ev.(*LEDEnv); ok {
+			st.Envs[nm] = visState{V1sKwta: lev.Vis.V1sKwtaTsr.Values, V1sInhibs: lev.Vis.V1sInhibs}
+		}
+	}
+	f, err := os.Create(fnm)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(f)
+	err = gob.NewEncoder(gw).Encode(st)
+	if cerr := gw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// OpenState restores the state of the sim from the given file,
+// saved by SaveState, which must be for the same network.
+func (ss *Sim) OpenState(fnm string) error {
+	f, err := os.Open(fnm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	st := &simState{}
+	if err := gob.NewDecoder(gr).Decode(st); err != nil {
+		return err
+	}
+	if len(st.Layers) != len(ss.Net.Layers) {
+		return fmt.Errorf("OpenState: %s has %d layers, not %d", fnm, len(st.Layers), len(ss.Net.Layers))
+	}
+	for li, ly := range ss.Net.Layers {
+		ls := &st.Layers[li]
+		if len(ls.Neurons) != len(ly.Neurons) || len(ls.Pools) != len(ly.Pools) || len(ls.Paths) != len(ly.RecvPaths) {
+			return fmt.Errorf("OpenState: %s does not match layer %s", fnm, ly.Name)
+		}
+		for pi, pt := range ly.RecvPaths {
+			if ps := &ls.Paths[pi]; len(ps.Syns) != len(pt.Syns) || len(ps.WbRecv) != len(pt.WbRecv) {
+				return fmt.Errorf("OpenState: %s does not match the path to layer %s from %s", fnm, ly.Name, pt.Send.Name)
+			}
+		}
+	}
+	ss.Net.WtBalCtr = st.WtBalCtr
+	for li, ly := range ss.Net.Layers {
+		ls := &st.Layers[li]
+		copy(ly.Neurons, ls.Neurons)
+		copy(ly.Pools, ls.Pools)
+		ly.CosDiff = ls.CosDiff
+		for pi, pt := range ly.RecvPaths {
+			copy(pt.Syns, ls.Paths[pi].Syns)
+			copy(pt.WbRecv, ls.Paths[pi].WbRecv)
+		}
+	}
+	for nm, vs := range st.Envs {
+		lev, ok := ss.Envs[nm].(*LEDEnv)
+		if !ok {
+			continue
+		}
+		lev.Vis.V1sKwtaTsr.SetShape([]int{len(vs.V1sKwta)})
+		copy(lev.Vis.V1sKwtaTsr.Values, vs.V1sKwta)
+		lev.Vis.V1sInhibs = vs.V1sInhibs
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/compcogneuro/sims/v2/simtest"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// newTestSim returns a Sim for running without the GUI, with small runs,
+// checkpoints every epoch, and output files in the given directory.
+// The test flags are kept out of the econfig args.
+func newTestSim(t *testing.T, dir string, nepochs int, resume bool) *Sim {
+	t.Helper()
+	ss := &Sim{}
+	restore := simtest.NoTestArgs()
+	ss.New()
+	restore()
+	ss.Config.GUI = false
+	ss.Config.Run.NEpochs = nepochs
+	ss.Config.Run.NTrials = 10
+	ss.Config.Run.PCAInterval = 0
+	ss.Config.Run.Resume = resume
+	ss.Config.Log.OutputDir = dir
+	ss.Config.Log.Checkpoint = 1
+	ss.Config.Log.Epoch = false
+	ss.Config.Log.Run = false
+	ss.ConfigAll()
+	return ss
+}
+
+// TestCheckpointResume checks that a run that is interrupted and resumed
+// from its checkpoint has the same Train Epoch log and error stats
+// as an uninterrupted run, and that the checkpoint is removed when
+// the run is done.
+func TestCheckpointResume(t *testing.T) {
+	full := newTestSim(t, t.TempDir(), 4, false)
+	full.RunNoGUI()
+	if _, err := os.Stat(full.CheckpointFile()); err == nil {
+		t.Errorf("checkpoint %s not removed after the run", full.CheckpointFile())
+	}
+
+	// interrupt the run after the second epoch, as if it had been killed
+	dir := t.TempDir()
+	part := newTestSim(t, dir, 4, false)
+	part.Loops.Loop(etime.Train, etime.Epoch).OnEnd.Add("Interrupt", func() {
+		if part.Loops.Loop(etime.Train, etime.Epoch).Counter.Cur == 1 {
+			part.Loops.Stop(etime.Epoch)
+		}
+	})
+	part.RunNoGUI()
+	pdt := part.Logs.Table(etime.Train, etime.Epoch).Clone()
+	if pdt.Rows != 2 {
+		t.Fatalf("interrupted run has %d epochs, want 2", pdt.Rows)
+	}
+	if part.OpenCheckpoint() == nil {
+		t.Fatalf("no checkpoint saved by the interrupted run")
+	}
+
+	res := newTestSim(t, dir, 4, true)
+	res.RunNoGUI()
+
+	for _, st := range []string{"NZero", "FirstZero", "LastZero"} {
+		if fv, rv := full.Stats.Int(st), res.Stats.Int(st); fv != rv {
+			t.Errorf("%s: resumed %d, uninterrupted %d", st, rv, fv)
+		}
+	}
+
+	fdt := full.Logs.Table(etime.Train, etime.Epoch)
+	rdt := res.Logs.Table(etime.Train, etime.Epoch)
+	if rdt.Rows != fdt.Rows {
+		t.Fatalf("Epoch log rows: resumed %d, uninterrupted %d", rdt.Rows, fdt.Rows)
+	}
+	for r := range rdt.Rows {
+		if re, fe := rdt.Float("Epoch", r), fdt.Float("Epoch", r); re != fe {
+			t.Errorf("row %d: Epoch: resumed %g, uninterrupted %g", r, re, fe)
+		}
+	}
+	// the rows from the checkpoint are restored exactly
+	compareRows(t, rdt, pdt, 0, pdt.Rows, 0)
+	// and the full state of the network is restored, so the resumed epochs are the same
+	compareRows(t, rdt, fdt, 0, fdt.Rows, 0)
+}
+
+// compareRows reports any difference greater than tol in the error stats
+// of rows st to ed in the given Epoch logs.
+func compareRows(t *testing.T, dt, want *table.Table, st, ed int, tol float64) {
+	t.Helper()
+	for r := st; r < ed; r++ {
+		for _, cl := range []string{"SSE", "PctErr", "TopKErr"} {
+			dv, wv := dt.Float(cl, r), want.Float(cl, r)
+			if math.Abs(dv-wv) > tol {
+				t.Errorf("row %d: %s = %g, want %g", r, cl, dv, wv)
+			}
+		}
+	}
+}
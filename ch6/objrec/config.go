@@ -75,12 +75,63 @@ type RunConfig struct { //types:add
 
 	// how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing
 	TestInterval int `default:"-1"`
+
+	// number of most active outputs that can include the correct category
+	// for a trial to count as correct in the TopKErr stat
+	TopK int `default:"3" min:"1"`
+
+	// in-plane rotation angles in degrees to test in TestRotations;
+	// uses DefaultRotAngles if empty
+	RotAngles []float32
+
+	// number of testing trials per angle in TestRotations
+	RotTrials int `default:"100" min:"1"`
+
+	// if true, RunNoGUI resumes training from the checkpoint saved with the
+	// Log.Checkpoint option, if there is one, instead of starting over.
+	// The Log.Append option should also be used to keep the earlier logs.
+	Resume bool
 }
 
-// LogConfig has config parameters related to logging data
+// LogConfig has config parameters related to logging data.
+// Log files are written as tab-separated text, via elog SetLogFile.
+// There is no HDF5 option, as that requires a cgo HDF5 library that this
+// module does not depend on; ExportStatsJSON writes all of the logs
+// into one structured file for loading into Python and other tools.
 type LogConfig struct { //types:add
 
-	// if true, save final weights after each run
+	// directory for the log, weights and netview data files saved when running
+	// without the GUI, which is created if it does not exist.
+	// The current directory is used if empty.
+	OutputDir string
+
+	// if true, append to existing log files instead of overwriting them,
+	// so the results of repeated runs accumulate in the same files.
+	// Column headers are only written to new files.
+	Append bool
+
+	// if true, existing log files are rotated, by renaming them with the next
+	// numbered suffix (e.g., .1), so that the results in them are kept and
+	// a new file is started.  See RotateMB for only rotating large files.
+	Rotate bool
+
+	// if Rotate is on, only rotate existing log files that are at least
+	// this size in megabytes -- 0 rotates all existing files.
+	// Smaller files are appended to or overwritten, according to Append.
+	RotateMB float64 `min:"0"`
+
+	// save a checkpoint of the weights and training state every this many
+	// training epochs, which Run.Resume uses to resume an interrupted run.
+	// The random seeds are set from the run seed and epoch at the start of
+	// each epoch when this is on, and the full state of the network is saved,
+	// including the running averages of the neurons, so resumed runs are the
+	// same as uninterrupted ones.  The checkpoint is removed when all of the
+	// runs are done.  0 = no checkpoints.
+	Checkpoint int `default:"0" min:"0"`
+
+	// if true, save final weights at the end of each run, in the OutputDir,
+	// in a .wts.gz file named by the network, the run name, and the run and
+	// epoch numbers, so the trained weights from every run are kept
 	SaveWeights bool
 
 	// if true, save train epoch log to file, as .epc.tsv typically
@@ -100,6 +151,15 @@ type LogConfig struct { //types:add
 
 	// if true, save network activation etc data from testing trials, for later viewing in netview
 	NetData bool
+
+	// if true, stream the train epoch and run stats as JSON over a websocket
+	// when running without the GUI, as they are logged, for monitoring long
+	// runs from a browser: open the StreamAddr to see them.
+	Stream bool
+
+	// address for the Stream server.  Only reachable from the same
+	// machine by default -- use e.g., :8765 to allow remote connections.
+	StreamAddr string `default:"localhost:8765"`
 }
 
 // Config is a standard Sim config -- use as a starting point.
@@ -114,6 +174,14 @@ type Config struct { //types:add
 	// log debugging information
 	Debug bool
 
+	// name of the color map for the unit values in the Network view, e.g.,
+	// Viridis for color blindness, or DarkLight (grayscale) for printing
+	ColorMap string `default:"ColdHot"`
+
+	// use the dark theme for the plots (and the rest of the window),
+	// e.g., for presenting in a dark room
+	Dark bool
+
 	// environment configuration options
 	Env EnvConfig `display:"add-fields"`
 
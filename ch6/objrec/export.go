@@ -0,0 +1,183 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor"
+	"github.com/emer/etensor/tensor/table"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// StatsJSON is the structure written by ExportStatsJSON.
+type StatsJSON struct {
+
+	// name of the network
+	Network string
+
+	// RunName stat, identifying the params used
+	RunName string
+
+	// current scalar stat values
+	Floats  map[string]float64
+	Ints    map[string]int
+	Strings map[string]string
+
+	// current tensor stat values
+	Tensors map[string]TensorJSON
+
+	// all of the log tables, in mode, level order
+	Logs []LogJSON
+}
+
+// TensorJSON is a tensor with its shape, for JSON export.
+type TensorJSON struct {
+	Shape  []int
+	Values []any
+}
+
+// LogJSON is one log table, with a slice of row values for each column,
+// for JSON export. Columns with multiple values per row have a slice
+// for each row. NaN values are written as null.
+type LogJSON struct {
+
+	// evaluation mode, e.g., Train, Test
+	Mode string
+
+	// time level, e.g., Epoch, Trial
+	Level string
+
+	// number of rows
+	Rows int
+
+	// names of the columns, in order
+	Columns []string
+
+	// values of each column, by column name
+	Data map[string][]any
+}
+
+// ExportStatsJSON writes the current stats and all of the log tables
+// to the given file in JSON format, for loading into other analysis tools.
+func (ss *Sim) ExportStatsJSON(filename core.Filename) error { //types:add
+	sj := &StatsJSON{Network: ss.Net.Name, RunName: ss.Stats.String("RunName")}
+	sj.Floats = map[string]float64{}
+	for nm, v := range ss.Stats.Floats {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			sj.Floats[nm] = v
+		}
+	}
+	sj.Ints = ss.Stats.Ints
+	sj.Strings = ss.Stats.Strings
+	sj.Tensors = map[string]TensorJSON{}
+	for nm, tsr := range ss.Stats.F32Tensors {
+		sj.Tensors[nm] = TensorJSON{Shape: tsr.Shape().Sizes, Values: jsonValues(tsr, 0, tsr.Len())}
+	}
+
+	var keys []etime.ScopeKey
+	for sk := range ss.Logs.Tables {
+		keys = append(keys, sk)
+	}
+	slices.Sort(keys)
+	for _, sk := range keys {
+		dt := ss.Logs.Tables[sk].Table
+		modes, times := sk.ModesAndTimes()
+		sj.Logs = append(sj.Logs, jsonLog(modes[0], times[0], dt))
+	}
+
+	b, err := json.MarshalIndent(sj, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(string(filename), b, 0666)
+}
+
+// jsonLog returns the JSON form of given log table.
+func jsonLog(mode, level string, dt *table.Table) LogJSON {
+	lj := LogJSON{Mode: mode, Level: level, Rows: dt.Rows, Data: map[string][]any{}}
+	for ci, col := range dt.Columns {
+		nm := dt.ColumnNames[ci]
+		lj.Columns = append(lj.Columns, nm)
+		rows := make([]any, dt.Rows)
+		csz := 1
+		if dt.Rows > 0 {
+			csz = col.Len() / dt.Rows
+		}
+		for ri := range rows {
+			if csz == 1 {
+				rows[ri] = jsonValues(col, ri, ri+1)[0]
+			} else {
+				rows[ri] = jsonValues(col, ri*csz, (ri+1)*csz)
+			}
+		}
+		lj.Data[nm] = rows
+	}
+	return lj
+}
+
+// jsonValues returns the values of given tensor over the
+// given 1D index range, with NaN and Inf values as nil.
+func jsonValues(tsr tensor.Tensor, st, ed int) []any {
+	vals := make([]any, ed-st)
+	for i := st; i < ed; i++ {
+		if tsr.IsString() {
+			vals[i-st] = tsr.String1D(i)
+			continue
+		}
+		v := tsr.Float1D(i)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		vals[i-st] = v
+	}
+	return vals
+}
+
+// ExportTopology writes the network layers and pathways to the given file
+// as a GraphViz DOT graph, which can be rendered with e.g.:
+// dot -Tsvg objrec.dot -o objrec.svg
+// Nodes show the layer shape and type, with input, target and compare
+// layers in distinct colors, and back pathways are drawn dashed.
+func (ss *Sim) ExportTopology(filename core.Filename) error { //types:add
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", ss.Net.Name)
+	b.WriteString("\trankdir=BT;\n\tnode [shape=box, style=\"rounded,filled\", fillcolor=\"#e8e8e8\"];\n")
+	for _, ly := range ss.Net.Layers {
+		dims := make([]string, len(ly.Shape.Sizes))
+		for i, sz := range ly.Shape.Sizes {
+			dims[i] = strconv.Itoa(sz)
+		}
+		color := ""
+		switch ly.Type {
+		case leabra.InputLayer:
+			color = ", fillcolor=\"#b3d9ff\""
+		case leabra.TargetLayer:
+			color = ", fillcolor=\"#ffc2b3\""
+		case leabra.CompareLayer:
+			color = ", fillcolor=\"#fff0b3\""
+		}
+		fmt.Fprintf(&b, "\t%q [label=\"%s\\n%s (%d)\\n%s\"%s];\n", ly.Name, ly.Name, strings.Join(dims, "x"), ly.Shape.Len(), ly.Type, color)
+	}
+	for _, ly := range ss.Net.Layers {
+		for _, pt := range ly.RecvPaths {
+			style := ""
+			if pt.Type == leabra.BackPath {
+				style = " [style=dashed]"
+			}
+			fmt.Fprintf(&b, "\t%q -> %q%s;\n", pt.Send.Name, pt.Recv.Name, style)
+		}
+	}
+	b.WriteString("}\n")
+	return os.WriteFile(string(filename), []byte(b.String()), 0666)
+}
@@ -175,6 +175,26 @@ func (ev *LEDEnv) OutErr(tsr *tensor.Float32, corLED int) (maxi int, err, err2 f
 	return
 }
 
+// TopKErr scores the output activity of network, returning 0 if the correct
+// item is among the k most active outputs, and 1 otherwise.
+// For k = 1 this is the same as the err from OutErr, except for ties,
+// which count against the correct item, so that an output with no
+// activity (all ties) is always an error.
+func (ev *LEDEnv) TopKErr(tsr *tensor.Float32, corLED, k int) float64 {
+	nc := ev.Output.Len()
+	corv := tsr.Float1D(corLED)
+	nabove := 0
+	for i := 0; i < nc; i++ {
+		if i != corLED && tsr.Float1D(i) >= corv {
+			nabove++
+		}
+	}
+	if nabove < k {
+		return 0
+	}
+	return 1
+}
+
 // DrawRandLED picks a new random LED and draws it
 func (ev *LEDEnv) DrawRandLED() {
 	rng := 1 + ev.MaxLED - ev.MinLED
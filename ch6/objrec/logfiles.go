@@ -0,0 +1,82 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/lab/base/mpi"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// SetLogFile sets the log file for the given mode and time if on is true,
+// using the standard log file name in the Log.OutputDir directory.
+// By default, an existing file is overwritten, but it can be rotated
+// and / or appended to, according to the Log config.
+func (ss *Sim) SetLogFile(on bool, mode etime.Modes, time etime.Times, logName, netName, runName string) {
+	if !on {
+		return
+	}
+	lc := &ss.Config.Log
+	fnm := ss.OutputFile(elog.LogFilename(logName, netName, runName))
+	if lc.Rotate {
+		errors.Log(RotateFile(fnm, lc.RotateMB))
+	}
+	if !lc.Append {
+		ss.Logs.SetLogFile(mode, time, fnm)
+		return
+	}
+	errors.Log(ss.AppendLogFile(mode, time, fnm))
+}
+
+// AppendLogFile sets the log file for the given mode and time to
+// append to the given file, which is created if it does not exist.
+// The column headers are only written if the file is empty.
+func (ss *Sim) AppendLogFile(mode etime.Modes, time etime.Times, fnm string) error {
+	lt := ss.Logs.Tables[etime.Scope(mode, time)]
+	if lt == nil {
+		return fmt.Errorf("AppendLogFile: log not found for mode: %s, time: %s", mode, time)
+	}
+	f, err := os.OpenFile(fnm, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	lt.File = f
+	lt.WroteHeaders = st.Size() > 0
+	mpi.Printf("Appending log to: %s\n", fnm)
+	return nil
+}
+
+// RotateFile renames the given file, if it exists and is at least
+// minMB megabytes in size, by adding the next numbered suffix that
+// is not already in use (e.g., .1, .2), so that it is kept and
+// a new file can be started in its place.
+func RotateFile(fnm string, minMB float64) error {
+	st, err := os.Stat(fnm)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if float64(st.Size()) < minMB*1.0e6 {
+		return nil
+	}
+	for n := 1; ; n++ {
+		rnm := fmt.Sprintf("%s.%d", fnm, n)
+		if _, err := os.Stat(rnm); os.IsNotExist(err) {
+			mpi.Printf("Rotating log: %s to: %s\n", fnm, rnm)
+			return os.Rename(fnm, rnm)
+		}
+	}
+}
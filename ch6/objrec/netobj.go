@@ -0,0 +1,100 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/math32"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// ExportNetViewOBJ writes the network as currently shown in the NetView
+// to the given Wavefront OBJ file, for 3D printing or static 3D figures.
+// Each unit is a box whose height and color reflect its value on the
+// NetView variable (e.g., Act), using per-vertex colors (v x y z r g b),
+// which are supported by Blender, MeshLab and most other 3D tools.
+// Layers are laid out as in the NetView, with Y as the vertical axis.
+func (ss *Sim) ExportNetViewOBJ(filename core.Filename) error { //types:add
+	varNm := "Act"
+	if nv := ss.ViewUpdate.View; nv != nil && nv.Var != "" {
+		varNm = nv.Var
+	}
+	f, err := os.Create(string(filename))
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "# network %s, variable %s\n", ss.Net.Name, varNm)
+	nvtx := 0 // number of vertices written so far
+	for _, ly := range ss.Net.Layers {
+		if ly.Off {
+			continue
+		}
+		fmt.Fprintf(w, "o %s\n", ly.Name)
+		lpos := ly.Pos.Pos
+		vi, _ := ly.UnitVarIndex(varNm)
+		for ui := range ly.Neurons {
+			val := ly.UnitValue1D(vi, ui, 0)
+			if math32.IsNaN(val) {
+				val = 0
+			}
+			x, y := unitXY(ly, ui)
+			pos := math32.Vec3(lpos.X+x, lpos.Z*layerZScale, -(lpos.Y + y))
+			writeUnitBox(w, pos, val, &nvtx)
+		}
+	}
+	err = w.Flush()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// layerZScale is the vertical distance between layer planes, in units.
+const layerZScale = 10
+
+// unitXY returns the position of given unit within its layer plane,
+// with a gap of one unit between the pools of 4D layers.
+func unitXY(ly *leabra.Layer, ui int) (x, y float32) {
+	sz := ly.Shape.Sizes
+	if len(sz) == 4 {
+		nuy, nux := sz[2], sz[3]
+		pi := ui / (nuy * nux)
+		pu := ui % (nuy * nux)
+		py, px := pi/sz[1], pi%sz[1]
+		return float32(px*(nux+1) + pu%nux), float32(py*(nuy+1) + pu/nux)
+	}
+	nux := sz[len(sz)-1]
+	return float32(ui % nux), float32(ui / nux)
+}
+
+// writeUnitBox writes a box for one unit at given base position,
+// with height and color given by its value, clipped to 0-1.
+// nv is the running vertex count, used for the face indexes.
+func writeUnitBox(w *bufio.Writer, pos math32.Vector3, val float32, nv *int) {
+	val = math32.Clamp(val, 0, 1)
+	const wd = 0.8
+	ht := max(val, 0.02)
+	// simple blue (0) to red (1) color scale
+	r, g, b := val, 0.2*(1-val), 1-val
+	for _, c := range [8][3]float32{
+		{0, 0, 0}, {wd, 0, 0}, {wd, 0, wd}, {0, 0, wd},
+		{0, ht, 0}, {wd, ht, 0}, {wd, ht, wd}, {0, ht, wd},
+	} {
+		fmt.Fprintf(w, "v %g %g %g %.3f %.3f %.3f\n", pos.X+c[0], pos.Y+c[1], pos.Z-c[2], r, g, b)
+	}
+	st := *nv + 1 // obj indexes start at 1
+	for _, fc := range [6][4]int{
+		{0, 3, 2, 1}, {4, 5, 6, 7}, {0, 1, 5, 4},
+		{1, 2, 6, 5}, {2, 3, 7, 6}, {3, 0, 4, 7},
+	} {
+		fmt.Fprintf(w, "f %d %d %d %d\n", st+fc[0], st+fc[1], st+fc[2], st+fc[3])
+	}
+	*nv += 8
+}
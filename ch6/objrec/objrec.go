@@ -16,8 +16,11 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"time"
 
+	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
@@ -27,6 +30,10 @@ import (
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/mpi"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/findlayer"
+	"github.com/compcogneuro/sims/v2/netcolor"
+	"github.com/compcogneuro/sims/v2/plottheme"
+	"github.com/compcogneuro/sims/v2/statstream"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -38,6 +45,7 @@ import (
 	"github.com/emer/emergent/v2/netview"
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/etensor/plot/plotcore"
 	"github.com/emer/etensor/tensor/stats/split"
 	"github.com/emer/etensor/tensor/stats/stats"
 	"github.com/emer/etensor/tensor/table"
@@ -73,6 +81,10 @@ type Sim struct {
 	// Probability of training on novel items (0 for first phase, then .5 = 50%)
 	PNovel float64
 
+	// wall-clock time of the most recent training epoch, and the running
+	// average over the epochs of the current run, updated at each epoch end
+	EpochTime string `edit:"-"`
+
 	// simulation configuration parameters -- set by .toml config file and / or args
 	Config Config `new-window:"+"`
 
@@ -105,6 +117,20 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// wall-clock start times of the current training run and epoch,
+	// for the EpochSec and RunSec timing stats
+	runStart, epochStart time.Time
+
+	// total duration and number of epochs in the current run, for the EpochTime average
+	epochDurs time.Duration
+	epochN    int
+
+	// checkpoint that RunNoGUI is resuming from, at the start of the next run
+	resume *Checkpoint
+
+	// server streaming the stats when Log.Stream is on
+	stream *statstream.Server
 }
 
 // New creates new blank elements and initializes defaults
@@ -238,6 +264,7 @@ func (ss *Sim) Init() {
 	// selected or patterns have been modified etc
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -268,6 +295,10 @@ func (ss *Sim) ConfigLoops() {
 
 	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, 75, 99)                // plus phase timing
 	leabra.LooperSimCycleAndLearn(ls, ss.Net, &ss.Context, &ss.ViewUpdate) // std algo code
+	// note: the per-cycle update runs entirely within leabra Network.Cycle, which
+	// iterates over layers and neurons itself. There is no batched / vectorized
+	// version of it in the leabra API, so there is nothing to select at this level:
+	// the main sim-level cost is the NetView update, controlled by ViewUpdate.
 
 	ls.Stacks[etime.Train].OnInit.Add("Init", func() { ss.Init() })
 
@@ -280,6 +311,17 @@ func (ss *Sim) ConfigLoops() {
 
 	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
+	// time.Now includes a monotonic clock reading, used by time.Since
+	ls.Loop(etime.Train, etime.Run).OnStart.Add("RunTimer", func() {
+		ss.runStart = time.Now()
+		ss.epochDurs, ss.epochN = 0, 0
+	})
+	ls.Loop(etime.Train, etime.Epoch).OnStart.Add("EpochTimer", func() {
+		ss.epochStart = time.Now()
+	})
+	ls.Loop(etime.Train, etime.Epoch).OnStart.Add("CheckpointSeed", ss.CheckpointSeed)
+	ls.Loop(etime.Train, etime.Epoch).OnEnd.Add("EpochTime", ss.UpdateEpochTime)
+
 	// Add Testing
 	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
 	trainEpoch.OnStart.Add("TestAtInterval", func() {
@@ -307,6 +349,8 @@ func (ss *Sim) ConfigLoops() {
 		ss.Log(mode.(etime.Modes), time.(etime.Times))
 	})
 	leabra.LooperResetLogBelow(ls, &ss.Logs)
+	// after ResetLog, so the Epoch log from the checkpoint is kept
+	ls.Loop(etime.Train, etime.Run).OnStart.Add("ResumeCheckpoint", ss.ResumeCheckpoint)
 
 	ls.Loop(etime.Train, etime.Trial).OnEnd.Add("LogAnalyze", func() {
 		trnEpc := ls.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
@@ -319,10 +363,17 @@ func (ss *Sim) ConfigLoops() {
 		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
 	})
 
+	ls.Loop(etime.Train, etime.Epoch).OnEnd.Add("SaveCheckpoint", ss.SaveCheckpoint)
+
 	// Save weights to file, to look at later
 	ls.Loop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
+		if !ss.Config.Log.SaveWeights {
+			return
+		}
 		ctrString := ss.Stats.PrintValues([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
-		leabra.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWeights, ctrString, ss.Stats.String("RunName"))
+		fnm := ss.OutputFile(leabra.WeightsFilename(ss.Net, ctrString, ss.Stats.String("RunName")))
+		fmt.Printf("Saving Weights to: %s\n", fnm)
+		errors.Log(ss.Net.SaveWeightsJSON(core.Filename(fnm)))
 	})
 
 	////////////////////////////////////////////
@@ -394,8 +445,10 @@ func (ss *Sim) ApplyInputs() {
 func (ss *Sim) NewRun() {
 	ctx := &ss.Context
 	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
-	ss.Envs.ByMode(etime.Train).Init(0)
-	ss.Envs.ByMode(etime.Test).Init(0)
+	// reset all envs, including the extra analysis / test envs
+	for _, ev := range ss.Envs {
+		ev.Init(0)
+	}
 	ctx.Reset()
 	ctx.Mode = etime.Train
 	ss.Net.InitWeights()
@@ -405,15 +458,21 @@ func (ss *Sim) NewRun() {
 	ss.Logs.ResetLog(etime.Test, etime.Epoch)
 }
 
-// TestAll runs through the full set of testing items
-func (ss *Sim) TestAll() {
+// TestAll runs through the full set of testing items, and returns
+// the accuracy, as the proportion of testing items that are correct
+// (PctCor in the Test Epoch log, from 0 to 1).
+func (ss *Sim) TestAll() float64 {
 	ss.Envs.ByMode(etime.Test).Init(0)
 	ss.Stats.ActRFs.Reset()
 	ss.Loops.ResetAndRun(etime.Test)
 	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
 	ss.Stats.ActRFsAvgNorm()
 	ss.GUI.ViewActRFs(&ss.Stats.ActRFs)
-
+	dt := ss.Logs.Table(etime.Test, etime.Epoch)
+	if dt.Rows == 0 {
+		return 0
+	}
+	return dt.Float("PctCor", dt.Rows-1)
 }
 
 // RunTestAll runs through the full set of testing items, has stop running = false at end -- for gui
@@ -427,10 +486,26 @@ func (ss *Sim) RunTestAll() {
 ////////////////////////////////////////////////////////////////////////////////////////////
 // 		Stats
 
+// UpdateEpochTime updates the EpochTime readout in the GUI with the
+// duration of the epoch that just ended, and the average over the run.
+func (ss *Sim) UpdateEpochTime() {
+	d := time.Since(ss.epochStart)
+	ss.epochDurs += d
+	ss.epochN++
+	avg := ss.epochDurs / time.Duration(ss.epochN)
+	ss.EpochTime = fmt.Sprintf("%.2fs (avg %.2fs)", d.Seconds(), avg.Seconds())
+	if fm := ss.GUI.SimForm; fm != nil {
+		fm.AsyncLock()
+		fm.Update()
+		fm.AsyncUnlock()
+	}
+}
+
 // InitStats initializes all the statistics.
 // called at start of new run
 func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("SSE", 0.0)
+	ss.Stats.SetFloat("TopKErr", 0.0)
 	ss.Stats.SetInt("Cat", 0)
 	ss.Stats.SetString("Cat", "0")
 	ss.Stats.SetString("TrialName", "0")
@@ -478,6 +553,7 @@ func (ss *Sim) TrialStats() {
 	rsp, trlErr, trlErr2 := ev.OutErr(ovt, cat)
 	ss.Stats.SetFloat("TrlErr", trlErr)
 	ss.Stats.SetFloat("TrlErr2", trlErr2)
+	ss.Stats.SetFloat("TopKErr", ev.TopKErr(ovt, cat, ss.Config.Run.TopK))
 	ss.Stats.SetString("TrlOut", fmt.Sprintf("%d", rsp))
 	ss.Stats.SetString("Cat", fmt.Sprintf("%d", cat))
 }
@@ -490,12 +566,34 @@ func (ss *Sim) ConfigLogs() {
 
 	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
 	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
+
+	// wall-clock seconds for each training epoch, including any testing
+	// done within it, and cumulative seconds since the start of the run
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "EpochSec",
+		Type:   reflect.Float64,
+		FixMin: true,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Train, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetFloat64(time.Since(ss.epochStart).Seconds())
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "RunSec",
+		Type:   reflect.Float64,
+		FixMin: true,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Train, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetFloat64(time.Since(ss.runStart).Seconds())
+			}, etime.Scope(etime.Train, etime.Run): func(ctx *elog.Context) {
+				ctx.SetFloat64(time.Since(ss.runStart).Seconds())
+			}}})
 	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
 	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "Cat", "TrialName")
 
 	ss.Logs.AddStatAggItem("SSE", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("AvgSSE", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddErrStatAggItems("TrlErr", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("TopKErr", etime.Run, etime.Epoch, etime.Trial)
 
 	ss.ConfigLogItems()
 
@@ -514,7 +612,7 @@ func (ss *Sim) ConfigLogs() {
 	// this was useful during development of trace learning:
 	// leabra.LogAddCaLrnDiagnosticItems(&ss.Logs, ss.Net, etime.Epoch, etime.Trial)
 
-	ss.Logs.PlotItems("PctErr")
+	ss.Logs.PlotItems("PctErr", "TopKErr", "EpochSec")
 
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
@@ -525,6 +623,8 @@ func (ss *Sim) ConfigLogs() {
 	// note: Analyze not plotted by default
 	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
 	ss.Logs.SetMeta(etime.Test, etime.Epoch, "Type", "Bar")
+
+	ss.ConfigRotationTest(ss.Logs.MiscTable("RotationTest"))
 }
 
 // ConfigLogItems specifies extra logging items
@@ -587,6 +687,9 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 	}
 
 	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
+	if ss.stream != nil && mode == etime.Train {
+		errors.Log(ss.stream.SendRow(dt, row))
+	}
 }
 
 // ConfigActRFs
@@ -601,6 +704,7 @@ func (ss *Sim) ConfigActRFs() {
 // ConfigGUI configures the Cogent Core GUI interface for this simulation.
 func (ss *Sim) ConfigGUI() {
 	title := "Object Recognition"
+	plottheme.Init(ss.Config.Dark)
 	ss.GUI.MakeBody(ss, "objrec", title, `This simulation explores how a hierarchy of areas in the ventral stream of visual processing (up to inferotemporal (IT) cortex) can produce robust object recognition that is invariant to changes in position, size, etc of retinal input images. See <a href="https://github.com/compcogneuro/sims/blob/main/ch6/objrec/README.md">README.md on GitHub</a>.</p>`, readme)
 	ss.GUI.CycleUpdateInterval = 10
 
@@ -608,6 +712,7 @@ func (ss *Sim) ConfigGUI() {
 	nv.Options.MaxRecs = 300
 	nv.Options.Raster.Max = 100
 	nv.Options.LayerNameSize = 0.03
+	errors.Log(netcolor.Set(nv, ss.Config.ColorMap))
 	nv.SetNet(ss.Net)
 	ss.ViewUpdate.Config(nv, etime.GammaCycle, etime.GammaCycle)
 
@@ -626,11 +731,35 @@ func (ss *Sim) ConfigGUI() {
 
 	ss.GUI.AddActRFGridTabs(&ss.Stats.ActRFs)
 
+	stnm := "RotationTest"
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey(stnm), stnm+" Plot")
+	plt.Options.Title = "Percent Correct by Rotation Angle"
+	plt.Options.XAxis = "Angle"
+	plt.SetTable(ss.Logs.MiscTable(stnm))
+	// order of params: on, fixMin, min, fixMax, max
+	plt.SetColumnOptions("PctCor", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 1)
+
 	ss.GUI.FinalizeGUI(false)
 }
 
+// FindLayer points the Network view camera at the first layer whose name
+// matches the given name, or part of it (see findlayer.Match).
+func (ss *Sim) FindLayer(name string) { //types:add
+	names := make([]string, len(ss.Net.Layers))
+	for i, ly := range ss.Net.Layers {
+		names[i] = ly.Name
+	}
+	lnm := findlayer.Match(names, name)
+	nv := ss.GUI.ViewUpdate.View
+	if lnm == "" || nv == nil || !findlayer.Focus(nv, lnm) {
+		core.MessageSnackbar(ss.GUI.Body, "No layer matching: "+name)
+	}
+}
+
 func (ss *Sim) MakeToolbar(p *tree.Plan) {
 	ss.GUI.AddLooperCtrl(p, ss.Loops)
+	findlayer.AddField(p, ss.FindLayer)
+	plottheme.AddSwitch(p, &ss.Config.Dark)
 
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Test All",
 		Icon:    icons.PlayArrow,
@@ -645,6 +774,19 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 		},
 	})
 
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Test Rotations",
+		Icon:    icons.PlayArrow,
+		Tooltip: "Tests the objects at a range of in-plane rotation angles, and plots the percent correct as a function of angle in the RotationTest Plot.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if !ss.GUI.IsRunning {
+				ss.GUI.IsRunning = true
+				ss.GUI.UpdateWindow()
+				go ss.RunTestRotations()
+			}
+		},
+	})
+
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Open Trained Wts", Icon: icons.Open,
 		Tooltip: "Opened weights from the first phase of training, which excludes novel objects",
 		Active:  egui.ActiveStopped,
@@ -671,6 +813,30 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 		},
 	})
 
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Export Stats JSON", Icon: icons.Save,
+		Tooltip: "Save the current stats and all of the log tables to a JSON file, for analysis in other tools",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ExportStatsJSON)
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Export Topology", Icon: icons.Save,
+		Tooltip: "Save the network layers and pathways as a GraphViz DOT graph, for rendering a diagram of the model",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ExportTopology)
+		},
+	})
+
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Export NetView OBJ", Icon: icons.Save,
+		Tooltip: "Save the network with the current NetView variable values as a 3D OBJ file, with unit height and color showing the values",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ExportNetViewOBJ)
+		},
+	})
+
 	////////////////////////////////////////////////
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset RunLog",
@@ -715,15 +881,21 @@ func (ss *Sim) RunNoGUI() {
 	if ss.Config.Log.SaveWeights {
 		mpi.Printf("Saving final weights per run\n")
 	}
+	if od := ss.Config.Log.OutputDir; od != "" {
+		mpi.Printf("Saving output files in: %s\n", od)
+		if errors.Log(os.MkdirAll(od, 0755)) != nil {
+			return
+		}
+	}
 	runName := ss.Params.RunName(ss.Config.Run.Run)
 	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
 	netName := ss.Net.Name
 
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Trial, etime.Train, etime.Trial, "trl", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Epoch, etime.Train, etime.Epoch, "epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Run, etime.Train, etime.Run, "run", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestEpoch, etime.Test, etime.Epoch, "tst_epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestTrial, etime.Test, etime.Trial, "tst_trl", netName, runName)
+	ss.SetLogFile(ss.Config.Log.Trial, etime.Train, etime.Trial, "trl", netName, runName)
+	ss.SetLogFile(ss.Config.Log.Epoch, etime.Train, etime.Epoch, "epc", netName, runName)
+	ss.SetLogFile(ss.Config.Log.Run, etime.Train, etime.Run, "run", netName, runName)
+	ss.SetLogFile(ss.Config.Log.TestEpoch, etime.Test, etime.Epoch, "tst_epc", netName, runName)
+	ss.SetLogFile(ss.Config.Log.TestTrial, etime.Test, etime.Trial, "tst_trl", netName, runName)
 
 	netdata := ss.Config.Log.NetData
 	if netdata {
@@ -731,16 +903,44 @@ func (ss *Sim) RunNoGUI() {
 		ss.GUI.InitNetData(ss.Net, 200)
 	}
 
+	if ss.Config.Log.Stream {
+		sv, err := statstream.Start(ss.Config.Log.StreamAddr)
+		if errors.Log(err) == nil {
+			mpi.Printf("Streaming stats at: http://%s\n", sv.Addr)
+			ss.stream = sv
+			defer func() {
+				sv.Close()
+				ss.stream = nil
+			}()
+		}
+	}
+
 	ss.Init()
 
 	mpi.Printf("Running %d Runs starting at %d\n", ss.Config.Run.NRuns, ss.Config.Run.Run)
 	ss.Loops.Loop(etime.Train, etime.Run).Counter.SetCurMaxPlusN(ss.Config.Run.Run, ss.Config.Run.NRuns)
+	if ss.Config.Run.Resume {
+		if ck := ss.OpenCheckpoint(); ck != nil && ck.Run >= ss.Config.Run.Run {
+			ss.resume = ck
+			ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur = ck.Run
+		}
+	}
 
 	ss.Loops.Run(etime.Train)
+	if rl := ss.Loops.Loop(etime.Train, etime.Run).Counter; ss.Config.Log.Checkpoint > 0 && rl.Cur >= rl.Max {
+		ss.RemoveCheckpoint() // all runs are done
+	}
 
 	ss.Logs.CloseLogFiles()
 
 	if netdata {
-		ss.GUI.SaveNetData(ss.Stats.String("RunName"))
+		fnm := ss.OutputFile(ss.Net.Name + "_" + ss.Stats.String("RunName") + ".netdata.gz")
+		errors.Log(ss.GUI.NetData.SaveJSON(core.Filename(fnm)))
 	}
 }
+
+// OutputFile returns the path for the given output file name,
+// in the Log.OutputDir directory.
+func (ss *Sim) OutputFile(fnm string) string {
+	return filepath.Join(ss.Config.Log.OutputDir, fnm)
+}
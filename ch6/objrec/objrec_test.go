@@ -0,0 +1,39 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/compcogneuro/sims/v2/simtest"
+)
+
+// TestNewRunEnvs checks that NewRun resets the counters of all the envs,
+// including the extra analysis / test envs, to their starting values.
+func TestNewRunEnvs(t *testing.T) {
+	ss := newTestSim(t, t.TempDir(), 1, false)
+	ss.NewRun()
+	start := simtest.EnvCounters(ss.Envs)
+	for _, ev := range ss.Envs {
+		for range 3 {
+			ev.Step()
+		}
+	}
+	if reflect.DeepEqual(simtest.EnvCounters(ss.Envs), start) {
+		t.Fatalf("env counters did not change with Step: %v", start)
+	}
+	ss.NewRun()
+	if cs := simtest.EnvCounters(ss.Envs); !reflect.DeepEqual(cs, start) {
+		t.Errorf("env counters after NewRun: %v, want %v", cs, start)
+	}
+}
+
+// TestInitClearsLogs checks that Init after training clears the Train
+// logs and the accumulated Stats, so the plots start out empty.
+func TestInitClearsLogs(t *testing.T) {
+	ss := newTestSim(t, t.TempDir(), 1, false)
+	simtest.InitClearsLogs(t, ss.Init, ss.Loops, &ss.Logs, &ss.Stats)
+}
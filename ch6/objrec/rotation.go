@@ -0,0 +1,75 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// DefaultRotAngles are the in-plane rotations tested by TestRotations
+// if Config.Run.RotAngles is empty.
+var DefaultRotAngles = []float32{0, 15, 30, 45, 60, 90, 135, 180}
+
+// TestRotations tests the objects at each of the Config.Run.RotAngles
+// in-plane rotations (in degrees), with Config.Run.RotTrials trials per
+// angle and the usual random position and size, and records the percent
+// correct at each angle in the RotationTest plot.  Training only includes
+// small rotations, so this probes how far the invariance of the model
+// extends to a transformation it has not learned.
+func (ss *Sim) TestRotations() {
+	tst := ss.Envs.ByMode(etime.Test).(*LEDEnv)
+	angles := ss.Config.Run.RotAngles
+	if len(angles) == 0 {
+		angles = DefaultRotAngles
+	}
+	dt := ss.Logs.MiscTable("RotationTest")
+	dt.SetNumRows(0)
+	rot, ntrl := tst.XFormRand.Rot, tst.Trial.Max
+	tstTrial := ss.Loops.Loop(etime.Test, etime.Trial)
+	trlMax := tstTrial.Counter.Max
+	tst.Trial.Max = ss.Config.Run.RotTrials
+	tstTrial.Counter.Max = ss.Config.Run.RotTrials
+	for _, ang := range angles {
+		if ss.GUI.StopNow {
+			break
+		}
+		tst.XFormRand.Rot.Set(ang, ang)
+		ss.TestAll()
+		epc := ss.Logs.Table(etime.Test, etime.Epoch)
+		pc := epc.Float("PctCor", epc.Rows-1)
+		row := dt.Rows
+		dt.SetNumRows(row + 1)
+		dt.SetFloat("Angle", row, float64(ang))
+		dt.SetFloat("PctCor", row, pc)
+		if plt := ss.GUI.PlotByName("RotationTest"); plt != nil {
+			plt.GoUpdatePlot()
+		} else {
+			fmt.Printf("Rotation: %g\tPctCor: %.4g\n", ang, pc)
+		}
+	}
+	tst.XFormRand.Rot = rot
+	tst.Trial.Max = ntrl
+	tstTrial.Counter.Max = trlMax
+}
+
+// RunTestRotations runs TestRotations in the GUI.
+func (ss *Sim) RunTestRotations() {
+	ss.GUI.StopNow = false
+	ss.TestRotations()
+	ss.GUI.Stopped()
+}
+
+// ConfigRotationTest configures the table of results from TestRotations,
+// with the percent correct for each rotation angle.
+func (ss *Sim) ConfigRotationTest(dt *table.Table) {
+	dt.SetMetaData("name", "RotationTest")
+	dt.SetMetaData("desc", "percent correct as a function of in-plane rotation angle")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Angle")
+	dt.AddFloat64Column("PctCor")
+}
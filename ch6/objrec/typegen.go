@@ -6,15 +6,23 @@ import (
 	"cogentcore.org/core/types"
 )
 
+var _ = types.AddType(&types.Type{Name: "main.Checkpoint", IDName: "checkpoint", Doc: "Checkpoint records the state of a training run at the end of an epoch,\nsaved every Log.Checkpoint epochs, from which the run can be resumed\nwith the Run.Resume option, e.g., after a long run has been interrupted.", Fields: []types.Field{{Name: "Run", Doc: "run number"}, {Name: "Epoch", Doc: "last epoch that was completed in the run"}, {Name: "Weights", Doc: "file with the weights at the end of Epoch"}, {Name: "State", Doc: "file with the rest of the state of the network and envs at the end\nof Epoch, including the running averages of the neurons, saved by SaveState"}, {Name: "NZero", Doc: "error stats at the end of Epoch, from the elog ErrStat items,\nwhich depend on the previous epochs"}, {Name: "FirstZero", Doc: "error stats at the end of Epoch, from the elog ErrStat items,\nwhich depend on the previous epochs"}, {Name: "LastZero", Doc: "error stats at the end of Epoch, from the elog ErrStat items,\nwhich depend on the previous epochs"}, {Name: "EpochLog", Doc: "files with the Train Epoch log of the run through Epoch,\nand the Train Run log of the previous runs"}, {Name: "RunLog", Doc: "files with the Train Epoch log of the run through Epoch,\nand the Train Run log of the previous runs"}}})
+
 var _ = types.AddType(&types.Type{Name: "main.EnvConfig", IDName: "env-config", Doc: "EnvConfig has config params for environment\nnote: only adding fields for key Env params that matter for both Network and Env\nother params are set via the Env map data mechanism.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Env", Doc: "env parameters -- can set any field/subfield on Env struct, using standard TOML formatting"}}})
 
 var _ = types.AddType(&types.Type{Name: "main.ParamConfig", IDName: "param-config", Doc: "ParamConfig has config parameters related to sim params", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Network", Doc: "network parameters"}, {Name: "Sheet", Doc: "Extra Param Sheet name(s) to use (space separated if multiple) -- must be valid name as listed in compiled-in params or loaded params"}, {Name: "Tag", Doc: "extra tag to add to file names and logs saved from this run"}, {Name: "Note", Doc: "user note -- describe the run params etc -- like a git commit message for the run"}, {Name: "File", Doc: "Name of the JSON file to input saved parameters from."}, {Name: "SaveAll", Doc: "Save a snapshot of all current param and config settings in a directory named params_<datestamp> (or _good if Good is true), then quit -- useful for comparing to later changes and seeing multiple views of current params"}, {Name: "Good", Doc: "for SaveAll, save to params_good for a known good params state.  This can be done prior to making a new release after all tests are passing -- add results to git to provide a full diff record of all params over time."}, {Name: "V1V4Path"}}})
 
-var _ = types.AddType(&types.Type{Name: "main.RunConfig", IDName: "run-config", Doc: "RunConfig has config parameters related to running the sim", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Run", Doc: "starting run number -- determines the random seed -- runs counts from there -- can do all runs in parallel by launching separate jobs with each run, runs = 1"}, {Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NTrials", Doc: "total number of trials per epoch.  Should be an even multiple of NData."}, {Name: "PCAInterval", Doc: "how frequently (in epochs) to compute PCA on hidden representations to measure variance?"}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing"}}})
+var _ = types.AddType(&types.Type{Name: "main.RunConfig", IDName: "run-config", Doc: "RunConfig has config parameters related to running the sim", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Run", Doc: "starting run number -- determines the random seed -- runs counts from there -- can do all runs in parallel by launching separate jobs with each run, runs = 1"}, {Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NTrials", Doc: "total number of trials per epoch.  Should be an even multiple of NData."}, {Name: "PCAInterval", Doc: "how frequently (in epochs) to compute PCA on hidden representations to measure variance?"}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs -- can use 0 or -1 for no testing"}, {Name: "TopK", Doc: "number of most active outputs that can include the correct category\nfor a trial to count as correct in the TopKErr stat"}, {Name: "RotAngles", Doc: "in-plane rotation angles in degrees to test in TestRotations;\nuses DefaultRotAngles if empty"}, {Name: "RotTrials", Doc: "number of testing trials per angle in TestRotations"}, {Name: "Resume", Doc: "if true, RunNoGUI resumes training from the checkpoint saved with the\nLog.Checkpoint option, if there is one, instead of starting over.\nThe Log.Append option should also be used to keep the earlier logs."}}})
+
+var _ = types.AddType(&types.Type{Name: "main.LogConfig", IDName: "log-config", Doc: "LogConfig has config parameters related to logging data.\nLog files are written as tab-separated text, via elog SetLogFile.\nThere is no HDF5 option, as that requires a cgo HDF5 library that this\nmodule does not depend on; ExportStatsJSON writes all of the logs\ninto one structured file for loading into Python and other tools.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "OutputDir", Doc: "directory for the log, weights and netview data files saved when running\nwithout the GUI, which is created if it does not exist.\nThe current directory is used if empty."}, {Name: "Append", Doc: "if true, append to existing log files instead of overwriting them,\nso the results of repeated runs accumulate in the same files.\nColumn headers are only written to new files."}, {Name: "Rotate", Doc: "if true, existing log files are rotated, by renaming them with the next\nnumbered suffix (e.g., .1), so that the results in them are kept and\na new file is started.  See RotateMB for only rotating large files."}, {Name: "RotateMB", Doc: "if Rotate is on, only rotate existing log files that are at least\nthis size in megabytes -- 0 rotates all existing files.\nSmaller files are appended to or overwritten, according to Append."}, {Name: "Checkpoint", Doc: "save a checkpoint of the weights and training state every this many\ntraining epochs, which Run.Resume uses to resume an interrupted run.\nThe random seeds are set from the run seed and epoch at the start of\neach epoch when this is on, and the full state of the network is saved,\nincluding the running averages of the neurons, so resumed runs are the\nsame as uninterrupted ones.  The checkpoint is removed when all of the\nruns are done.  0 = no checkpoints."}, {Name: "SaveWeights", Doc: "if true, save final weights at the end of each run, in the OutputDir,\nin a .wts.gz file named by the network, the run name, and the run and\nepoch numbers, so the trained weights from every run are kept"}, {Name: "Epoch", Doc: "if true, save train epoch log to file, as .epc.tsv typically"}, {Name: "Run", Doc: "if true, save run log to file, as .run.tsv typically"}, {Name: "Trial", Doc: "if true, save train trial log to file, as .trl.tsv typically. May be large."}, {Name: "TestEpoch", Doc: "if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there."}, {Name: "TestTrial", Doc: "if true, save testing trial log to file, as .tst_trl.tsv typically. May be large."}, {Name: "NetData", Doc: "if true, save network activation etc data from testing trials, for later viewing in netview"}, {Name: "Stream", Doc: "if true, stream the train epoch and run stats as JSON over a websocket\nwhen running without the GUI, as they are logged, for monitoring long\nruns from a browser: open the StreamAddr to see them."}, {Name: "StreamAddr", Doc: "address for the Stream server.  Only reachable from the same\nmachine by default -- use e.g., :8765 to allow remote connections."}}})
+
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config is a standard Sim config -- use as a starting point.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Includes", Doc: "specify include files here, and after configuration, it contains list of include files added"}, {Name: "GUI", Doc: "open the GUI -- does not automatically run -- if false, then runs automatically and quits"}, {Name: "Debug", Doc: "log debugging information"}, {Name: "ColorMap", Doc: "name of the color map for the unit values in the Network view, e.g.,\nViridis for color blindness, or DarkLight (grayscale) for printing"}, {Name: "Dark", Doc: "use the dark theme for the plots (and the rest of the window),\ne.g., for presenting in a dark room"}, {Name: "Env", Doc: "environment configuration options"}, {Name: "Params", Doc: "parameter related configuration options"}, {Name: "Run", Doc: "sim running related configuration options"}, {Name: "Log", Doc: "data logging related configuration options"}}})
+
+var _ = types.AddType(&types.Type{Name: "main.StatsJSON", IDName: "stats-json", Doc: "StatsJSON is the structure written by ExportStatsJSON.", Fields: []types.Field{{Name: "Network", Doc: "name of the network"}, {Name: "RunName", Doc: "RunName stat, identifying the params used"}, {Name: "Floats", Doc: "current scalar stat values"}, {Name: "Ints"}, {Name: "Strings"}, {Name: "Tensors", Doc: "current tensor stat values"}, {Name: "Logs", Doc: "all of the log tables, in mode, level order"}}})
 
-var _ = types.AddType(&types.Type{Name: "main.LogConfig", IDName: "log-config", Doc: "LogConfig has config parameters related to logging data", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "SaveWeights", Doc: "if true, save final weights after each run"}, {Name: "Epoch", Doc: "if true, save train epoch log to file, as .epc.tsv typically"}, {Name: "Run", Doc: "if true, save run log to file, as .run.tsv typically"}, {Name: "Trial", Doc: "if true, save train trial log to file, as .trl.tsv typically. May be large."}, {Name: "TestEpoch", Doc: "if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there."}, {Name: "TestTrial", Doc: "if true, save testing trial log to file, as .tst_trl.tsv typically. May be large."}, {Name: "NetData", Doc: "if true, save network activation etc data from testing trials, for later viewing in netview"}}})
+var _ = types.AddType(&types.Type{Name: "main.TensorJSON", IDName: "tensor-json", Doc: "TensorJSON is a tensor with its shape, for JSON export.", Fields: []types.Field{{Name: "Shape"}, {Name: "Values"}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config is a standard Sim config -- use as a starting point.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Includes", Doc: "specify include files here, and after configuration, it contains list of include files added"}, {Name: "GUI", Doc: "open the GUI -- does not automatically run -- if false, then runs automatically and quits"}, {Name: "Debug", Doc: "log debugging information"}, {Name: "Env", Doc: "environment configuration options"}, {Name: "Params", Doc: "parameter related configuration options"}, {Name: "Run", Doc: "sim running related configuration options"}, {Name: "Log", Doc: "data logging related configuration options"}}})
+var _ = types.AddType(&types.Type{Name: "main.LogJSON", IDName: "log-json", Doc: "LogJSON is one log table, with a slice of row values for each column,\nfor JSON export. Columns with multiple values per row have a slice\nfor each row. NaN values are written as null.", Fields: []types.Field{{Name: "Mode", Doc: "evaluation mode, e.g., Train, Test"}, {Name: "Level", Doc: "time level, e.g., Epoch, Trial"}, {Name: "Rows", Doc: "number of rows"}, {Name: "Columns", Doc: "names of the columns, in order"}, {Name: "Data", Doc: "values of each column, by column name"}}})
 
 var _ = types.AddType(&types.Type{Name: "main.LEDEnv", IDName: "led-env", Doc: "LEDEnv generates images of old-school \"LED\" style \"letters\" composed of a set of horizontal\nand vertical elements.  All possible such combinations of 3 out of 6 line segments are created.\nRenders using SVG.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment"}, {Name: "Draw", Doc: "draws LEDs onto image"}, {Name: "Vis", Doc: "visual processing params"}, {Name: "MinLED", Doc: "minimum LED number to draw (0-19)"}, {Name: "MaxLED", Doc: "maximum LED number to draw (0-19)"}, {Name: "CurLED", Doc: "current LED number that was drawn"}, {Name: "PrvLED", Doc: "previous LED number that was drawn"}, {Name: "XFormRand", Doc: "random transform parameters"}, {Name: "XForm", Doc: "current -- prev transforms"}, {Name: "Trial", Doc: "trial is the step counter for items"}, {Name: "OrigImg", Doc: "original image prior to random transforms"}, {Name: "Output", Doc: "CurLED one-hot output tensor"}}})
 
@@ -22,6 +30,6 @@ var _ = types.AddType(&types.Type{Name: "main.LEDraw", IDName: "le-draw", Doc: "
 
 var _ = types.AddType(&types.Type{Name: "main.LEDSegs", IDName: "led-segs", Doc: "LEDSegs are the led segments"})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "PNovel", Doc: "Probability of training on novel items (0 for first phase, then .5 = 50%)"}, {Name: "Config", Doc: "simulation configuration parameters -- set by .toml config file and / or args"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "all parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "ExportStatsJSON", Doc: "ExportStatsJSON writes the current stats and all of the log tables\nto the given file in JSON format, for loading into other analysis tools.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}, {Name: "ExportTopology", Doc: "ExportTopology writes the network layers and pathways to the given file\nas a GraphViz DOT graph, which can be rendered with e.g.:\ndot -Tsvg objrec.dot -o objrec.svg\nNodes show the layer shape and type, with input, target and compare\nlayers in distinct colors, and back pathways are drawn dashed.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}, {Name: "ExportNetViewOBJ", Doc: "ExportNetViewOBJ writes the network as currently shown in the NetView\nto the given Wavefront OBJ file, for 3D printing or static 3D figures.\nEach unit is a box whose height and color reflect its value on the\nNetView variable (e.g., Act), using per-vertex colors (v x y z r g b),\nwhich are supported by Blender, MeshLab and most other 3D tools.\nLayers are laid out as in the NetView, with Y as the vertical axis.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}, {Name: "FindLayer", Doc: "FindLayer points the Network view camera at the first layer whose name\nmatches the given name, or part of it (see findlayer.Match).", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"name"}}}, Fields: []types.Field{{Name: "PNovel", Doc: "Probability of training on novel items (0 for first phase, then .5 = 50%)"}, {Name: "EpochTime", Doc: "wall-clock time of the most recent training epoch, and the running\naverage over the epochs of the current run, updated at each epoch end"}, {Name: "Config", Doc: "simulation configuration parameters -- set by .toml config file and / or args"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "all parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
 
 var _ = types.AddType(&types.Type{Name: "main.Vis", IDName: "vis", Doc: "Vis encapsulates specific visual processing pipeline for V1 filtering", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "V1sGabor", Doc: "V1 simple gabor filter parameters"}, {Name: "V1sGeom", Doc: "geometry of input, output for V1 simple-cell processing"}, {Name: "V1sNeighInhib", Doc: "neighborhood inhibition for V1s -- each unit gets inhibition from same feature in nearest orthogonal neighbors -- reduces redundancy of feature code"}, {Name: "V1sKWTA", Doc: "kwta parameters for V1s"}, {Name: "ImgSize", Doc: "target image size to use -- images will be rescaled to this size"}, {Name: "V1sGaborTsr", Doc: "V1 simple gabor filter tensor"}, {Name: "ImgTsr", Doc: "input image as tensor"}, {Name: "Img", Doc: "current input image"}, {Name: "V1sTsr", Doc: "V1 simple gabor filter output tensor"}, {Name: "V1sExtGiTsr", Doc: "V1 simple extra Gi from neighbor inhibition tensor"}, {Name: "V1sKwtaTsr", Doc: "V1 simple gabor filter output, kwta output tensor"}, {Name: "V1sPoolTsr", Doc: "V1 simple gabor filter output, max-pooled 2x2 of V1sKwta tensor"}, {Name: "V1sUnPoolTsr", Doc: "V1 simple gabor filter output, un-max-pooled 2x2 of V1sPool tensor"}, {Name: "V1sAngOnlyTsr", Doc: "V1 simple gabor filter output, angle-only features tensor"}, {Name: "V1sAngPoolTsr", Doc: "V1 simple gabor filter output, max-pooled 2x2 of AngOnly tensor"}, {Name: "V1cLenSumTsr", Doc: "V1 complex length sum filter output tensor"}, {Name: "V1cEndStopTsr", Doc: "V1 complex end stop filter output tensor"}, {Name: "V1AllTsr", Doc: "Combined V1 output tensor with V1s simple as first two rows, then length sum, then end stops = 5 rows total"}, {Name: "V1sInhibs", Doc: "inhibition values for V1s KWTA"}}})
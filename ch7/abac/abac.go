@@ -10,8 +10,11 @@ package main
 //go:generate core generate -add-types
 
 import (
+	"context"
 	"embed"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 
 	"cogentcore.org/core/base/errors"
@@ -21,6 +24,9 @@ import (
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/golden"
+	"github.com/compcogneuro/sims/v2/headless"
+	"github.com/compcogneuro/sims/v2/simapi"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -51,8 +57,14 @@ var readme embed.FS
 func main() {
 	sim := &Sim{}
 	sim.New()
-	sim.ConfigAll()
-	sim.RunGUI()
+	if sim.Config.Golden {
+		sim.RunGolden()
+	} else if sim.Config.Serve {
+		sim.RunServe()
+	} else {
+		sim.ConfigAll()
+		sim.RunGUI()
+	}
 }
 
 // ParamSets is the default set of parameters.
@@ -96,6 +108,24 @@ type Config struct {
 	// how often to run through all the test patterns, in terms of training epochs.
 	// can use 0 or -1 for no testing.
 	TestInterval int `default:"1"`
+
+	// run headless for the golden-file regression test (see the golden package),
+	// comparing the Train Epoch log against testdata/golden_epc.tsv
+	Golden bool
+
+	// regenerate the golden file from the current results, instead of comparing
+	GoldenUpdate bool
+
+	// number of epochs to train in one run for the golden-file regression test
+	GoldenEpochs int `default:"10"`
+
+	// serve an HTTP API for driving the model without the GUI (see the simapi
+	// package), e.g., from a web front-end, instead of opening the GUI
+	Serve bool
+
+	// address for the Serve API.  Only reachable from the same
+	// machine by default -- use e.g., :8766 to allow remote connections.
+	ServeAddr string `default:"localhost:8766"`
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -162,6 +192,9 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// running without the GUI, so the GUI updates are not configured
+	noGUI bool
 }
 
 // New creates new blank elements and initializes defaults
@@ -307,6 +340,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -349,14 +383,6 @@ func (ss *Sim) ConfigLoops() {
 
 	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
-	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunDone", func() {
-		if ss.Stats.Int("Run") >= ss.Config.NRuns-1 {
-			ss.RunStats()
-			expt := ss.Stats.Int("Expt")
-			ss.Stats.SetInt("Expt", expt+1)
-		}
-	})
-
 	// Train stop early condition
 	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("NZeroStop", func() bool {
 		// This is calculated in TrialStats
@@ -377,6 +403,7 @@ func (ss *Sim) ConfigLoops() {
 		if stop || epc >= 50 {
 			ss.Stats.SetInt("FirstPerfect", epc)
 			trn.Config(table.NewIndexView(ss.ACPatterns))
+			ss.AddEvent(epc+1, "AC") // first epoch of training on the AC list
 		}
 		return false
 	})
@@ -403,15 +430,25 @@ func (ss *Sim) ConfigLoops() {
 	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
 		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
 	})
+	// after the Log, so that the last run is included
+	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunDone", func() {
+		if ss.Stats.Int("Run") >= ss.Config.NRuns-1 {
+			ss.RunStats()
+			expt := ss.Stats.Int("Expt")
+			ss.Stats.SetInt("Expt", expt+1)
+		}
+	})
 
 	////////////////////////////////////////////
 	// GUI
 
-	leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
-	leabra.LooperUpdatePlots(ls, &ss.GUI)
+	if !ss.noGUI {
+		leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
+		leabra.LooperUpdatePlots(ls, &ss.GUI)
 
-	ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
-	ls.Stacks[etime.Test].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+		ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+		ls.Stacks[etime.Test].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+	}
 
 	ss.Loops = ls
 }
@@ -455,6 +492,7 @@ func (ss *Sim) NewRun() {
 	ss.StatCounters()
 	ss.Logs.ResetLog(etime.Train, etime.Epoch)
 	ss.Logs.ResetLog(etime.Test, etime.Epoch)
+	ss.Logs.MiscTable("Events").SetNumRows(0)
 }
 
 // TestAll runs through the full set of testing items
@@ -540,9 +578,13 @@ func (ss *Sim) RunStats() {
 	runix := table.NewIndexView(dt)
 	spl := split.GroupBy(runix, "Expt")
 	split.DescColumn(spl, "ABErr")
+	split.DescColumn(spl, "ACErr")
 	st := spl.AggsToTableCopy(table.AddAggName)
 	ss.Logs.MiscTables["RunStats"] = st
 	plt := ss.GUI.Plots[etime.ScopeKey("RunStats")]
+	if plt == nil { // no GUI
+		return
+	}
 
 	st.SetMetaData("XAxis", "RunName")
 
@@ -555,6 +597,16 @@ func (ss *Sim) RunStats() {
 	st.SetMetaData("ABErr:Mean:Max", "1")
 	st.SetMetaData("ABErr:Min:On", "+")
 	st.SetMetaData("ABErr:Count:On", "-")
+	// error bars show the standard error of the mean across runs
+	st.SetMetaData("ABErr:Mean:ErrColumn", "ABErr:Sem")
+
+	st.SetMetaData("ACErr:Mean:On", "+")
+	st.SetMetaData("ACErr:Mean:FixMin", "true")
+	st.SetMetaData("ACErr:Mean:FixMax", "true")
+	st.SetMetaData("ACErr:Mean:Min", "0")
+	st.SetMetaData("ACErr:Mean:Max", "1")
+	st.SetMetaData("ACErr:Mean:ErrColumn", "ACErr:Sem")
+	st.SetMetaData("ACErr:Count:On", "-")
 
 	plt.SetTable(st)
 	plt.GoUpdatePlot()
@@ -612,6 +664,8 @@ func (ss *Sim) ConfigLogs() {
 				ctx.SetFloat64(ctx.Stats.Float("ACErr"))
 			}}})
 
+	ss.ConfigEventItems()
+
 	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
 
 	ss.Logs.AddLayerTensorItems(ss.Net, "ActM", etime.Test, etime.Trial, "InputLayer", "SuperLayer", "TargetLayer")
@@ -627,6 +681,8 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.NoPlot(etime.Test, etime.Trial)
 	ss.Logs.NoPlot(etime.Test, etime.Run)
 	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+
+	ss.ConfigEvents(ss.Logs.MiscTable("Events"))
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -760,3 +816,65 @@ func (ss *Sim) RunGUI() {
 	ss.ConfigGUI()
 	ss.GUI.Body.RunMainWindow()
 }
+
+// SimConfig returns a pointer to the Config, for running the
+// model with the headless package.
+func (ss *Sim) SimConfig() *Config {
+	return &ss.Config
+}
+
+// RunHeadless configures and runs the model without the GUI, according
+// to the current Config, and returns a copy of the Train Epoch log, which
+// has the stats for each epoch of the last run.  No files are written.
+// The run stops early when the context is done.
+// Use headless.Run to run the model with a given Config.
+func (ss *Sim) RunHeadless(ctx context.Context) *table.Table {
+	ss.noGUI = true
+	ss.ConfigAll()
+	headless.AddCancel(ctx, ss.Loops, etime.Train)
+	ss.Init()
+	ss.Loops.Run(etime.Train)
+	return ss.Logs.Table(etime.Train, etime.Epoch).Clone()
+}
+
+// CheckGolden runs the model headless for one run of GoldenEpochs epochs,
+// using the default random seed, and checks the resulting Train Epoch log
+// against the golden file, or regenerates it if GoldenUpdate is set.
+func (ss *Sim) CheckGolden() error {
+	ss.Config.NRuns = 1
+	ss.Config.NEpochs = ss.Config.GoldenEpochs
+	dt := ss.RunHeadless(context.Background())
+	return golden.Check(dt, filepath.Join("testdata", "golden_epc.tsv"), ss.Config.GoldenUpdate, "PerTrlMSec")
+}
+
+// RunGolden runs CheckGolden, and exits with an error status if the
+// results differ from the golden file.
+func (ss *Sim) RunGolden() {
+	if err := ss.CheckGolden(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// SimLoops returns the looper stacks, for the simapi package.
+func (ss *Sim) SimLoops() *looper.Stacks {
+	return ss.Loops
+}
+
+// SimLogs returns the logs, for the simapi package.
+func (ss *Sim) SimLogs() *elog.Logs {
+	return &ss.Logs
+}
+
+// RunServe configures the model without the GUI, and serves the simapi
+// HTTP API for driving it at the ServeAddr, until it is stopped with /quit.
+func (ss *Sim) RunServe() {
+	ss.noGUI = true
+	ss.ConfigAll()
+	ss.Init()
+	fmt.Printf("Serving the sim API at: http://%s\n", ss.Config.ServeAddr)
+	if err := simapi.Serve(ss.Config.ServeAddr, ss); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
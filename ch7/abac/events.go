@@ -0,0 +1,79 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+
+	"cogentcore.org/core/math32/minmax"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// AddEvent records an event with the given label at the given training
+// epoch in the Events table, e.g., the switch from the AB to the AC list.
+// Events are shown as labeled markers in the epoch plots, to line
+// up changes in performance with the manipulations that caused them.
+// The Events table is reset at the start of each run.
+func (ss *Sim) AddEvent(epoch int, label string) {
+	dt := ss.Logs.MiscTable("Events")
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetFloat("Epoch", row, float64(epoch))
+	dt.SetString("Label", row, label)
+}
+
+// EventLabel returns the label of the event at the given training epoch,
+// or "" if there is none.
+func (ss *Sim) EventLabel(epoch int) string {
+	dt := ss.Logs.MiscTable("Events")
+	for ri := range dt.Rows {
+		if int(dt.Float("Epoch", ri)) == epoch {
+			return dt.StringValue("Label", ri)
+		}
+	}
+	return ""
+}
+
+// ConfigEventItems adds the Event and EventLabel items to the epoch logs,
+// which show the events as markers in their plots: Event is 1 at epochs
+// with an event, and 0 otherwise, and EventLabel has the label of the event.
+// It must be called before Logs.CreateTables.
+func (ss *Sim) ConfigEventItems() {
+	ss.Logs.AddItem(&elog.Item{
+		Name:   "Event",
+		Type:   reflect.Float64,
+		Plot:   true,
+		FixMin: true,
+		FixMax: true,
+		Range:  minmax.F32{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ev := 0.0
+				if ss.EventLabel(ctx.Stats.Int("Epoch")) != "" {
+					ev = 1
+				}
+				ctx.SetFloat64(ev)
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "EventLabel",
+		Type: reflect.String,
+		Plot: true,
+		Write: elog.WriteMap{
+			etime.Scope(etime.AllModes, etime.Epoch): func(ctx *elog.Context) {
+				ctx.SetString(ss.EventLabel(ctx.Stats.Int("Epoch")))
+			}}})
+}
+
+// ConfigEvents configures the Events table, which is made by
+// Logs.CreateTables.
+func (ss *Sim) ConfigEvents(dt *table.Table) {
+	dt.SetMetaData("name", "Events")
+	dt.SetMetaData("desc", "events in the current run, by training epoch")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Epoch")
+	dt.AddStringColumn("Label")
+}
@@ -6,6 +6,6 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}}})
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}, {Name: "Golden", Doc: "run headless for the golden-file regression test (see the golden package),\ncomparing the Train Epoch log against testdata/golden_epc.tsv"}, {Name: "GoldenUpdate", Doc: "regenerate the golden file from the current results, instead of comparing"}, {Name: "GoldenEpochs", Doc: "number of epochs to train in one run for the golden-file regression test"}, {Name: "Serve", Doc: "serve an HTTP API for driving the model without the GUI (see the simapi\npackage), e.g., from a web front-end, instead of opening the GUI"}, {Name: "ServeAddr", Doc: "address for the Serve API.  Only reachable from the same\nmachine by default -- use e.g., :8766 to allow remote connections."}}})
 
 var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "HiddenInhibGi", Doc: "HiddenInhibGi is the hidden layer inhibition; increase to make sparser."}, {Name: "WtInitVar", Doc: "WtInitVar is the random initial weight variance; increase to make more random."}, {Name: "FmContext", Doc: "FmContext is the relative WtScale.Rel from Context layer."}, {Name: "XCalLLrn", Doc: "XCalLLrn is the amount of Hebbian BCM learning based on AvgL long-term average\nactivity. Increase to increase amount of hebbian."}, {Name: "Lrate", Doc: "Lrate is the learning rate"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "ABPatterns", Doc: "AB training patterns"}, {Name: "ACPatterns", Doc: "AC training patterns"}, {Name: "ABACPatterns", Doc: "ABAC testing patterns"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
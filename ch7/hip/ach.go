@@ -0,0 +1,113 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+
+	"cogentcore.org/core/math32"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// AChParams are the parameters for an acetylcholine (ACh)-like
+// neuromodulatory signal that scales the learning rate of all paths
+// according to the phase of memory processing: high ACh during encoding
+// (training trials) and low ACh during retrieval (testing trials),
+// as in the theory of Hasselmo (1999), which complements the theta phase
+// dynamics that alternate between encoding and retrieval within each trial.
+type AChParams struct {
+
+	// use phase-dependent modulation of the learning rate -- otherwise
+	// the standard learning rate is used for training, and there is
+	// no learning during testing
+	On bool
+
+	// learning rate multiplier during encoding (training trials)
+	Encode float32 `default:"1" min:"0"`
+
+	// learning rate multiplier during retrieval (testing trials) --
+	// if > 0, the network also learns on testing trials, so that
+	// retrieval can modify the stored memories and cause interference
+	Retrieve float32 `default:"0" min:"0"`
+}
+
+func (ac *AChParams) Defaults() {
+	ac.Encode = 1
+	ac.Retrieve = 0
+}
+
+// Lrate returns the learning rate multiplier for given mode.
+func (ac *AChParams) Lrate(mode etime.Modes) float32 {
+	if !ac.On {
+		return 1
+	}
+	if mode == etime.Train {
+		return ac.Encode
+	}
+	return ac.Retrieve
+}
+
+// ApplyACh sets the learning rate of all paths according to the
+// ACh modulation for given mode, called at the start of each trial.
+func (ss *Sim) ApplyACh(mode etime.Modes) {
+	ss.Net.LrateMult(ss.ACh.Lrate(mode))
+}
+
+// RetrievalLearn updates the weights at the end of a testing trial,
+// if the ACh modulation allows learning during retrieval.
+func (ss *Sim) RetrievalLearn() {
+	if !ss.ACh.On || ss.ACh.Retrieve <= 0 {
+		return
+	}
+	ss.Net.DWt()
+	ss.Net.WtFromDWt()
+}
+
+// RecordCA3 saves the CA3 minus phase activity pattern for the current
+// testing trial, for computing the CA3Overlap.
+func (ss *Sim) RecordCA3() {
+	if ss.ca3Pats == nil {
+		ss.ca3Pats = make(map[string][]float32)
+	}
+	ca3 := ss.Net.LayerByName("CA3")
+	pat := make([]float32, len(ca3.Neurons))
+	for ni := range ca3.Neurons {
+		pat[ni] = ca3.Neurons[ni].ActM
+	}
+	ss.ca3Pats[ss.Stats.String("TrialName")] = pat
+}
+
+// CA3Overlap computes the mean overlap (cosine) between the CA3 patterns
+// for each AB testing item and the corresponding AC item, recorded over
+// the last testing epoch.  These items share the same A stimulus, so
+// lower overlap reflects greater pattern separation, and thus less
+// interference between the two lists.
+func (ss *Sim) CA3Overlap() float64 {
+	sum := float32(0)
+	n := 0
+	for nm, ab := range ss.ca3Pats {
+		if !strings.HasPrefix(nm, "ab") {
+			continue
+		}
+		ac, ok := ss.ca3Pats["ac"+strings.TrimPrefix(nm, "ab")]
+		if !ok {
+			continue
+		}
+		var dot, ssab, ssac float32
+		for i := range ab {
+			dot += ab[i] * ac[i]
+			ssab += ab[i] * ab[i]
+			ssac += ac[i] * ac[i]
+		}
+		if ssab > 0 && ssac > 0 {
+			sum += dot / math32.Sqrt(ssab*ssac)
+		}
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(sum / float32(n))
+}
@@ -198,6 +198,9 @@ type Sim struct {
 	// all parameter management
 	Params emer.NetParams `display:"add-fields"`
 
+	// acetylcholine-like modulation of the learning rate for encoding vs. retrieval
+	ACh AChParams `display:"add-fields"`
+
 	// contains looper control loops for running sim
 	Loops *looper.Stacks `new-window:"+" display:"no-inline"`
 
@@ -251,6 +254,9 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// CA3 activity patterns for each testing item, for CA3Overlap
+	ca3Pats map[string][]float32
 }
 
 // New creates new blank elements and initializes defaults
@@ -275,6 +281,7 @@ func (ss *Sim) New() {
 	ss.TrainAll = &table.Table{}
 	ss.TestAll = &table.Table{}
 	ss.PretrainMode = false
+	ss.ACh.Defaults()
 
 	ss.RandSeeds.Init(100) // max 100 runs
 	ss.InitRandSeed(0)
@@ -405,6 +412,7 @@ func (ss *Sim) Init() {
 
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -448,6 +456,10 @@ func (ss *Sim) ConfigLoops() {
 		})
 	}
 
+	testTrial := ls.Loop(etime.Test, etime.Trial)
+	testTrial.OnEnd.Add("RecordCA3", ss.RecordCA3)
+	testTrial.OnEnd.Add("RetrievalLearn", ss.RetrievalLearn)
+
 	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
 	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunDone", func() {
@@ -524,6 +536,7 @@ func (ss *Sim) ApplyInputs() {
 		ecout.Type = leabra.CompareLayer // don't clamp
 	}
 	ecout.UpdateExtFlags() // call this after updating type
+	ss.ApplyACh(ctx.Mode)
 	net.InitExt()
 	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer)
 	ev.Step()
@@ -670,6 +683,7 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("ACMem", 0.0)
 	ss.Stats.SetFloat("LureMem", 0.0)
 	ss.Stats.SetFloat("Mem", 0.0)
+	ss.Stats.SetFloat("CA3Overlap", 0.0)
 	ss.Stats.SetInt("FirstPerfect", -1) // first epoch at when AB Mem is perfect
 
 	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
@@ -821,7 +835,7 @@ func (ss *Sim) RunStats() {
 // 		Logging
 
 func (ss *Sim) AddLogItems() {
-	itemNames := []string{"TrgOnWasOffAll", "TrgOnWasOffCmp", "TrgOffWasOn", "Mem", "ABMem", "ACMem", "LureMem"}
+	itemNames := []string{"TrgOnWasOffAll", "TrgOnWasOffCmp", "TrgOffWasOn", "Mem", "ABMem", "ACMem", "LureMem", "CA3Overlap"}
 	for _, st := range itemNames {
 		stnm := st
 		tonm := "Tst" + st
@@ -855,6 +869,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddStatAggItem("LureMem", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("Mem", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatIntNoAggItem(etime.Train, etime.Run, "FirstPerfect")
+	ss.Logs.AddStatFloatNoAggItem(etime.Test, etime.Epoch, "CA3Overlap")
 
 	// ss.Logs.AddCopyFromFloatItems(etime.Train, etime.Epoch, etime.Test, etime.Epoch, "Tst", "PhaseDiff", "UnitErr", "PctCor", "PctErr", "TrgOnWasOffAll", "TrgOnWasOffCmp", "TrgOffWasOn", "Mem")
 	ss.AddLogItems()
@@ -885,6 +900,7 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.SetMeta(etime.Train, etime.Run, "TstABMem:On", "+")
 	ss.Logs.SetMeta(etime.Train, etime.Run, "TstACMem:On", "+")
 	ss.Logs.SetMeta(etime.Train, etime.Run, "TstLureMem:On", "+")
+	ss.Logs.SetMeta(etime.Train, etime.Run, "TstCA3Overlap:On", "+")
 	ss.Logs.SetMeta(etime.Train, etime.Run, "Type", "Bar")
 	ss.Logs.SetMeta(etime.Train, etime.Epoch, "ABMem:On", "-")
 	ss.Logs.SetMeta(etime.Train, etime.Epoch, "ACMem:On", "-")
@@ -921,6 +937,8 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 		ss.StatCounters()
 		ss.Logs.LogRow(mode, time, row)
 		return // don't do reg below
+	case mode == etime.Test && time == etime.Epoch:
+		ss.Stats.SetFloat("CA3Overlap", ss.CA3Overlap())
 	}
 
 	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
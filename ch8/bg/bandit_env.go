@@ -6,7 +6,6 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
 
 	"cogentcore.org/lab/base/randx"
 	"github.com/emer/emergent/v2/env"
@@ -43,6 +42,10 @@ type BanditEnv struct {
 
 	// single reward value
 	Reward tensor.Float64
+
+	// random number generator for the env -- all random draws of options
+	// and rewards use this, so they are reproducible for a given seed
+	Rand randx.SysRand `display:"-"`
 }
 
 func (ev *BanditEnv) Label() string { return ev.Name }
@@ -80,7 +83,7 @@ func (ev *BanditEnv) Init(run int) {
 
 // RandomOpt selects option at random -- sets Option.Cur and returns it
 func (ev *BanditEnv) RandomOpt() int {
-	op := rand.Intn(ev.N)
+	op := ev.Rand.Intn(ev.N)
 	ev.Option.Set(op)
 	return op
 }
@@ -94,7 +97,7 @@ func (ev *BanditEnv) SetInput() {
 // SetReward sets reward for current option according to probability -- returns true if rewarded
 func (ev *BanditEnv) SetReward() bool {
 	p := ev.P[ev.Option.Cur]
-	rw := randx.BoolP(float64(p))
+	rw := randx.BoolP(float64(p), &ev.Rand)
 	if rw {
 		ev.Reward.Values[0] = float64(ev.RewVal)
 	} else {
@@ -14,7 +14,11 @@ package main
 //go:generate core generate -add-types
 
 import (
+	"context"
 	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
@@ -22,6 +26,8 @@ import (
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/golden"
+	"github.com/compcogneuro/sims/v2/headless"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -33,6 +39,7 @@ import (
 	"github.com/emer/emergent/v2/netview"
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/etensor/plot/plotcore"
 	"github.com/emer/etensor/tensor"
 	"github.com/emer/etensor/tensor/stats/metric"
 	"github.com/emer/etensor/tensor/stats/norm"
@@ -46,8 +53,12 @@ var readme embed.FS
 func main() {
 	sim := &Sim{}
 	sim.New()
-	sim.ConfigAll()
-	sim.RunGUI()
+	if sim.Config.Golden {
+		sim.RunGolden()
+	} else {
+		sim.ConfigAll()
+		sim.RunGUI()
+	}
 }
 
 // ParamSets is the default set of parameters.
@@ -139,6 +150,16 @@ type Config struct {
 
 	// total number of trials per epoch
 	NTrials int `default:"100"`
+
+	// run headless for the golden-file regression test (see the golden package),
+	// comparing the Train Epoch log against testdata/golden_epc.tsv
+	Golden bool
+
+	// regenerate the golden file from the current results, instead of comparing
+	GoldenUpdate bool
+
+	// number of epochs to train in one run for the golden-file regression test
+	GoldenEpochs int `default:"5"`
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -153,6 +174,10 @@ type Sim struct {
 	// strength of dopamine dips: 1 default -- reduce to siulate D2 agonists
 	DipDaGain float32 `min:"0" step:"0.1"`
 
+	// probability of reward (dopamine burst vs. dip) for each of the 6 options (A-F),
+	// applied at Init -- use intermediate values for all options to increase the uncertainty
+	RewProbs []float32 `display:"inline"`
+
 	// Config contains misc configuration parameters for running the sim
 	Config Config `new-window:"+" display:"no-inline"`
 
@@ -185,6 +210,9 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// running without the GUI, so the GUI updates are not configured
+	noGUI bool
 }
 
 // New creates new blank elements and initializes defaults
@@ -202,6 +230,7 @@ func (ss *Sim) New() {
 func (ss *Sim) Defaults() {
 	ss.BurstDaGain = 1
 	ss.DipDaGain = 1
+	ss.RewProbs = []float32{1, .8, .6, .4, .2, 0}
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -228,7 +257,7 @@ func (ss *Sim) ConfigEnv() {
 	trn.Name = etime.Train.String()
 	trn.SetN(6)
 	trn.RndOpt = true
-	trn.P = []float32{1, .8, .6, .4, .2, 0}
+	copy(trn.P, ss.RewProbs)
 	trn.RewVal = 1
 	trn.NoRewVal = -1
 
@@ -300,6 +329,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -348,9 +378,11 @@ func (ss *Sim) ConfigLoops() {
 	////////////////////////////////////////////
 	// GUI
 
-	leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
-	leabra.LooperUpdatePlots(ls, &ss.GUI)
-	ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+	if !ss.noGUI {
+		leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
+		leabra.LooperUpdatePlots(ls, &ss.GUI)
+		ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+	}
 
 	ss.Loops = ls
 }
@@ -380,8 +412,11 @@ func (ss *Sim) ApplyInputs() {
 // for the new run value
 func (ss *Sim) NewRun() {
 	ctx := &ss.Context
-	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
-	ss.Envs.ByMode(etime.Train).Init(0)
+	run := ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur
+	ss.InitRandSeed(run)
+	trn := ss.Envs.ByMode(etime.Train).(*BanditEnv)
+	trn.Rand.NewRand(ss.RandSeeds[run] + 1) // separate stream from the network
+	trn.Init(0)
 	ctx.Reset()
 	ctx.Mode = etime.Train
 	ss.Net.InitWeights()
@@ -399,6 +434,10 @@ func (ss *Sim) NewRun() {
 func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("UniqPats", 0.0)
 	ss.Stats.SetString("TrialName", "")
+	ss.Stats.SetInt("Option", 0)
+	ss.Stats.SetFloat("RewProb", 0.0)
+	ss.Stats.SetFloat("Go", 0.0)
+	ss.Stats.SetFloat("DA", 0.0)
 	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
 
@@ -429,7 +468,18 @@ func (ss *Sim) NetViewCounters(tm etime.Times) {
 
 // TrialStats computes the trial-level statistics.
 // Aggregation is done directly from log data.
+// Go is the PFCoutD activity for the current option, which reflects
+// whether the BG chose to gate (Go) or not (NoGo) that option,
+// and DA is the dopamine signal on the trial.
 func (ss *Sim) TrialStats() {
+	ev := ss.Envs.ByMode(ss.Context.Mode).(*BanditEnv)
+	opt := ev.Option.Cur
+	ss.Stats.SetInt("Option", opt)
+	ss.Stats.SetFloat("RewProb", float64(ev.P[opt]))
+	pfc := ss.Net.LayerByName("PFCoutD")
+	ss.Stats.SetFloat("Go", float64(pfc.Neurons[opt].Act))
+	snc := ss.Net.LayerByName("SNc")
+	ss.Stats.SetFloat("DA", float64(snc.Neurons[0].Act))
 }
 
 // UniquePatStat analyzes the hidden activity patterns for the single-line test inputs
@@ -485,6 +535,51 @@ func (ss *Sim) MatrixFromInput() {
 	gv.Update()
 }
 
+// ChoiceStats computes the mean Go and DA for each option over the
+// trials of the current epoch, in the Choice table, which shows how
+// choice behavior and dopamine track the reward probability of each option.
+func (ss *Sim) ChoiceStats() {
+	dt := ss.Logs.MiscTable("Choice")
+	trl := ss.Logs.Table(etime.Train, etime.Trial)
+	ev := ss.Envs.ByMode(etime.Train).(*BanditEnv)
+	dt.SetNumRows(ev.N)
+	for opt := range ev.N {
+		var gsum, dsum float64
+		n := 0
+		for ri := range trl.Rows {
+			if int(trl.Float("Option", ri)) != opt {
+				continue
+			}
+			gsum += trl.Float("Go", ri)
+			dsum += trl.Float("DA", ri)
+			n++
+		}
+		if n > 0 {
+			gsum /= float64(n)
+			dsum /= float64(n)
+		}
+		dt.SetString("Option", opt, string([]byte{byte('A' + opt)}))
+		dt.SetFloat("RewProb", opt, float64(ev.P[opt]))
+		dt.SetFloat("Go", opt, gsum)
+		dt.SetFloat("DA", opt, dsum)
+	}
+	if plt := ss.GUI.PlotByName("Choice"); plt != nil {
+		plt.GoUpdatePlot()
+	}
+}
+
+// ConfigChoice configures the table of results from ChoiceStats,
+// with the mean Go and DA for each option.
+func (ss *Sim) ConfigChoice(dt *table.Table) {
+	dt.SetMetaData("name", "Choice")
+	dt.SetMetaData("desc", "mean Go and DA for each option as a function of its reward probability")
+	dt.SetMetaData("read-only", "true")
+	dt.AddStringColumn("Option")
+	dt.AddFloat64Column("RewProb")
+	dt.AddFloat64Column("Go")
+	dt.AddFloat64Column("DA")
+}
+
 //////////////////////////////////////////////////////////////////////////////
 // 		Logging
 
@@ -497,13 +592,19 @@ func (ss *Sim) ConfigLogs() {
 
 	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
 
+	ss.Logs.AddStatIntNoAggItem(etime.Train, etime.Trial, "Option")
+	ss.Logs.AddStatFloatNoAggItem(etime.Train, etime.Trial, "RewProb")
+	ss.Logs.AddStatAggItem("Go", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("DA", etime.Run, etime.Epoch, etime.Trial)
+
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
 	// don't plot certain combinations we don't use
 	ss.Logs.NoPlot(etime.Train, etime.Cycle)
-	ss.Logs.NoPlot(etime.Train, etime.Epoch)
 	ss.Logs.NoPlot(etime.Train, etime.Run)
 	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+
+	ss.ConfigChoice(ss.Logs.MiscTable("Choice"))
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -526,6 +627,7 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 		ss.StatCounters()
 	case time == etime.Epoch:
 		ss.MatrixFromInput()
+		ss.ChoiceStats()
 	}
 
 	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
@@ -553,6 +655,15 @@ func (ss *Sim) ConfigGUI() {
 
 	ss.GUI.AddTableView(&ss.Logs, etime.Train, etime.Trial)
 
+	chnm := "Choice"
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey(chnm), chnm+" Plot")
+	plt.Options.Title = "Choice and Dopamine by Reward Probability"
+	plt.Options.XAxis = "RewProb"
+	plt.Options.Points = true
+	plt.SetTable(ss.Logs.MiscTable(chnm))
+	plt.SetColumnOptions("Go", plotcore.On, plotcore.FixMin, -1, plotcore.FixMax, 1)
+	plt.SetColumnOptions("DA", plotcore.On, plotcore.FixMin, -1, plotcore.FixMax, 1)
+
 	wgv := ss.GUI.AddGridTab("Weights")
 	wg := ss.Stats.F32Tensor("MatrixFromInput")
 	wg.SetShape([]int{6, 1, 1, 6})
@@ -599,3 +710,42 @@ func (ss *Sim) RunGUI() {
 	ss.ConfigGUI()
 	ss.GUI.Body.RunMainWindow()
 }
+
+// SimConfig returns a pointer to the Config, for running the
+// model with the headless package.
+func (ss *Sim) SimConfig() *Config {
+	return &ss.Config
+}
+
+// RunHeadless configures and runs the model without the GUI, according
+// to the current Config, and returns a copy of the Train Epoch log, which
+// has the stats for each epoch of the last run.  No files are written.
+// The run stops early when the context is done.
+// Use headless.Run to run the model with a given Config.
+func (ss *Sim) RunHeadless(ctx context.Context) *table.Table {
+	ss.noGUI = true
+	ss.ConfigAll()
+	headless.AddCancel(ctx, ss.Loops, etime.Train)
+	ss.Init()
+	ss.Loops.Run(etime.Train)
+	return ss.Logs.Table(etime.Train, etime.Epoch).Clone()
+}
+
+// CheckGolden runs the model headless for one run of GoldenEpochs epochs,
+// using the default random seed, and checks the resulting Train Epoch log
+// against the golden file, or regenerates it if GoldenUpdate is set.
+func (ss *Sim) CheckGolden() error {
+	ss.Config.NRuns = 1
+	ss.Config.NEpochs = ss.Config.GoldenEpochs
+	dt := ss.RunHeadless(context.Background())
+	return golden.Check(dt, filepath.Join("testdata", "golden_epc.tsv"), ss.Config.GoldenUpdate, "PerTrlMSec")
+}
+
+// RunGolden runs CheckGolden, and exits with an error status if the
+// results differ from the golden file.
+func (ss *Sim) RunGolden() {
+	if err := ss.CheckGolden(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
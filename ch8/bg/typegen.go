@@ -6,8 +6,8 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "main.BanditEnv", IDName: "bandit-env", Doc: "BanditEnv simulates an n-armed bandit, where each of n inputs is associated with\na specific probability of reward.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment (Train or Test)"}, {Name: "N", Doc: "number of different inputs"}, {Name: "P", Doc: "probabilities for each option"}, {Name: "RewVal", Doc: "value for reward"}, {Name: "NoRewVal", Doc: "value for non-reward"}, {Name: "Option", Doc: "bandit option current / prev"}, {Name: "RndOpt", Doc: "if true, select option at random each Step -- otherwise must be set externally (e.g., by model)"}, {Name: "Input", Doc: "one-hot input representation of current option"}, {Name: "Reward", Doc: "single reward value"}}})
+var _ = types.AddType(&types.Type{Name: "main.BanditEnv", IDName: "bandit-env", Doc: "BanditEnv simulates an n-armed bandit, where each of n inputs is associated with\na specific probability of reward.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment (Train or Test)"}, {Name: "N", Doc: "number of different inputs"}, {Name: "P", Doc: "probabilities for each option"}, {Name: "RewVal", Doc: "value for reward"}, {Name: "NoRewVal", Doc: "value for non-reward"}, {Name: "Option", Doc: "bandit option current / prev"}, {Name: "RndOpt", Doc: "if true, select option at random each Step -- otherwise must be set externally (e.g., by model)"}, {Name: "Input", Doc: "one-hot input representation of current option"}, {Name: "Reward", Doc: "single reward value"}, {Name: "Rand", Doc: "random number generator for the env -- all random draws of options\nand rewards use this, so they are reproducible for a given seed"}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NTrials", Doc: "total number of trials per epoch"}}})
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NTrials", Doc: "total number of trials per epoch"}, {Name: "Golden", Doc: "run headless for the golden-file regression test (see the golden package),\ncomparing the Train Epoch log against testdata/golden_epc.tsv"}, {Name: "GoldenUpdate", Doc: "regenerate the golden file from the current results, instead of comparing"}, {Name: "GoldenEpochs", Doc: "number of epochs to train in one run for the golden-file regression test"}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "BurstDaGain", Doc: "strength of dopamine bursts: 1 default -- reduce for PD OFF, increase for PD ON"}, {Name: "DipDaGain", Doc: "strength of dopamine dips: 1 default -- reduce to siulate D2 agonists"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "BurstDaGain", Doc: "strength of dopamine bursts: 1 default -- reduce for PD OFF, increase for PD ON"}, {Name: "DipDaGain", Doc: "strength of dopamine dips: 1 default -- reduce to siulate D2 agonists"}, {Name: "RewProbs", Doc: "probability of reward (dopamine burst vs. dip) for each of the 6 options (A-F),\napplied at Init -- use intermediate values for all options to increase the uncertainty"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
@@ -15,7 +15,10 @@ package main
 import (
 	"embed"
 	"fmt"
+	"math"
+	"strings"
 
+	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
@@ -23,6 +26,7 @@ import (
 	"cogentcore.org/core/styles"
 	"cogentcore.org/core/tree"
 	"cogentcore.org/lab/base/randx"
+	"github.com/compcogneuro/sims/v2/findlayer"
 	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
@@ -209,6 +213,11 @@ type Config struct {
 	// total number of trials per epochs per run
 	NTrials int `default:"100"`
 
+	// number of independent memory slots, each with its own PFC maintenance
+	// and output stripe, and its own store and recall actions.
+	// This determines the network structure, so it must be set at startup.
+	NSlots int `default:"1" min:"1" max:"4"`
+
 	// stop run after this number of perfect, zero-error epochs.
 	NZero int `default:"5"`
 
@@ -306,12 +315,14 @@ func (ss *Sim) ConfigEnv() {
 
 	// note: names must be standard here!
 	trn.Name = etime.Train.String()
+	trn.SetNSlots(ss.Config.NSlots)
 	trn.SetNStim(4)
 	trn.RewVal = 1
 	trn.NoRewVal = 0
 	trn.Trial.Max = ss.Config.NTrials
 
 	tst.Name = etime.Test.String()
+	tst.SetNSlots(ss.Config.NSlots)
 	tst.SetNStim(4)
 	tst.RewVal = 1
 	tst.NoRewVal = 0
@@ -330,13 +341,14 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 	rew, rp, da := net.AddRWLayers("", 2)
 	da.Name = "SNc"
 
+	nslots := max(ss.Config.NSlots, 1)
 	inp := net.AddLayer2D("Input", 1, 4, leabra.InputLayer)
-	ctrl := net.AddLayer2D("CtrlInput", 1, 3, leabra.InputLayer)
+	ctrl := net.AddLayer2D("CtrlInput", 1, 2*nslots+1, leabra.InputLayer)
 	out := net.AddLayer2D("Output", 1, 4, leabra.TargetLayer)
 	hid := net.AddLayer2D("Hidden", 7, 7, leabra.SuperLayer)
 
 	// args: nY, nMaint, nOut, nNeurBgY, nNeurBgX, nNeurPfcY, nNeurPfcX
-	mtxGo, mtxNoGo, gpe, gpi, cin, pfcMnt, pfcMntD, pfcOut, pfcOutD := net.AddPBWM("", 1, 1, 1, 1, 3, 1, 4)
+	mtxGo, mtxNoGo, gpe, gpi, cin, pfcMnt, pfcMntD, pfcOut, pfcOutD := net.AddPBWM("", 1, nslots, nslots, 1, 3, 1, 4)
 	_ = gpe
 	_ = gpi
 	_ = pfcMnt
@@ -363,8 +375,14 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 	net.ConnectLayers(pfcMntD, rp, full, leabra.RWPath)
 	net.ConnectLayers(pfcOutD, rp, full, leabra.RWPath)
 
-	net.ConnectLayers(ctrl, mtxGo, fmin, leabra.MatrixPath)
-	net.ConnectLayers(ctrl, mtxNoGo, fmin, leabra.MatrixPath)
+	// with multiple slots, the Matrix must learn which control inputs
+	// gate each stripe, so it gets all of them
+	var ctrl2mtx paths.Pattern = fmin
+	if nslots > 1 {
+		ctrl2mtx = full
+	}
+	net.ConnectLayers(ctrl, mtxGo, ctrl2mtx, leabra.MatrixPath)
+	net.ConnectLayers(ctrl, mtxNoGo, ctrl2mtx, leabra.MatrixPath)
 	pt := net.ConnectLayers(inp, pfcMnt, fmin, leabra.ForwardPath)
 	pt.AddClass("PFCFixed")
 
@@ -401,6 +419,13 @@ func (ss *Sim) ApplyParams() {
 	matg.Matrix.DipGain = ss.DipDaGain
 	matn.Matrix.BurstGain = ss.BurstDaGain
 	matn.Matrix.DipGain = ss.DipDaGain
+
+	// PFC OutD needs to be stronger in proportion to the number of stripes
+	for _, lnm := range []string{"Hidden", "Output"} {
+		ly := ss.Net.LayerByName(lnm)
+		pt := errors.Log1(ly.RecvPathBySendName("PFCoutD")).(*leabra.Path)
+		pt.WtScale.Abs = float32(max(ss.Config.NSlots, 1))
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -415,6 +440,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -596,6 +622,9 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("AbsDA", 0.0)
 	ss.Stats.SetFloat("RewPred", 0.0)
 	ss.Stats.SetString("TrialName", "")
+	for si := range max(ss.Config.NSlots, 1) {
+		ss.Stats.SetFloat(SlotRecallStat(si), math.NaN())
+	}
 	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
 
@@ -640,6 +669,7 @@ func (ss *Sim) TrialStats() {
 	} else {
 		ss.Stats.SetFloat("TrlErr", 0)
 	}
+	ss.SlotRecallStats(sse == 0)
 
 	snc := ss.Net.LayerByName("SNc")
 	ss.Stats.SetFloat32("DA", snc.Neurons[0].Act)
@@ -648,6 +678,30 @@ func (ss *Sim) TrialStats() {
 	ss.Stats.SetFloat32("RewPred", rp.Neurons[0].Act)
 }
 
+// SlotRecallStat returns the name of the recall accuracy stat for given slot.
+func SlotRecallStat(slot int) string {
+	return fmt.Sprintf("Slot%dRecall", slot+1)
+}
+
+// SlotRecallStats sets the recall accuracy stat for the slot that was
+// recalled on the current trial to 1 if correct and 0 if not, and to NaN
+// for all other slots and non-recall trials, so that the average over
+// trials is the proportion of correct recalls for each slot.
+func (ss *Sim) SlotRecallStats(correct bool) {
+	for si := range max(ss.Config.NSlots, 1) {
+		ss.Stats.SetFloat(SlotRecallStat(si), math.NaN())
+	}
+	ev := ss.Envs.ByMode(ss.Context.Mode).(*SIREnv)
+	if ev.Act != Recall {
+		return
+	}
+	cor := 0.0
+	if correct {
+		cor = 1
+	}
+	ss.Stats.SetFloat(SlotRecallStat(ev.Slot), cor)
+}
+
 //////////////////////////////////////////////////////////////////////
 // 		Logging
 
@@ -669,7 +723,16 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddStatAggItem("AbsDA", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("RewPred", etime.Run, etime.Epoch, etime.Trial)
 
+	slots := make([]string, max(ss.Config.NSlots, 1))
+	for si := range slots {
+		slots[si] = SlotRecallStat(si)
+		ss.Logs.AddStatAggItem(slots[si], etime.Run, etime.Epoch, etime.Trial)
+	}
+
 	ss.Logs.PlotItems("PctErr", "AbsDA", "RewPred")
+	if len(slots) > 1 {
+		ss.Logs.PlotItems(slots...)
+	}
 
 	ss.Logs.CreateTables()
 	ss.Logs.SetContext(&ss.Stats, ss.Net)
@@ -719,6 +782,19 @@ func (ss *Sim) ConfigNetView(nv *netview.NetView) {
 
 	labs := []string{"  A B C D ", " A B C D", " A B C D  ",
 		"A B C D", "A B C D ", " A B C D ", "  S I R "}
+	if nslots := max(ss.Config.NSlots, 1); nslots > 1 { // labels must be unique
+		pfc := strings.Repeat("A B C D   ", nslots)
+		labs[1], labs[2], labs[3], labs[4] = " "+pfc, " "+pfc+" ", pfc, pfc+" "
+		ctrl := " "
+		for si := range nslots {
+			ctrl += fmt.Sprintf("S%d ", si+1)
+		}
+		ctrl += "I "
+		for si := range nslots {
+			ctrl += fmt.Sprintf("R%d ", si+1)
+		}
+		labs[6] = ctrl
+	}
 	nv.ConfigLabels(labs)
 
 	lays := []string{"Input", "PFCmnt", "PFCmntD", "PFCout", "PFCoutD", "Output", "CtrlInput"}
@@ -757,8 +833,23 @@ func (ss *Sim) ConfigGUI() {
 	ss.GUI.FinalizeGUI(false)
 }
 
+// FindLayer points the Network view camera at the first layer whose name
+// matches the given name, or part of it (see findlayer.Match).
+func (ss *Sim) FindLayer(name string) { //types:add
+	names := make([]string, len(ss.Net.Layers))
+	for i, ly := range ss.Net.Layers {
+		names[i] = ly.Name
+	}
+	lnm := findlayer.Match(names, name)
+	nv := ss.GUI.ViewUpdate.View
+	if lnm == "" || nv == nil || !findlayer.Focus(nv, lnm) {
+		core.MessageSnackbar(ss.GUI.Body, "No layer matching: "+name)
+	}
+}
+
 func (ss *Sim) MakeToolbar(p *tree.Plan) {
 	ss.GUI.AddLooperCtrl(p, ss.Loops)
+	findlayer.AddField(p, ss.FindLayer)
 
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset RunLog",
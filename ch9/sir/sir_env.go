@@ -30,6 +30,10 @@ type SIREnv struct {
 	// number of different stimuli that can be maintained
 	NStim int
 
+	// number of independent memory slots that can each maintain a stimulus,
+	// with separate store and recall actions for each slot
+	NSlots int
+
 	// value for reward, based on whether model output = target
 	RewVal float32
 
@@ -42,9 +46,15 @@ type SIREnv struct {
 	// current stimulus
 	Stim int
 
-	// current stimulus being maintained
+	// current memory slot for store and recall actions
+	Slot int
+
+	// current stimulus being maintained in the current Slot
 	Maint int
 
+	// stimulus being maintained in each slot, -1 if empty
+	Maints []int
+
 	// input pattern with stim
 	Input tensor.Float64
 
@@ -63,11 +73,12 @@ type SIREnv struct {
 
 func (ev *SIREnv) Label() string { return ev.Name }
 
-// SetNStim initializes env for given number of stimuli, init states
+// SetNStim initializes env for given number of stimuli, init states.
+// Call SetNSlots first if using more than one slot.
 func (ev *SIREnv) SetNStim(n int) {
 	ev.NStim = n
 	ev.Input.SetShape([]int{n})
-	ev.CtrlInput.SetShape([]int{int(ActionsN)})
+	ev.CtrlInput.SetShape([]int{ev.NCtrl()})
 	ev.Output.SetShape([]int{n})
 	ev.Reward.SetShape([]int{1})
 	if ev.RewVal == 0 {
@@ -75,6 +86,32 @@ func (ev *SIREnv) SetNStim(n int) {
 	}
 }
 
+// SetNSlots sets the number of memory slots.
+func (ev *SIREnv) SetNSlots(n int) {
+	ev.NSlots = max(n, 1)
+}
+
+// NCtrl returns the number of CtrlInput units: a store and a recall
+// unit for each slot, and one ignore unit, in the order:
+// Store 1..NSlots, Ignore, Recall 1..NSlots.
+func (ev *SIREnv) NCtrl() int {
+	return 2*max(ev.NSlots, 1) + 1
+}
+
+// CtrlIndex returns the index of the CtrlInput unit for the
+// current action and slot.
+func (ev *SIREnv) CtrlIndex() int {
+	nslots := max(ev.NSlots, 1)
+	switch ev.Act {
+	case Store:
+		return ev.Slot
+	case Ignore:
+		return nslots
+	default:
+		return nslots + 1 + ev.Slot
+	}
+}
+
 func (ev *SIREnv) State(element string) tensor.Tensor {
 	switch element {
 	case "Input":
@@ -94,22 +131,32 @@ func (ev *SIREnv) StimStr(stim int) string {
 	return string([]byte{byte('A' + stim)})
 }
 
-// String returns the current state as a string
+// String returns the current state as a string, with the slot number
+// after the action if there is more than one slot.
 func (ev *SIREnv) String() string {
-	return fmt.Sprintf("%s_%s_mnt_%s_rew_%g", ev.Act, ev.StimStr(ev.Stim), ev.StimStr(ev.Maint), ev.Reward.Values[0])
+	act := ev.Act.String()
+	if ev.NSlots > 1 && ev.Act != Ignore {
+		act += fmt.Sprintf("%d", ev.Slot+1)
+	}
+	return fmt.Sprintf("%s_%s_mnt_%s_rew_%g", act, ev.StimStr(ev.Stim), ev.StimStr(ev.Maint), ev.Reward.Values[0])
 }
 
 func (ev *SIREnv) Init(run int) {
 	ev.Trial.Scale = etime.Trial
 	ev.Trial.Init()
 	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Slot = 0
 	ev.Maint = -1
+	ev.Maints = make([]int, max(ev.NSlots, 1))
+	for si := range ev.Maints {
+		ev.Maints[si] = -1
+	}
 }
 
 // SetState sets the input, output states
 func (ev *SIREnv) SetState() {
 	ev.CtrlInput.SetZeros()
-	ev.CtrlInput.Values[ev.Act] = 1
+	ev.CtrlInput.Values[ev.CtrlIndex()] = 1
 	ev.Input.SetZeros()
 	if ev.Act != Recall {
 		ev.Input.Values[ev.Stim] = 1
@@ -130,14 +177,23 @@ func (ev *SIREnv) SetReward(netout int) bool {
 	return rw
 }
 
-// Step the SIR task
+// Step the SIR task.  Store uses a random empty slot, and Recall
+// a random slot that is maintaining a stimulus.
 func (ev *SIREnv) StepSIR() {
+	var empty, full []int
+	for si, mnt := range ev.Maints {
+		if mnt < 0 {
+			empty = append(empty, si)
+		} else {
+			full = append(full, si)
+		}
+	}
 	for {
 		ev.Act = Actions(rand.Intn(int(ActionsN)))
-		if ev.Act == Store && ev.Maint >= 0 { // already full
+		if ev.Act == Store && len(empty) == 0 { // already full
 			continue
 		}
-		if ev.Act == Recall && ev.Maint < 0 { // nothign
+		if ev.Act == Recall && len(full) == 0 { // nothign
 			continue
 		}
 		break
@@ -145,15 +201,27 @@ func (ev *SIREnv) StepSIR() {
 	ev.Stim = rand.Intn(ev.NStim)
 	switch ev.Act {
 	case Store:
-		ev.Maint = ev.Stim
+		ev.Slot = randSlot(empty)
+		ev.Maints[ev.Slot] = ev.Stim
 	case Ignore:
 	case Recall:
-		ev.Stim = ev.Maint
-		ev.Maint = -1
+		ev.Slot = randSlot(full)
+		ev.Stim = ev.Maints[ev.Slot]
+		ev.Maints[ev.Slot] = -1
 	}
+	ev.Maint = ev.Maints[ev.Slot]
 	ev.SetState()
 }
 
+// randSlot returns a random one of given slots,
+// without using the random number generator if there is only one.
+func randSlot(slots []int) int {
+	if len(slots) == 1 {
+		return slots[0]
+	}
+	return slots[rand.Intn(len(slots))]
+}
+
 func (ev *SIREnv) Step() bool {
 	ev.StepSIR()
 	ev.Trial.Incr()
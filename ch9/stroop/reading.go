@@ -0,0 +1,79 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// ReadingDeficitTest tests the trained network with the word reading
+// pathway strength (FromWords) reduced in the given number of equal steps
+// from 1 down to minStrength, and records the RT for each test condition
+// in the ReadingDeficit plot.  A weaker reading pathway should reduce the
+// interference from the word in the conflict color naming condition,
+// as found in people who read less fluently.  FromWords is restored
+// at the end.
+func (ss *Sim) ReadingDeficitTest(minStrength float32, levels int) { //types:add
+	if ss.GUI.Body == nil {
+		ss.readingDeficitTest(minStrength, levels)
+		return
+	}
+	if ss.GUI.IsRunning {
+		return
+	}
+	ss.GUI.IsRunning = true
+	ss.GUI.UpdateWindow()
+	go func() {
+		ss.readingDeficitTest(minStrength, levels)
+		ss.GUI.Stopped()
+	}()
+}
+
+func (ss *Sim) readingDeficitTest(minStrength float32, levels int) {
+	levels = max(levels, 2)
+	ss.GUI.StopNow = false
+	dt := ss.Logs.MiscTable("ReadingDeficit")
+	dt.SetNumRows(0)
+	fw := ss.FromWords
+	trl := ss.Logs.Table(etime.Test, etime.Trial)
+	for li := range levels {
+		if ss.GUI.StopNow {
+			break
+		}
+		ss.FromWords = 1 - (1-minStrength)*float32(li)/float32(levels-1)
+		ss.TestAll()
+		for ri := range trl.Rows {
+			row := dt.Rows
+			dt.SetNumRows(row + 1)
+			dt.SetFloat("FromWords", row, float64(ss.FromWords))
+			dt.SetString("Condition", row, trl.StringValue("TrialName", ri))
+			dt.SetFloat("RT", row, trl.Float("RT", ri))
+			if ss.GUI.Body == nil {
+				fmt.Printf("FromWords: %g\t%s\tRT: %g\n", ss.FromWords, trl.StringValue("TrialName", ri), trl.Float("RT", ri))
+			}
+		}
+		if plt := ss.GUI.PlotByName("ReadingDeficit"); plt != nil {
+			plt.GoUpdatePlot()
+		}
+	}
+	ss.FromWords = fw
+	ss.ApplyParams()
+}
+
+// ConfigReadingDeficit configures the table of results from
+// ReadingDeficitTest, with the RT for each test condition at
+// each strength of the word reading pathway.
+func (ss *Sim) ConfigReadingDeficit(dt *table.Table) {
+	dt.SetMetaData("name", "ReadingDeficit")
+	dt.SetMetaData("desc", "RT for each condition as a function of the word reading pathway strength")
+	dt.SetMetaData("read-only", "true")
+	dt.SetMetaData("LegendCol", "Condition")
+	dt.AddFloat64Column("FromWords")
+	dt.AddStringColumn("Condition")
+	dt.AddFloat64Column("RT")
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/emer/emergent/v2/netview"
 	"github.com/emer/emergent/v2/params"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/etensor/plot/plotcore"
 	"github.com/emer/etensor/tensor/table"
 	"github.com/emer/leabra/v2/leabra"
 )
@@ -107,6 +108,10 @@ var ParamSets = params.Sets{
 				"Path.Learn.XCal.SetLLrn": "true",
 				"Path.Learn.XCal.LLrn":    "0.1",
 			}},
+		{Sel: "#WordsToHidden", Desc: "word reading pathway -- set from FromWords",
+			Params: params.Params{
+				"Path.WtScale.Abs": "1",
+			}},
 		{Sel: "#HiddenToOutput", Desc: "to output",
 			Params: params.Params{
 				"Path.Learn.Lrate":        "0.08",
@@ -159,6 +164,9 @@ type Sim struct {
 	// strength of projection from PFC to Hidden -- reduce to simulate PFC damage
 	FromPFC float32 `def:"0.3" step:"0.01"`
 
+	// strength of projection from Words to Hidden -- reduce to simulate a weakness of the word reading pathway, e.g., a developmental or acquired reading deficit
+	FromWords float32 `def:"1" step:"0.1"`
+
 	// time constant for updating the network
 	DtVmTau float32 `def:"30" step:"5"`
 
@@ -222,6 +230,7 @@ func (ss *Sim) New() {
 
 func (ss *Sim) Defaults() {
 	ss.FromPFC = 0.3
+	ss.FromWords = 1
 	ss.DtVmTau = 30
 }
 
@@ -328,6 +337,8 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 func (ss *Sim) ApplyParams() {
 	spo, _ := ss.Params.Params["Testing"].SelByName("Layer")
 	spo.Params.SetByName("Layer.Act.Dt.VmTau", fmt.Sprintf("%g", ss.DtVmTau))
+	spw, _ := ss.Params.Params["Base"].SelByName("#WordsToHidden")
+	spw.Params.SetByName("Path.WtScale.Abs", fmt.Sprintf("%g", ss.FromWords))
 	ss.Params.SetAll()
 	ss.Params.SetAllSheet("Training")
 
@@ -358,6 +369,7 @@ func (ss *Sim) Init() {
 	ss.ConfigEnv() // re-config env just in case a different set of patterns was
 	ss.GUI.StopNow = false
 	ss.ApplyParams()
+	ss.Logs.ResetLog(etime.Train, etime.Run) // clear results from previous runs
 	ss.NewRun()
 	ss.ViewUpdate.RecordSyns()
 	ss.ViewUpdate.Update()
@@ -531,8 +543,10 @@ func (ss *Sim) ApplyInputs() {
 func (ss *Sim) NewRun() {
 	ctx := &ss.Context
 	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
-	ss.Envs.ByMode(etime.Train).Init(0)
-	ss.Envs.ByMode(etime.Test).Init(0)
+	// reset all envs, including the extra analysis / test envs
+	for _, ev := range ss.Envs {
+		ev.Init(0)
+	}
 	ctx.Reset()
 	ctx.Mode = etime.Train
 	ss.Net.InitWeights()
@@ -688,6 +702,8 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.SetMeta(etime.Validate, etime.Trial, "RT:FixMax", "true")
 	ss.Logs.SetMeta(etime.Validate, etime.Trial, "RT:Min", "0")
 	ss.Logs.SetMeta(etime.Validate, etime.Trial, "RT:Max", "250")
+
+	ss.ConfigReadingDeficit(ss.Logs.MiscTable("ReadingDeficit"))
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -760,6 +776,13 @@ func (ss *Sim) ConfigGUI() {
 
 	ss.GUI.AddPlots(title, &ss.Logs)
 
+	rdnm := "ReadingDeficit"
+	plt := ss.GUI.NewPlotTab(etime.ScopeKey(rdnm), rdnm+" Plot")
+	plt.Options.Title = "Condition RT vs. Word Pathway Strength"
+	plt.Options.XAxis = "FromWords"
+	plt.SetTable(ss.Logs.MiscTable(rdnm))
+	plt.SetColumnOptions("RT", plotcore.On, plotcore.FixMin, 0, plotcore.FixMax, 250)
+
 	ss.GUI.FinalizeGUI(false)
 }
 
@@ -776,6 +799,14 @@ func (ss *Sim) MakeToolbar(p *tree.Plan) {
 			ss.GUI.UpdatePlot(etime.Train, etime.Run)
 		},
 	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reading Deficit Test",
+		Icon:    icons.PlayArrow,
+		Tooltip: "Tests the trained network with the word reading pathway (FromWords) weakened in steps down to given minimum strength, and plots the RT for each condition in the ReadingDeficit plot",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			core.CallFunc(ss.GUI.Body, ss.ReadingDeficitTest)
+		},
+	})
 	////////////////////////////////////////////////
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "New Seed",
@@ -8,4 +8,4 @@ import (
 
 var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "FromPFC", Doc: "strength of projection from PFC to Hidden -- reduce to simulate PFC damage"}, {Name: "DtVmTau", Doc: "time constant for updating the network"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Train", Doc: "easy training patterns"}, {Name: "Test", Doc: "hard training patterns"}, {Name: "SOA", Doc: "impossible training patterns"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Methods: []types.Method{{Name: "ReadingDeficitTest", Doc: "ReadingDeficitTest tests the trained network with the word reading\npathway strength (FromWords) reduced in the given number of equal steps\nfrom 1 down to minStrength, and records the RT for each test condition\nin the ReadingDeficit plot.  A weaker reading pathway should reduce the\ninterference from the word in the conflict color naming condition,\nas found in people who read less fluently.  FromWords is restored\nat the end.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"minStrength", "levels"}}}, Fields: []types.Field{{Name: "FromPFC", Doc: "strength of projection from PFC to Hidden -- reduce to simulate PFC damage"}, {Name: "FromWords", Doc: "strength of projection from Words to Hidden -- reduce to simulate a weakness of the word reading pathway, e.g., a developmental or acquired reading deficit"}, {Name: "DtVmTau", Doc: "time constant for updating the network"}, {Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Train", Doc: "training patterns"}, {Name: "Test", Doc: "testing patterns"}, {Name: "SOA", Doc: "SOA testing patterns"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
@@ -0,0 +1,147 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package decode provides a linear readout of a category from the activity
+patterns of a layer, which is the standard decoding analysis used in
+neuroscience to determine whether information is linearly available in
+a neural representation, independent of how well the model's own readout
+uses it.
+
+[CrossValidate] trains a [Linear] classifier on all but one of a number
+of folds of the patterns, tests it on the remaining fold, and returns the
+proportion correct over all of the folds, so that each pattern is only
+classified by a classifier that was not trained on it.  See ch4/family_trees.
+*/
+package decode
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Epochs is the number of passes through the training patterns
+// used to train a [Linear] classifier.
+var Epochs = 200
+
+// LRate is the learning rate used to train a [Linear] classifier.
+var LRate = 0.1
+
+// Seed is the random seed used to assign patterns to folds
+// in [CrossValidate], so the results are reproducible.
+var Seed int64 = 1
+
+// Linear is a linear softmax (multinomial logistic regression) classifier,
+// with one weight per input for each class, plus a bias.
+type Linear struct {
+
+	// number of classes
+	NClass int
+
+	// number of inputs
+	NIn int
+
+	// weights, NClass x (NIn + 1), with the bias last for each class
+	Weights []float64
+}
+
+// Train initializes the classifier for the given number of classes,
+// and trains it on the given input patterns x and their classes y,
+// with Epochs of gradient descent on the cross-entropy loss,
+// presenting the patterns in order.
+func (ln *Linear) Train(x [][]float64, y []int, nclass int) {
+	ln.NClass = nclass
+	ln.NIn = 0
+	if len(x) > 0 {
+		ln.NIn = len(x[0])
+	}
+	ln.Weights = make([]float64, nclass*(ln.NIn+1))
+	p := make([]float64, nclass)
+	for range Epochs {
+		for i, xi := range x {
+			ln.probs(xi, p)
+			for c := range nclass {
+				err := p[c]
+				if c == y[i] {
+					err -= 1
+				}
+				w := ln.Weights[c*(ln.NIn+1):]
+				for j, v := range xi {
+					w[j] -= LRate * err * v
+				}
+				w[ln.NIn] -= LRate * err
+			}
+		}
+	}
+}
+
+// Predict returns the class with the highest output for the given input.
+func (ln *Linear) Predict(x []float64) int {
+	p := make([]float64, ln.NClass)
+	ln.probs(x, p)
+	mi := 0
+	for c := range p {
+		if p[c] > p[mi] {
+			mi = c
+		}
+	}
+	return mi
+}
+
+// probs sets p to the softmax probability of each class for input x.
+func (ln *Linear) probs(x []float64, p []float64) {
+	mx := math.Inf(-1)
+	for c := range ln.NClass {
+		w := ln.Weights[c*(ln.NIn+1):]
+		net := w[ln.NIn]
+		for j, v := range x {
+			net += w[j] * v
+		}
+		p[c] = net
+		mx = max(mx, net)
+	}
+	sum := 0.0
+	for c := range p {
+		p[c] = math.Exp(p[c] - mx)
+		sum += p[c]
+	}
+	for c := range p {
+		p[c] /= sum
+	}
+}
+
+// CrossValidate returns the proportion of the input patterns x that are
+// correctly classified as their classes y, which are 0 to nclass-1, by a
+// [Linear] classifier trained on the other folds, with the patterns
+// assigned at random to the given number of folds (at least 2).
+func CrossValidate(x [][]float64, y []int, nclass, folds int) float64 {
+	n := len(x)
+	folds = min(max(folds, 2), n)
+	if n < 2 {
+		return math.NaN()
+	}
+	fold := make([]int, n)
+	for i, pi := range rand.New(rand.NewSource(Seed)).Perm(n) {
+		fold[pi] = i % folds
+	}
+	ncor := 0
+	for f := range folds {
+		var trx [][]float64
+		var try []int
+		for i := range n {
+			if fold[i] != f {
+				trx = append(trx, x[i])
+				try = append(try, y[i])
+			}
+		}
+		ln := &Linear{}
+		ln.Train(trx, try, nclass)
+		for i := range n {
+			if fold[i] == f && ln.Predict(x[i]) == y[i] {
+				ncor++
+			}
+		}
+	}
+	return float64(ncor) / float64(n)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package downsample reduces the number of rows in a log table for
+// plotting, by averaging within bins of rows, which keeps plots of long
+// cycle-level recordings fast and uncluttered, without affecting the log.
+package downsample
+
+import (
+	"github.com/emer/etensor/plot/plotcore"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// Table sets dst to src downsampled to n rows, by averaging the values of
+// the rows of src within each of n equal-sized bins.  String columns use the
+// value of the first row in each bin.  dst must have the same columns as src,
+// e.g., from src.Clone(), and only its values and number of rows are set, so
+// its metadata (e.g., plot settings) are preserved.  If n <= 0 or src has
+// n or fewer rows, dst has all of the rows of src.
+func Table(dst, src *table.Table, n int) {
+	nr := src.Rows
+	if n <= 0 || nr <= n {
+		n = nr
+	}
+	dst.SetNumRows(n)
+	if nr == 0 {
+		return
+	}
+	for ci, scl := range src.Columns {
+		dcl := dst.Columns[ci]
+		ncell := scl.Len() / nr
+		for bi := range n {
+			st := (bi * nr) / n
+			ed := ((bi + 1) * nr) / n
+			for ce := range ncell {
+				if scl.IsString() {
+					dcl.SetString1D(bi*ncell+ce, scl.String1D(st*ncell+ce))
+					continue
+				}
+				sum := 0.0
+				for ri := st; ri < ed; ri++ {
+					sum += scl.Float1D(ri*ncell + ce)
+				}
+				dcl.SetFloat1D(bi*ncell+ce, sum/float64(ed-st))
+			}
+		}
+	}
+}
+
+// Plot shows a log table in a plot, downsampled with Table to a maximum
+// number of points when the table has more rows than that, e.g., for
+// a Cycle log that grows over a long run.  The zero value is ready to use.
+type Plot struct {
+
+	// downsampled copy of the log table, shown in the plot when Down is true
+	Table *table.Table
+
+	// whether the plot is currently showing the downsampled Table
+	Down bool
+}
+
+// Update updates plt to show dt downsampled to n points, if it has more
+// than n rows, and otherwise dt itself, and then updates the plot.
+// The table of the plot is only set when this changes, to keep its
+// column settings.  n <= 0 always shows dt.  Does nothing if plt is nil.
+func (dp *Plot) Update(plt *plotcore.PlotEditor, dt *table.Table, n int) {
+	if plt == nil {
+		return
+	}
+	down := n > 0 && dt.Rows > n
+	if down {
+		if dp.Table == nil {
+			dp.Table = dt.Clone()
+		}
+		Table(dp.Table, dt, n)
+	}
+	if down != dp.Down {
+		dp.Down = down
+		if down {
+			plt.SetTable(dp.Table)
+		} else {
+			plt.SetTable(dt)
+		}
+	}
+	plt.GoUpdatePlot()
+}
+
+// Reset sets plt back to showing dt, if it is showing the downsampled
+// table, e.g., after dt has been reset.
+func (dp *Plot) Reset(plt *plotcore.PlotEditor, dt *table.Table) {
+	if !dp.Down {
+		return
+	}
+	dp.Down = false
+	if plt != nil {
+		plt.SetTable(dt)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package envstate provides reusable input tensors for applying the
+patterns of an [env.FixedTable] to the layers of a network, as done in
+the ApplyInputs method of the sims.  [env.FixedTable.State] returns a
+new sub-tensor of the table on every call, which generates garbage on
+every trial of a long training run.  [Buffers.State] instead copies the
+current row into a tensor that is kept for each element (layer) name,
+and is only re-shaped when the geometry of the column changes.
+
+Because the values are copied on every call, the returned tensor always
+reflects the current contents of the table, even after the patterns
+are edited or the env is given a different table.
+*/
+package envstate
+
+import (
+	"slices"
+
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/etensor/tensor"
+)
+
+// Buffers holds a reusable tensor for each element name.
+// The zero value is ready to use.
+type Buffers struct {
+	tsrs map[string]*tensor.Float32
+}
+
+// State returns the pattern for the given element (column) name on the
+// current trial of the given env, copied into the reused tensor for that
+// name, which is only valid until the next call with the same name.
+// Returns nil if the env table has no column of that name.
+func (bf *Buffers) State(ev *env.FixedTable, element string) *tensor.Float32 {
+	col, err := ev.Table.Table.ColumnByName(element)
+	if err != nil {
+		return nil
+	}
+	shp := col.Shape().Sizes[1:]
+	tsr := bf.tsrs[element]
+	switch {
+	case tsr == nil:
+		tsr = tensor.NewFloat32(shp)
+		if bf.tsrs == nil {
+			bf.tsrs = make(map[string]*tensor.Float32)
+		}
+		bf.tsrs[element] = tsr
+	case !slices.Equal(tsr.Shape().Sizes, shp):
+		tsr.SetShape(shp)
+	}
+	st := ev.Row() * tsr.Len()
+	if f32, ok := col.(*tensor.Float32); ok {
+		copy(tsr.Values, f32.Values[st:st+tsr.Len()])
+		return tsr
+	}
+	for i := range tsr.Values {
+		tsr.Values[i] = float32(col.Float1D(st + i))
+	}
+	return tsr
+}
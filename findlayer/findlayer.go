@@ -0,0 +1,83 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package findlayer finds a layer in a large network by (partial) name, and
+points the camera of the NetView at it, so it can be seen up close.
+A sim adds a search field to its toolbar with [AddField], calling a
+FindLayer method that uses [Match] and [Focus], as in ch6/objrec.
+*/
+package findlayer
+
+import (
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tree"
+	"cogentcore.org/core/xyz"
+	"github.com/emer/emergent/v2/netview"
+)
+
+// Match returns the first of the given layer names that matches s,
+// ignoring case: an exact match if there is one, otherwise the first
+// name starting with s, otherwise the first name containing s.
+// Returns "" if none match.
+func Match(names []string, s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ""
+	}
+	for _, tst := range []func(nm string) bool{
+		func(nm string) bool { return nm == s },
+		func(nm string) bool { return strings.HasPrefix(nm, s) },
+		func(nm string) bool { return strings.Contains(nm, s) },
+	} {
+		for _, nm := range names {
+			if tst(strings.ToLower(nm)) {
+				return nm
+			}
+		}
+	}
+	return ""
+}
+
+// Focus moves the camera of the given NetView to look at the given
+// layer from just above and in front of it.  Returns false if the layer
+// is not in the view.  Use the camera reset button in the NetView
+// toolbar to go back to the view of the whole network.
+func Focus(nv *netview.NetView, lnm string) bool {
+	se := nv.SceneXYZ()
+	lays := se.ChildByName("Layers", 0)
+	if lays == nil {
+		return false
+	}
+	ln := lays.AsTree().ChildByName(lnm, 0)
+	if ln == nil {
+		return false
+	}
+	_, nb := xyz.AsNode(ln)
+	if nb == nil {
+		return false
+	}
+	ctr := nb.WorldBBox.BBox.Center()
+	se.Camera.Pose.Pos = ctr.Add(math32.Vec3(0, 0.6, 0.8))
+	se.Camera.LookAt(ctr, math32.Vec3(0, 1, 0))
+	nv.SceneWidget().NeedsRender()
+	return true
+}
+
+// AddField adds a text field to the toolbar for typing the name, or part
+// of the name, of a layer, which calls the given find function with the
+// text when it is entered.
+func AddField(p *tree.Plan, find func(name string)) {
+	tree.Add(p, func(w *core.TextField) {
+		w.SetPlaceholder("Find layer")
+		w.SetTooltip("type the name, or part of the name, of a layer, and press enter to point the Network view camera at it")
+		w.OnChange(func(e events.Event) {
+			find(w.Text())
+		})
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gifmovie records movies of a sim, e.g., of the activity
+// or the weights of a network changing over time, as animated GIFs,
+// adding one image as a frame at a time.
+package gifmovie
+
+import (
+	"image"
+	"image/gif"
+	"os"
+)
+
+// MinDelay is the minimum delay of each frame, in 100ths of a second.
+// Browsers show frames with shorter delays at a much slower rate.
+const MinDelay = 2
+
+// Movie is an animated GIF being recorded.
+type Movie struct {
+	gif.GIF
+
+	// delay of each frame, in 100ths of a second
+	delay int
+}
+
+// New returns a new Movie, shown at the given number of
+// frames per second, up to 100 / MinDelay.
+func New(fps int) *Movie {
+	return &Movie{delay: Delay(fps)}
+}
+
+// Delay returns the delay of each frame in 100ths of a second
+// for the given number of frames per second, which is at least MinDelay.
+func Delay(fps int) int {
+	return max(100/max(fps, 1), MinDelay)
+}
+
+// AddFrame adds the image as the next frame of the movie.
+func (mv *Movie) AddFrame(img *image.Paletted) {
+	mv.Image = append(mv.Image, img)
+	mv.Delay = append(mv.Delay, mv.delay)
+}
+
+// Frames returns the number of frames in the movie.
+func (mv *Movie) Frames() int {
+	return len(mv.Image)
+}
+
+// Save saves the movie to the given file as an animated GIF.
+func (mv *Movie) Save(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = gif.EncodeAll(f, &mv.GIF)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package golden provides a deterministic golden-file regression test
+harness for the stats logs of the sims.
+
+Each sim that supports the harness has a Golden config option, which runs
+the model headless with its default (fixed) random seed, and calls [Check]
+on its Train Epoch log, comparing it against the golden file stored in
+the testdata directory of the sim.  The GoldenUpdate config option
+regenerates the golden file from the current results, for use when a
+change in the results is expected, or to create it for a new sim.
+It is an error for the golden file to be missing otherwise.
+
+The check is run by the TestGolden test of each sim, as part of go test,
+and it can also be run with the golden.goal script for all of the sims,
+or for a single sim from its directory with:
+
+	go run . -Golden
+
+and regenerate its golden file with:
+
+	go run . -Golden -GoldenUpdate
+
+To add a sim to the harness, add the Golden, GoldenUpdate and GoldenEpochs
+config options, and the RunHeadless (see the headless package), CheckGolden
+and RunGolden methods and the TestGolden test modeled on those of an existing
+sim (e.g., ch7/abac), call RunGolden from main when Golden is set, add the sim
+directory to the list in the golden.goal script at the top level, and
+commit the golden file made with GoldenUpdate.
+*/
+package golden
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/emer/etensor/tensor/table"
+)
+
+// Tol is the relative tolerance for differences in numerical values
+// between the results and the golden file.
+var Tol = 1.0e-6
+
+// MaxDiffs is the maximum number of differences reported by [Compare].
+var MaxDiffs = 10
+
+// Check compares the given table against the golden file, returning
+// an error describing the differences if they do not match, or if the
+// golden file does not exist.  If update is true, the golden file is
+// written from the table instead.  Columns named in skip are excluded,
+// which must be used for any values that are not deterministic,
+// such as timing stats (e.g., PerTrlMSec).
+func Check(dt *table.Table, file string, update bool, skip ...string) error {
+	if !update {
+		if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("golden: %s does not exist: run with -GoldenUpdate to create it", file)
+		}
+		return Compare(dt, file, skip...)
+	}
+	if err := Save(dt, file, skip...); err != nil {
+		return err
+	}
+	fmt.Printf("golden: wrote %s\n", file)
+	return nil
+}
+
+// Save writes the given table to the golden file, as tab-separated
+// values with a header row of column names, excluding the columns
+// named in skip.  Tensor cells are written as separate columns,
+// with the cell index in brackets after the column name.
+func Save(dt *table.Table, file string, skip ...string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for ri, row := range rows(dt, skip) {
+		if ri > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.Join(row, "\t"))
+	}
+	b.WriteString("\n")
+	return os.WriteFile(file, []byte(b.String()), 0644)
+}
+
+// Compare compares the given table against the golden file, excluding
+// the columns named in skip, returning an error that lists the first
+// MaxDiffs differences if they do not match.  Numerical values are
+// compared with relative tolerance Tol, and other values must be equal.
+func Compare(dt *table.Table, file string, skip ...string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var gold [][]string
+	for _, ln := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		gold = append(gold, strings.Split(ln, "\t"))
+	}
+	cur := rows(dt, skip)
+	if !slices.Equal(cur[0], gold[0]) {
+		return fmt.Errorf("golden: %s: columns differ:\n  got:  %v\n  want: %v", file, cur[0], gold[0])
+	}
+	var diffs []string
+	if len(cur) != len(gold) {
+		diffs = append(diffs, fmt.Sprintf("  number of rows: got %d, want %d", len(cur)-1, len(gold)-1))
+	}
+	hdr := cur[0]
+	nr := min(len(cur), len(gold))
+	for ri := 1; ri < nr && len(diffs) < MaxDiffs; ri++ {
+		for ci, nm := range hdr {
+			if ci >= len(gold[ri]) || !equal(cur[ri][ci], gold[ri][ci]) {
+				want := ""
+				if ci < len(gold[ri]) {
+					want = gold[ri][ci]
+				}
+				diffs = append(diffs, fmt.Sprintf("  row %d, %s: got %s, want %s", ri-1, nm, cur[ri][ci], want))
+				if len(diffs) >= MaxDiffs {
+					break
+				}
+			}
+		}
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("golden: %s: results differ:\n%s", file, strings.Join(diffs, "\n"))
+}
+
+// rows returns the header row and the values of each row of the table
+// as strings, excluding the columns named in skip.
+func rows(dt *table.Table, skip []string) [][]string {
+	out := make([][]string, dt.Rows+1)
+	for ci, nm := range dt.ColumnNames {
+		if slices.Contains(skip, nm) {
+			continue
+		}
+		cl := dt.Columns[ci]
+		ncell := 1
+		if dt.Rows > 0 {
+			ncell = cl.Len() / dt.Rows
+		}
+		for ce := range ncell {
+			if ncell == 1 {
+				out[0] = append(out[0], nm)
+			} else {
+				out[0] = append(out[0], fmt.Sprintf("%s[%d]", nm, ce))
+			}
+			for ri := range dt.Rows {
+				out[ri+1] = append(out[ri+1], cl.String1D(ri*ncell+ce))
+			}
+		}
+	}
+	return out
+}
+
+// equal returns true if the two values are equal, within
+// the relative tolerance Tol for numerical values.
+func equal(a, b string) bool {
+	if a == b {
+		return true
+	}
+	af, aerr := strconv.ParseFloat(a, 64)
+	bf, berr := strconv.ParseFloat(b, 64)
+	if aerr != nil || berr != nil {
+		return false
+	}
+	if math.IsNaN(af) || math.IsNaN(bf) {
+		return math.IsNaN(af) && math.IsNaN(bf)
+	}
+	return math.Abs(af-bf) <= Tol*max(1, math.Abs(bf))
+}
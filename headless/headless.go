@@ -0,0 +1,88 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package headless provides a programmatic interface for running a sim
+without the GUI, and getting its stats as an in-memory table, for
+scripting experiments, e.g., from a test or from an interpreter such as
+gomacro, independent of the GUI.  No files are written.
+
+A sim supports this interface by implementing the [Sim] methods, as in
+ch7/abac, and is then run from within its package with the default Config:
+
+	dt := headless.Run(&Sim{}, nil)
+
+or with a given Config, in which any fields not set are zero:
+
+	dt := headless.Run(&Sim{}, &Config{NRuns: 1, NEpochs: 20, TestInterval: 1})
+
+[RunContext] runs the sim with a [context.Context] that can cancel the run,
+e.g., with a timeout for batch jobs, in which case the stats table has
+the results up to the point of cancellation.
+*/
+package headless
+
+import (
+	"context"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// Sim is the interface for a sim that can be run headless,
+// where C is the type of its Config.
+type Sim[C any] interface {
+	// New creates the sim, with the default Config values.
+	New()
+
+	// SimConfig returns a pointer to the Config of the sim.
+	SimConfig() *C
+
+	// RunHeadless configures and runs the sim without the GUI,
+	// according to its current Config, and returns its stats table.
+	// The run must stop when the context is done, which is
+	// done by calling [AddCancel] on its loops.
+	RunHeadless(ctx context.Context) *table.Table
+}
+
+// Run creates the given sim, sets its Config to the given one
+// if it is non-nil (otherwise the defaults are used), and runs it
+// without the GUI, returning its stats table.
+func Run[C any](sim Sim[C], cfg *C) *table.Table {
+	dt, _ := RunContext(context.Background(), sim, cfg)
+	return dt
+}
+
+// RunContext is like [Run], with a context that stops the run when
+// it is done, e.g., from a timeout or cancel function.  The stats table
+// has the results up to the point at which the run was stopped,
+// and the error is that of the context if it was stopped.
+func RunContext[C any](ctx context.Context, sim Sim[C], cfg *C) (*table.Table, error) {
+	sim.New()
+	if cfg != nil {
+		*sim.SimConfig() = *cfg
+	}
+	dt := sim.RunHeadless(ctx)
+	return dt, ctx.Err()
+}
+
+// AddCancel adds IsDone conditions to the Run, Epoch and Trial loops
+// of given mode, which stop the loops when the context is done.
+// The current trial finishes normally, and the current epoch then
+// ends early, so it is logged with the stats over the trials that
+// were completed, and the logs remain valid.
+func AddCancel(ctx context.Context, ls *looper.Stacks, mode etime.Modes) {
+	if ctx.Done() == nil { // never cancelled
+		return
+	}
+	done := func() bool {
+		return ctx.Err() != nil
+	}
+	for _, tm := range []etime.Times{etime.Run, etime.Epoch, etime.Trial} {
+		if lp := ls.Loop(mode, tm); lp != nil {
+			lp.IsDone.AddBool("Cancel", done)
+		}
+	}
+}
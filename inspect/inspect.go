@@ -0,0 +1,65 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package inspect shows the exact values of the units in a layer in a
+popup table, so they can be read directly instead of estimated from the
+colors in the NetView.  A sim adds an Inspect Layer toolbar button that
+calls [Show] with the [LayerTable] for the layer of the unit that was last
+clicked on in the NetView, as in ch3/faces.
+*/
+package inspect
+
+import (
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"github.com/emer/etensor/tensor/table"
+	"github.com/emer/etensor/tensor/tensorcore"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// Vars are the default unit variables shown by [LayerTable].
+var Vars = []string{"Act", "ActM", "ActP", "Ge", "Gi", "Vm", "Ext", "Targ"}
+
+// LayerTable returns a table with the current values of the given
+// unit variables (Vars if none) for each unit in the given layer,
+// with the index of the unit in the first column.
+func LayerTable(ly *leabra.Layer, vars ...string) *table.Table {
+	if len(vars) == 0 {
+		vars = Vars
+	}
+	dt := table.NewTable()
+	dt.SetMetaData("name", ly.Name)
+	dt.SetMetaData("desc", "current unit values of layer "+ly.Name)
+	dt.SetMetaData("read-only", "true")
+	dt.AddIntColumn("Unit")
+	for _, vnm := range vars {
+		dt.AddFloat64Column(vnm)
+	}
+	nu := len(ly.Neurons)
+	dt.SetNumRows(nu)
+	for ui := range nu {
+		dt.SetFloat("Unit", ui, float64(ui))
+	}
+	for _, vnm := range vars {
+		vi, err := ly.UnitVarIndex(vnm)
+		if errors.Log(err) != nil {
+			continue
+		}
+		for ui := range nu {
+			dt.SetFloat(vnm, ui, float64(ly.UnitValue1D(vi, ui, 0)))
+		}
+	}
+	return dt
+}
+
+// Show shows the given table in a popup window dialog,
+// with the given title, in the context of the given widget.
+func Show(ctx core.Widget, dt *table.Table, title string) {
+	d := core.NewBody(title)
+	tv := tensorcore.NewTable(d)
+	tv.SetReadOnly(true)
+	tv.SetTable(dt)
+	d.AddOKOnly().RunWindowDialog(ctx)
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package mmapfile
+
+import "errors"
+
+// Map returns an error on this platform, where memory mapping is
+// not supported: use [Open] to fall back to reading the file.
+func Map(path string) (*File, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func unmap(b []byte) error {
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package mmapfile
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Map returns the contents of the file at the given path, memory-mapped
+// read-only, or an error if it cannot be mapped.
+func Map(path string) (*File, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close() // the mapping remains valid after closing
+	fi, err := fp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	n := fi.Size()
+	if n == 0 { // empty files cannot be mapped
+		return &File{}, nil
+	}
+	if int64(int(n)) != n {
+		return nil, fmt.Errorf("mmapfile: %s is too large to map", path)
+	}
+	b, err := syscall.Mmap(int(fp.Fd()), 0, int(n), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmapfile: %s: %w", path, err)
+	}
+	return &File{Data: b, Mapped: true}, nil
+}
+
+func unmap(b []byte) error {
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package mmapfile opens large data files, such as the text corpora of the
+language sims, by memory-mapping them read-only, so their contents are
+paged in from the file by the operating system as they are accessed,
+instead of all being read into memory up front.  The contents of a mapped
+file must not be modified, and are no longer valid after [File.Close].
+
+[Open] falls back to reading the whole file into memory when memory
+mapping is not supported on the platform, or fails, so a sim can use it
+in any case.  See ch10/sem.
+*/
+package mmapfile
+
+import "os"
+
+// File has the contents of a file, opened by [Open] or [Map].
+type File struct {
+
+	// contents of the file, which must not be modified
+	Data []byte
+
+	// whether the Data is memory-mapped, otherwise it was read into memory
+	Mapped bool
+}
+
+// Open returns the contents of the file at the given path, memory-mapped
+// if mmap is true and the file can be mapped, and otherwise read into memory.
+func Open(path string, mmap bool) (*File, error) {
+	if mmap {
+		if f, err := Map(path); err == nil {
+			return f, nil
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Data: b}, nil
+}
+
+// Close unmaps the Data if it is mapped, after which it must not be used.
+func (f *File) Close() error {
+	var err error
+	if f.Mapped {
+		err = unmap(f.Data)
+	}
+	f.Data = nil
+	f.Mapped = false
+	return err
+}
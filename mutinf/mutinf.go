@@ -0,0 +1,70 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package mutinf estimates the mutual information between two discrete
+variables from samples of them, e.g., between the input patterns presented
+over a test epoch and the responses of a category layer to them, binned
+into the most active unit, which quantifies how much information about
+the input the layer carries.
+
+[MI] is the plug-in estimate, which uses the observed frequencies as the
+probabilities.  It is biased upward for small numbers of samples relative
+to the number of possible values, because chance co-occurrences in a
+small sample look like information: with only one sample of each input,
+every response appears to be fully determined by the input, and the MI is
+just the entropy of the responses.  [Bias] estimates this bias to first
+order (the Miller-Madow correction), which can be subtracted from MI, but
+it is only reliable when there are several samples of each value.
+*/
+package mutinf
+
+import "math"
+
+// MI returns the plug-in estimate of the mutual information, in bits,
+// between the paired values of x and y, which must be the same length.
+func MI(x, y []int) float64 {
+	n := len(x)
+	if n == 0 {
+		return 0
+	}
+	px := counts(x)
+	py := counts(y)
+	pxy := make(map[[2]int]int)
+	for i := range n {
+		pxy[[2]int{x[i], y[i]}]++
+	}
+	nf := float64(n)
+	mi := 0.0
+	for xy, c := range pxy {
+		p := float64(c) / nf
+		mi += p * math.Log2(p*nf*nf/(float64(px[xy[0]])*float64(py[xy[1]])))
+	}
+	return max(mi, 0)
+}
+
+// Bias returns the first-order (Miller-Madow) estimate of the upward
+// bias of [MI] for the given values, in bits, based on the numbers of
+// distinct values observed of x, y, and the (x, y) pairs.
+func Bias(x, y []int) float64 {
+	n := len(x)
+	if n == 0 {
+		return 0
+	}
+	nxy := make(map[[2]int]bool)
+	for i := range n {
+		nxy[[2]int{x[i], y[i]}] = true
+	}
+	b := float64(len(nxy)-len(counts(x))-len(counts(y))+1) / (2 * float64(n) * math.Ln2)
+	return max(b, 0)
+}
+
+// counts returns the number of times each value occurs in v.
+func counts(v []int) map[int]int {
+	c := make(map[int]int)
+	for _, vi := range v {
+		c[vi]++
+	}
+	return c
+}
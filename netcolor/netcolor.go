@@ -0,0 +1,41 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package netcolor sets the color map used to display the unit values in a
+NetView from a name in the Config of a sim, so that the choice persists
+across sessions (e.g., in config.toml) and can be given on the command line.
+The default ColdHot map is not ideal for all purposes: useful alternatives
+include Viridis, which is perceptually uniform and readable with the common
+forms of color blindness, DarkLight, a grayscale map for printed figures,
+and BlueWhiteRed, a diverging map centered on white.  Any of the color maps
+in the colormap.AvailableMaps of Cogent Core can be used.
+A sim calls [Set] on its NetView in ConfigGUI, as in ch3/faces.
+*/
+package netcolor
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/colors/colormap"
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/netview"
+)
+
+// Set sets the color map of the given NetView to the one with the given
+// name, returning an error listing the available maps if there is no map
+// with that name.  An empty name leaves the NetView default.
+func Set(nv *netview.NetView, name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := colormap.AvailableMaps[name]; !ok {
+		return fmt.Errorf("netcolor: color map %q not found, available maps are: %s", name, strings.Join(slices.Sorted(maps.Keys(colormap.AvailableMaps)), ", "))
+	}
+	nv.Options.ColorMap = core.ColorMapName(name)
+	return nil
+}
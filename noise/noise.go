@@ -0,0 +1,107 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package noise presents noisy variants of the input patterns of a sim on the
+fly, for testing the robustness of a trained network, without precomputing
+noisy pattern sets.  The noise is either gaussian, or salt-and-pepper noise
+that flips values, which is more appropriate for binary patterns.
+The noise for each trial is generated from the [Params.Seed] and the trial
+index, so a given pattern at a given noise level always has the same noise,
+and results are reproducible.
+
+A sim uses it by calling [Params.Add] on the input patterns in its
+ApplyInputs method during testing (see ch4/pat_assoc), and [Params.Sweep] to
+test accuracy as a function of the noise level.
+*/
+package noise
+
+import (
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"github.com/emer/etensor/tensor"
+	"github.com/emer/etensor/tensor/table"
+)
+
+// Params are the parameters for the noise added to input patterns.
+type Params struct {
+
+	// use salt-and-pepper noise instead of gaussian noise: each value of the
+	// input patterns is flipped (to 1 - value) with probability Level,
+	// which is more appropriate for binary patterns.
+	Flip bool
+
+	// amount of noise: the standard deviation of the gaussian noise added to
+	// each value of the input patterns, which are then clipped to the 0-1
+	// range, or the probability of flipping each value if Flip; 0 = no noise.
+	Level float32 `min:"0" step:"0.05"`
+
+	// random seed for the noise, which is combined with the trial index,
+	// so that each trial has its own noise that is the same every time.
+	Seed int64
+}
+
+func (np *Params) Defaults() {
+	np.Flip = false
+	np.Level = 0
+	np.Seed = 1
+}
+
+// Add returns a copy of the given patterns for the given trial index,
+// with the noise added, or the patterns themselves if Level is 0.
+func (np *Params) Add(pats tensor.Tensor, trial int) tensor.Tensor {
+	if np.Level <= 0 {
+		return pats
+	}
+	rnd := rand.New(rand.NewSource(np.Seed*1000003 + int64(trial)))
+	out := tensor.NewFloat32(pats.Shape().Sizes)
+	for i := range pats.Len() {
+		v := float32(pats.Float1D(i))
+		if np.Flip {
+			if rnd.Float32() < np.Level {
+				v = 1 - v
+			}
+		} else {
+			v += np.Level * float32(rnd.NormFloat64())
+		}
+		out.Values[i] = math32.Clamp(v, 0, 1)
+	}
+	return out
+}
+
+// ConfigSweep configures the table of results from [Params.Sweep],
+// with the PctCor for each noise Level.
+func ConfigSweep(dt *table.Table) {
+	dt.SetMetaData("name", "NoiseSweep")
+	dt.SetMetaData("desc", "testing accuracy as a function of the input noise level")
+	dt.SetMetaData("read-only", "true")
+	dt.AddFloat64Column("Level")
+	dt.AddFloat64Column("PctCor")
+}
+
+// Sweep sets the noise Level to each of the given levels in turn, and calls
+// the test function, which tests the network with that level and returns its
+// accuracy as the proportion correct, recording the results in the given
+// table, configured with [ConfigSweep].  The original Level is restored.
+func (np *Params) Sweep(dt *table.Table, levels []float32, test func() float64) {
+	lev := np.Level
+	dt.SetNumRows(len(levels))
+	for i, l := range levels {
+		np.Level = l
+		dt.SetFloat("Level", i, float64(l))
+		dt.SetFloat("PctCor", i, test())
+	}
+	np.Level = lev
+}
+
+// Levels returns n+1 evenly spaced noise levels from 0 to top.
+func Levels(top float32, n int) []float32 {
+	n = max(n, 1)
+	lv := make([]float32, n+1)
+	for i := range lv {
+		lv[i] = top * float32(i) / float32(n)
+	}
+	return lv
+}
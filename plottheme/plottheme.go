@@ -0,0 +1,66 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package plottheme switches the plots of a sim between light and dark
+themes, e.g., for lectures in a dark room.  The plots are drawn with the
+colors of the current Cogent Core theme, so this sets the theme of the
+app, which updates all of the open plot tabs (and the rest of the window)
+to match.  The theme is not saved in the Cogent Core settings: a sim keeps
+the choice in its Config, so it persists across sessions in config.toml,
+calls [Init] in ConfigGUI, and adds a toggle with [AddSwitch], as in ch3/faces.
+*/
+package plottheme
+
+import (
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/tree"
+)
+
+// Init sets the dark theme if dark is true, for a sim to call in ConfigGUI
+// with its config option.  Otherwise, the theme is left as it is in the
+// Cogent Core settings (e.g., ThemeAuto), as the option is false by default.
+func Init(dark bool) {
+	if dark {
+		Set(true)
+	}
+}
+
+// prevTheme is the theme from the Cogent Core settings before
+// Set last changed it to dark, which Set restores when dark is false.
+var prevTheme = core.ThemeLight
+
+// Set sets the theme of the app, and thus all of its plots, to dark if
+// dark is true, and otherwise back to the theme from the Cogent Core
+// settings that was in effect before (e.g., ThemeAuto).
+func Set(dark bool) {
+	th := prevTheme
+	if dark {
+		th = core.ThemeDark
+	}
+	if core.AppearanceSettings.Theme == th {
+		return
+	}
+	if dark {
+		prevTheme = core.AppearanceSettings.Theme
+	}
+	core.AppearanceSettings.Theme = th
+	core.AppearanceSettings.Apply()
+	core.UpdateAll()
+}
+
+// AddSwitch adds a Dark switch to the toolbar, which
+// sets dark and calls [Set] when it is toggled.
+func AddSwitch(p *tree.Plan, dark *bool) {
+	tree.Add(p, func(w *core.Switch) {
+		w.SetText("Dark")
+		w.SetTooltip("switch the plots between light and dark themes")
+		w.SetChecked(*dark)
+		w.OnChange(func(e events.Event) {
+			*dark = w.IsChecked()
+			Set(*dark)
+		})
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package sdt computes signal detection theory stats for categorization,
+treating the response to each trial as the detection of a target category
+vs. the rest: a hit is responding with the target category when it is
+present, and a false alarm is responding with it when it is not.
+The sensitivity d' (d-prime) is the difference between the z-scores of
+the hit and false alarm rates, which is independent of the bias
+toward responding with the target category.
+
+A sim accumulates [Counts] over the trials of a test epoch, as in
+ch3/faces, and records the [Counts.DPrime] at the end of the epoch.
+*/
+package sdt
+
+import "math"
+
+// Counts are the numbers of each kind of trial outcome,
+// accumulated over trials by [Counts.Add].
+type Counts struct {
+
+	// target present and responded
+	Hits int
+
+	// target present and not responded
+	Misses int
+
+	// target absent and responded
+	FalseAlarms int
+
+	// target absent and not responded
+	CorrectRejects int
+}
+
+// Reset sets all the counts to 0, e.g., at the start of an epoch.
+func (sc *Counts) Reset() {
+	*sc = Counts{}
+}
+
+// Add counts one trial with the given target presence and response.
+func (sc *Counts) Add(target, response bool) {
+	switch {
+	case target && response:
+		sc.Hits++
+	case target:
+		sc.Misses++
+	case response:
+		sc.FalseAlarms++
+	default:
+		sc.CorrectRejects++
+	}
+}
+
+// HitRate returns the proportion of target trials that were responded,
+// with the log-linear correction of adding .5 to the hits and 1 to the
+// trials, which keeps the rate strictly between 0 and 1, so d' is finite.
+func (sc *Counts) HitRate() float64 {
+	return (float64(sc.Hits) + .5) / (float64(sc.Hits+sc.Misses) + 1)
+}
+
+// FARate returns the proportion of non-target trials that were responded,
+// with the same log-linear correction as HitRate.
+func (sc *Counts) FARate() float64 {
+	return (float64(sc.FalseAlarms) + .5) / (float64(sc.FalseAlarms+sc.CorrectRejects) + 1)
+}
+
+// DPrime returns the sensitivity d' = z(HitRate) - z(FARate),
+// which is 0 for chance performance.
+func (sc *Counts) DPrime() float64 {
+	return Z(sc.HitRate()) - Z(sc.FARate())
+}
+
+// Z returns the z-score of the given probability, i.e., the inverse of the
+// standard normal cumulative distribution function.
+func Z(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
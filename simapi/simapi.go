@@ -0,0 +1,355 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package simapi provides an HTTP API for driving a sim without the GUI,
+from another program or a web front-end, e.g., for automated grading of
+exercises, using the same Init and looper controls as the toolbar.
+All of the responses are JSON.  The endpoints are:
+
+  - POST /init: initializes the sim, as with the Init button.
+  - POST /step?mode=Train&time=Trial&n=1: steps the given number of the
+    given time scale in the given mode (defaults as shown), and returns
+    the last row of the stats for that time scale.
+  - POST /run?mode=Train: starts running the given mode, in the background,
+    as with the Run button.  reset=true resets the counters first, which
+    is needed to run a Test epoch again after the first one.
+  - POST /stop: stops a run at the end of the current trial.
+  - GET /status: returns whether the sim is Running.
+  - GET /stats?mode=Train&time=Epoch: returns the stats for the given
+    time scale, as a list of Rows, or just the last one with last=true.
+  - POST /quit: stops the server, so [Serve] returns.
+
+Only one init, step, or run can happen at a time: the others fail with
+status 409 (Conflict) until it is done.  While the sim is busy, the stats
+are read from the logs at the end of the next trial, so they are never
+read while they are being written.
+
+A sim supports the API by implementing the [Sim] methods, and serves
+it when enabled in its Config, as in ch7/abac.  The server binds to
+localhost by default, so it is only reachable from the same machine.
+*/
+package simapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/compcogneuro/sims/v2/statstream"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// DefaultAddr is the default address of the server, on localhost.
+const DefaultAddr = "localhost:8766"
+
+// Sim is the interface for a sim that can be driven by the API.
+type Sim interface {
+	// Init initializes the sim, as with the Init button.
+	Init()
+
+	// SimLoops returns the looper stacks of the sim.
+	SimLoops() *looper.Stacks
+
+	// SimLogs returns the logs of the sim, for the stats.
+	SimLogs() *elog.Logs
+}
+
+// server has the state of the API server for a sim.
+type server struct {
+	sim Sim
+
+	mu sync.Mutex
+
+	// true while an init, step or run is going
+	busy bool
+
+	// set by stop, to end the current run at the end of the trial
+	stop bool
+
+	// set by quit, so Serve returns without an error
+	quit bool
+
+	// funcs waiting to read the logs until the end of the current trial,
+	// or of the current init, step or run, added by withLogs
+	pending []func()
+}
+
+// Serve serves the API for the given sim at the given address
+// (DefaultAddr if empty), until it is stopped by /quit,
+// returning an error if it cannot listen on the address.
+func Serve(addr string, sim Sim) error {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	sv := &server{sim: sim}
+	sv.addStop()
+	sv.addPending()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /init", sv.init)
+	mux.HandleFunc("POST /step", sv.step)
+	mux.HandleFunc("POST /run", sv.run)
+	mux.HandleFunc("POST /stop", sv.stopRun)
+	mux.HandleFunc("GET /status", sv.status)
+	mux.HandleFunc("GET /stats", sv.stats)
+	mux.HandleFunc("POST /quit", func(w http.ResponseWriter, r *http.Request) {
+		sv.mu.Lock()
+		sv.quit = true
+		sv.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"Quit": true})
+		go ln.Close()
+	})
+	err = http.Serve(ln, mux)
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if sv.quit {
+		return nil
+	}
+	return err
+}
+
+// addStop adds IsDone conditions to the Run, Epoch and Trial loops
+// of all modes, which stop the loops when stop is set, as in
+// [headless.AddCancel].
+func (sv *server) addStop() {
+	done := func() bool {
+		sv.mu.Lock()
+		defer sv.mu.Unlock()
+		return sv.stop
+	}
+	ls := sv.sim.SimLoops()
+	for mode := range ls.Stacks {
+		for _, tm := range []etime.Times{etime.Run, etime.Epoch, etime.Trial} {
+			if lp := ls.Loop(mode, tm); lp != nil {
+				lp.IsDone.AddBool("APIStop", done)
+			}
+		}
+	}
+}
+
+// addPending adds OnEnd functions to the Trial loops of all modes,
+// which run the pending withLogs funcs, after the trial is logged.
+func (sv *server) addPending() {
+	ls := sv.sim.SimLoops()
+	for mode := range ls.Stacks {
+		if lp := ls.Loop(mode, etime.Trial); lp != nil {
+			lp.OnEnd.Add("APIPending", sv.runPending)
+		}
+	}
+}
+
+// runPending runs the pending withLogs funcs.
+func (sv *server) runPending() {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.callPending()
+}
+
+// callPending calls the pending withLogs funcs, with mu locked.
+func (sv *server) callPending() {
+	for _, f := range sv.pending {
+		f()
+	}
+	sv.pending = nil
+}
+
+// withLogs calls f when the logs are not being written: right away if
+// the sim is not busy, and otherwise at the end of the current trial,
+// or when the sim is done, waiting until then to return.
+func (sv *server) withLogs(f func()) {
+	sv.mu.Lock()
+	if !sv.busy {
+		defer sv.mu.Unlock()
+		f()
+		return
+	}
+	ready := make(chan struct{})
+	sv.pending = append(sv.pending, func() {
+		f()
+		close(ready)
+	})
+	sv.mu.Unlock()
+	<-ready
+}
+
+// start marks the server as busy, returning false and writing
+// a Conflict error if it already is.
+func (sv *server) start(w http.ResponseWriter) bool {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	if sv.busy {
+		writeError(w, http.StatusConflict, fmt.Errorf("the sim is busy: use /stop to stop a run"))
+		return false
+	}
+	sv.busy = true
+	sv.stop = false
+	return true
+}
+
+// done marks the server as no longer busy, and runs the pending
+// withLogs funcs.
+func (sv *server) done() {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.busy = false
+	sv.callPending()
+}
+
+func (sv *server) init(w http.ResponseWriter, r *http.Request) {
+	if !sv.start(w) {
+		return
+	}
+	defer sv.done()
+	sv.sim.Init()
+	writeJSON(w, http.StatusOK, map[string]any{"Init": true})
+}
+
+func (sv *server) step(w http.ResponseWriter, r *http.Request) {
+	mode, err := parseMode(r, "Train")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	tm, err := parseTime(r, "Trial")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	n := 1
+	if s := r.FormValue("n"); s != "" {
+		if n, err = strconv.Atoi(s); err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid n: %q", s))
+			return
+		}
+	}
+	if !sv.start(w) {
+		return
+	}
+	defer sv.done()
+	sv.sim.SimLoops().Step(mode, n, tm)
+	dt := sv.sim.SimLogs().Table(mode, tm)
+	if dt == nil || dt.Rows == 0 {
+		writeJSON(w, http.StatusOK, map[string]any{})
+		return
+	}
+	writeJSON(w, http.StatusOK, statstream.Row(dt, dt.Rows-1))
+}
+
+func (sv *server) run(w http.ResponseWriter, r *http.Request) {
+	mode, err := parseMode(r, "Train")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	reset := r.FormValue("reset") == "true"
+	if !sv.start(w) {
+		return
+	}
+	go func() {
+		defer sv.done()
+		if reset {
+			sv.sim.SimLoops().ResetAndRun(mode)
+		} else {
+			sv.sim.SimLoops().Run(mode)
+		}
+	}()
+	writeJSON(w, http.StatusAccepted, map[string]any{"Running": true})
+}
+
+func (sv *server) stopRun(w http.ResponseWriter, r *http.Request) {
+	sv.mu.Lock()
+	sv.stop = sv.busy
+	sv.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"Stop": true})
+}
+
+func (sv *server) status(w http.ResponseWriter, r *http.Request) {
+	sv.mu.Lock()
+	busy := sv.busy
+	sv.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"Running": busy})
+}
+
+func (sv *server) stats(w http.ResponseWriter, r *http.Request) {
+	mode, err := parseMode(r, "Train")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	tm, err := parseTime(r, "Epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	last := r.FormValue("last") == "true"
+	var name string
+	var rows []map[string]any
+	sv.withLogs(func() {
+		dt := sv.sim.SimLogs().Table(mode, tm)
+		if dt == nil {
+			return
+		}
+		name = dt.MetaData["name"]
+		st := 0
+		if last {
+			st = max(dt.Rows-1, 0)
+		}
+		rows = []map[string]any{}
+		for row := st; row < dt.Rows; row++ {
+			rows = append(rows, statstream.Row(dt, row))
+		}
+	})
+	if rows == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no stats for %s %s", mode, tm))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"Table": name, "Rows": rows})
+}
+
+// parseMode returns the mode given by the mode form value, or the default.
+func parseMode(r *http.Request, def string) (etime.Modes, error) {
+	var mode etime.Modes
+	s := r.FormValue("mode")
+	if s == "" {
+		s = def
+	}
+	if err := mode.SetString(s); err != nil {
+		return mode, fmt.Errorf("invalid mode: %q", s)
+	}
+	return mode, nil
+}
+
+// parseTime returns the time scale given by the time form value, or the default.
+func parseTime(r *http.Request, def string) (etime.Times, error) {
+	var tm etime.Times
+	s := r.FormValue("time")
+	if s == "" {
+		s = def
+	}
+	if err := tm.SetString(s); err != nil {
+		return tm, fmt.Errorf("invalid time: %q", s)
+	}
+	return tm, nil
+}
+
+// writeJSON writes the given value as the JSON response, with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error as a JSON response, with the given status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]any{"Error": err.Error()})
+}
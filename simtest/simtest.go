@@ -0,0 +1,100 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package simtest provides helpers shared by the tests of the sims,
+// which are each in their own main package.
+package simtest
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// Sim is the interface of a sim used by [Config].
+type Sim interface {
+	New()
+	ConfigAll()
+}
+
+// Config calls New and ConfigAll on the given sim, without the GUI.
+// The test flags are removed from the args during New, so that they
+// are not seen by the econfig config of the sim.
+func Config(t testing.TB, ss Sim) {
+	t.Helper()
+	restore := NoTestArgs()
+	ss.New()
+	restore()
+	ss.ConfigAll()
+}
+
+// NoTestArgs removes the test flags from os.Args, so that they are not
+// seen by the econfig config of a sim, and returns a func that restores them.
+// It is for sims that need to set config options between New and ConfigAll.
+func NoTestArgs() (restore func()) {
+	args := os.Args
+	os.Args = args[:1]
+	return func() { os.Args = args }
+}
+
+// InitClearsLogs checks that calling init after training one short run
+// clears the Train Epoch and Run logs, so the plots start out empty,
+// and resets all of the float64 and int Stats tensors, which are used
+// to accumulate results, to zero rows.
+func InitClearsLogs(t *testing.T, init func(), ls *looper.Stacks, logs *elog.Logs, stats *estats.Stats) {
+	t.Helper()
+	init()
+	ls.Loop(etime.Train, etime.Run).Counter.Max = 1
+	ls.Loop(etime.Train, etime.Epoch).Counter.Max = 1
+	ls.Loop(etime.Train, etime.Trial).Counter.Max = 2
+	ls.Run(etime.Train)
+	times := []etime.Times{etime.Epoch, etime.Run}
+	for _, tm := range times {
+		if logs.Table(etime.Train, tm).Rows == 0 {
+			t.Fatalf("no Train %s log rows after training", tm)
+		}
+	}
+	init()
+	for _, tm := range times {
+		if n := logs.Table(etime.Train, tm).Rows; n != 0 {
+			t.Errorf("Train %s log has %d rows after Init, want 0", tm, n)
+		}
+	}
+	for nm, tsr := range stats.F64Tensors {
+		if tsr.NumDims() > 0 && tsr.DimSize(0) != 0 {
+			t.Errorf("Stats tensor %s has %d rows after Init, want 0", nm, tsr.DimSize(0))
+		}
+	}
+	for nm, tsr := range stats.IntTensors {
+		if tsr.NumDims() > 0 && tsr.DimSize(0) != 0 {
+			t.Errorf("Stats tensor %s has %d rows after Init, want 0", nm, tsr.DimSize(0))
+		}
+	}
+}
+
+// EnvCounters returns the current values of the exported env.Counter
+// fields of each of the envs, by env name and counter name
+// (e.g., "Train.Trial").
+func EnvCounters(envs env.Envs) map[string]int {
+	cs := map[string]int{}
+	for nm, ev := range envs {
+		v := reflect.Indirect(reflect.ValueOf(ev))
+		for i := range v.NumField() {
+			fld := v.Type().Field(i)
+			if !fld.IsExported() {
+				continue
+			}
+			if c, ok := v.Field(i).Interface().(env.Counter); ok {
+				cs[nm+"."+fld.Name] = c.Cur
+			}
+		}
+	}
+	return cs
+}
@@ -0,0 +1,293 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package statstream streams the stats of a sim as JSON over a websocket,
+as each row of its logs is produced, for monitoring long runs without the
+GUI, e.g., on a remote machine, from a browser.  Opening the address of the
+[Server] in a browser shows a simple dashboard page with the latest values
+of the stats, which are streamed from the /stats websocket, and any other
+client can connect to /stats to receive the rows as JSON text messages.
+
+The server binds to localhost by default, so it is only reachable from the
+same machine (e.g., through an ssh tunnel), unless another address is given.
+A sim starts it from RunNoGUI when enabled in its Config, and calls
+[Server.SendRow] after logging each row to stream, as in ch6/objrec.
+
+Only the minimal part of the websocket protocol (RFC 6455) needed to send
+text messages from the server is implemented, using the standard library,
+and messages from the clients are ignored.  Each client has its own buffer
+of messages, written in the background, so a slow client does not hold up
+the sim: messages to a client whose buffer is full are dropped, and a
+client that does not accept a message within WriteTimeout is disconnected.
+Websocket connections from pages on other sites (by the Origin header)
+are refused.
+*/
+package statstream
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emer/etensor/tensor/table"
+)
+
+// DefaultAddr is the default address of the server, on localhost.
+const DefaultAddr = "localhost:8765"
+
+const (
+	// WriteTimeout is how long a client has to accept each message,
+	// before it is disconnected.
+	WriteTimeout = 10 * time.Second
+
+	// BufferSize is the number of messages buffered for each client,
+	// beyond which messages to the client are dropped.
+	BufferSize = 256
+)
+
+// Server is a websocket server that sends messages to all
+// of the clients connected to it.
+type Server struct {
+
+	// address the server is listening on
+	Addr string
+
+	ln      net.Listener
+	mu      sync.Mutex
+	clients map[*client]struct{}
+}
+
+// client is a websocket connection to the server.
+type client struct {
+	conn net.Conn
+
+	// messages waiting to be written to the connection
+	msgs chan []byte
+}
+
+// Start starts a Server listening on the given address (DefaultAddr if
+// empty), returning an error if it cannot listen on the address.
+func Start(addr string) (*Server, error) {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	sv := &Server{Addr: ln.Addr().String(), ln: ln, clients: make(map[*client]struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", sv.serveStats)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, dashboard)
+	})
+	go http.Serve(ln, mux)
+	return sv, nil
+}
+
+// Close stops the server and closes all of the client connections.
+func (sv *Server) Close() error {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	for c := range sv.clients {
+		sv.removeLocked(c)
+	}
+	return sv.ln.Close()
+}
+
+// Send sends the given value, encoded as JSON, to all of the clients,
+// without waiting for them to receive it.  It is dropped for any
+// clients whose buffer of messages is full.
+func (sv *Server) Send(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	for c := range sv.clients {
+		select {
+		case c.msgs <- b:
+		default:
+		}
+	}
+	return nil
+}
+
+// remove removes the client and closes its connection, if it has
+// not already been removed.
+func (sv *Server) remove(c *client) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.removeLocked(c)
+}
+
+// removeLocked is [Server.remove] with mu locked.
+func (sv *Server) removeLocked(c *client) {
+	if _, ok := sv.clients[c]; !ok {
+		return
+	}
+	delete(sv.clients, c)
+	close(c.msgs)
+	c.conn.Close()
+}
+
+// SendRow sends the values of the given row of the table as a JSON object,
+// made by [Row].
+func (sv *Server) SendRow(dt *table.Table, row int) error {
+	if row < 0 || row >= dt.Rows {
+		return fmt.Errorf("statstream: row %d out of range for %d rows", row, dt.Rows)
+	}
+	return sv.Send(Row(dt, row))
+}
+
+// Row returns the values of the given row of the table, which must be
+// in range, for encoding as a JSON object, with the name of the table as
+// "Table", and a field for each column with a single value, named by the
+// column (tensor columns are not included).  NaN values are nil (null).
+func Row(dt *table.Table, row int) map[string]any {
+	msg := map[string]any{"Table": dt.MetaData["name"]}
+	for ci, cl := range dt.Columns {
+		if cl.Len() != dt.Rows {
+			continue
+		}
+		nm := dt.ColumnNames[ci]
+		switch {
+		case cl.IsString():
+			msg[nm] = cl.String1D(row)
+		default:
+			v := cl.Float1D(row)
+			if v != v { // NaN is not valid JSON
+				msg[nm] = nil
+			} else {
+				msg[nm] = v
+			}
+		}
+	}
+	return msg
+}
+
+// serveStats upgrades the request to a websocket connection,
+// and adds it to the clients.
+func (sv *Server) serveStats(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		http.Error(w, "statstream: websocket connection required", http.StatusBadRequest)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "statstream: cross-origin websocket connection refused", http.StatusForbidden)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "statstream: websocket not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, bw, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	h := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	fmt.Fprintf(bw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(h[:]))
+	if bw.Flush() != nil {
+		conn.Close()
+		return
+	}
+	c := &client{conn: conn, msgs: make(chan []byte, BufferSize)}
+	sv.mu.Lock()
+	sv.clients[c] = struct{}{}
+	sv.mu.Unlock()
+	// write the messages, until the client is removed or a write fails
+	go func() {
+		for b := range c.msgs {
+			conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+			if writeText(bw.Writer, b) != nil {
+				sv.remove(c)
+				return
+			}
+		}
+	}()
+	// read and discard client messages, until the client closes
+	go func() {
+		io.Copy(io.Discard, bw.Reader)
+		sv.remove(c)
+	}()
+}
+
+// sameOrigin returns whether the Origin header of the request, if any,
+// is for the same host as the request, so that pages on other sites
+// cannot connect to the server from a browser.  Clients other than
+// browsers generally do not send an Origin, and are allowed.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// writeText writes b as a single unmasked websocket text frame.
+func writeText(w *bufio.Writer, b []byte) error {
+	if w == nil {
+		return errors.New("statstream: no connection")
+	}
+	w.WriteByte(0x81) // final frame, text
+	switch n := len(b); {
+	case n < 126:
+		w.WriteByte(byte(n))
+	case n < 1<<16:
+		w.WriteByte(126)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(127)
+		binary.Write(w, binary.BigEndian, uint64(n))
+	}
+	w.Write(b)
+	return w.Flush()
+}
+
+// dashboard is the page served at the root of the server, which shows
+// the latest row of each table streamed from /stats.
+const dashboard = `<!DOCTYPE html>
+<html><head><title>Sim Stats</title>
+<style>body{font-family:sans-serif} table{border-collapse:collapse;margin-bottom:1em} td,th{border:1px solid #ccc;padding:2px 6px;text-align:right}</style>
+</head><body><h3 id="status">connecting...</h3><div id="tables"></div>
+<script>
+const ws = new WebSocket("ws://" + location.host + "/stats");
+const status = document.getElementById("status");
+ws.onopen = () => status.textContent = "connected: latest stats";
+ws.onclose = () => status.textContent = "disconnected";
+ws.onmessage = (ev) => {
+	const row = JSON.parse(ev.data);
+	const id = "t_" + row.Table;
+	let el = document.getElementById(id);
+	if (!el) {
+		el = document.createElement("div");
+		el.id = id;
+		document.getElementById("tables").appendChild(el);
+	}
+	const keys = Object.keys(row).filter(k => k !== "Table");
+	el.innerHTML = "<b>" + row.Table + "</b><table><tr>" + keys.map(k => "<th>" + k + "</th>").join("") +
+		"</tr><tr>" + keys.map(k => "<td>" + (typeof row[k] === "number" ? +row[k].toPrecision(4) : row[k]) + "</td>").join("") + "</tr></table>";
+};
+</script></body></html>
+`